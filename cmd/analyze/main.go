@@ -0,0 +1,43 @@
+// Command analyze serves the engine over HTTP, answering POST /analyze with
+// the best move, score and expected line for a position. See package httpapi
+// for the request and response format.
+//
+//	go run ./cmd/analyze -addr localhost:8080
+//	curl -d '{"moves": "h8 h9 i9"}' localhost:8080/analyze
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"simple-gomoku/game"
+	"simple-gomoku/httpapi"
+	"simple-gomoku/internal/engineenv"
+)
+
+func main() {
+	addr := flag.String("addr", "localhost:8080", "address to listen on")
+	engine := flag.String("engine", "expert", "default engine: "+game.DifficultyNames())
+	maxTime := flag.Duration("max-time", 10*time.Second, "longest a request may think, 0 for no limit")
+	flag.Parse()
+	engineenv.Load("analyze")
+
+	difficulty, err := game.ParseDifficulty(*engine)
+	if err != nil {
+		fail(err)
+	}
+	server := httpapi.NewServer(difficulty, *maxTime)
+	log.Printf("analyze: listening on %s", *addr)
+	if err := http.ListenAndServe(*addr, server.Handler()); err != nil {
+		fail(err)
+	}
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "analyze:", err)
+	os.Exit(1)
+}
@@ -0,0 +1,160 @@
+// Command bench runs the search engine on a fixed suite of positions and
+// reports its speed, how long it took to settle on its move, and whether it
+// found the expected move, so performance work can be measured without
+// playing full games.
+//
+//	go run ./cmd/bench -depth 6
+//
+// Positions come from the tactical suite embedded in the game package, or
+// from a test suite file given with -suite.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"simple-gomoku/game"
+	"simple-gomoku/internal/engineenv"
+)
+
+// quietPositions are middlegames from engine games without a forced answer,
+// timed for speed only. They follow the embedded tactical suite.
+const quietPositions = `
+h8 h9 h10 i9 g9 g10 j8 f11 e12 e11 i11 j12 g8 f8; id quiet-a
+h8 h7 j6 i7 g7 i6 i5 j7 i9 j10; id quiet-b
+h8 h7 g6 i7 i6 g7 j7 f7 e7 k8 f6 h6 d8 c9 e9 k9; id quiet-c
+`
+
+// result is the outcome of one benchmark position
+type result struct {
+	move    string
+	correct bool
+	depth   int
+	nodes   int
+	elapsed time.Duration
+	settled time.Duration // When the search found the move it kept
+}
+
+func main() {
+	engine := flag.String("engine", "expert", "engine: expert or master")
+	depth := flag.Int("depth", 6, "search depth")
+	limit := flag.Duration("time", 0, "time limit per position, 0 for none")
+	suiteFile := flag.String("suite", "", "test suite file, see game.TestPosition; default the built-in suite")
+	flag.Parse()
+	engineenv.Load("bench")
+
+	difficulty := game.Expert
+	switch strings.ToLower(*engine) {
+	case "expert":
+	case "master":
+		difficulty = game.Master
+	default:
+		fail(fmt.Errorf("unknown engine %q", *engine))
+	}
+
+	suite, err := loadSuite(*suiteFile)
+	if err != nil {
+		fail(err)
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "Position\tExpected\tMove\tOK\tDepth\tNodes\tTime\tSettled\tkN/s\t")
+	var solved, tactical, totalNodes int
+	var totalTime time.Duration
+	for _, p := range suite {
+		board, err := p.Board()
+		if err != nil {
+			fail(fmt.Errorf("%s: %w", p.ID, err))
+		}
+		ai := game.NewAI(board.CurrentTurn, difficulty)
+		ai.SetBook(nil)
+		ai.SetDepth(*depth)
+		ai.SetTimeLimit(*limit)
+		r := run(ai, board, p)
+
+		ok := "-"
+		if p.Tactical() {
+			tactical++
+			ok = "no"
+			if r.correct {
+				solved++
+				ok = "yes"
+			}
+		}
+		totalNodes += r.nodes
+		totalTime += r.elapsed
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%d\t%d\t%s\t%s\t%.0f\t\n", p.ID, formatMoves(p.Best), r.move, ok,
+			r.depth, r.nodes, r.elapsed.Round(time.Millisecond), r.settled.Round(time.Millisecond), knps(r.nodes, r.elapsed))
+	}
+	tw.Flush()
+	fmt.Printf("\nSolved %d of %d, %d nodes in %s, %.0f kN/s\n",
+		solved, tactical, totalNodes, totalTime.Round(time.Millisecond), knps(totalNodes, totalTime))
+}
+
+// loadSuite reads the test suite file at path, or returns the built-in suite
+// when path is empty
+func loadSuite(path string) ([]game.TestPosition, error) {
+	if path != "" {
+		return game.LoadTestSuiteFile(path)
+	}
+	quiet, err := game.ParseTestSuite(strings.NewReader(quietPositions))
+	if err != nil {
+		return nil, err
+	}
+	return append(game.DefaultTestSuite(), quiet...), nil
+}
+
+// run searches one position and times it
+func run(ai *game.AI, board *game.Board, p game.TestPosition) result {
+	var reports []game.SearchInfo
+	ai.SetSearchReport(func(info game.SearchInfo) {
+		reports = append(reports, info)
+	})
+
+	start := time.Now()
+	row, col := ai.MakeMove(board)
+	r := result{elapsed: time.Since(start), move: game.FormatMove(row, col)}
+	r.correct = p.Solves([2]int{row, col})
+
+	// The search settled at the first depth from which every depth chose a
+	// correct move, or the move finally played when there is no right answer
+	solves := p.Solves
+	if !p.Tactical() {
+		solves = func(move [2]int) bool { return move == [2]int{row, col} }
+	}
+	r.settled = r.elapsed
+	for i := len(reports) - 1; i >= 0; i-- {
+		if !solves(reports[i].Move) {
+			break
+		}
+		r.settled = reports[i].Elapsed
+	}
+	if n := len(reports); n > 0 {
+		r.depth, r.nodes = reports[n-1].Depth, reports[n-1].Nodes
+	}
+	return r
+}
+
+func formatMoves(moves [][2]int) string {
+	names := make([]string, len(moves))
+	for i, move := range moves {
+		names[i] = game.FormatMove(move[0], move[1])
+	}
+	return strings.Join(names, " ")
+}
+
+func knps(nodes int, elapsed time.Duration) float64 {
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(nodes) / elapsed.Seconds() / 1000
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "bench:", err)
+	os.Exit(1)
+}
@@ -0,0 +1,224 @@
+// Command engine runs the engine without a window, reading GTP-style text
+// commands on standard input and answering on standard output, so it can be
+// scripted and tested:
+//
+//	newgame
+//	play B H8
+//	genmove W
+//	undo
+//	showboard
+//
+// Replies follow GTP: "= result" on success or "? message" on failure, each
+// followed by a blank line, with the command's number echoed if it had one.
+// Columns run A to O without skipping I, and rows 1 to 15 from the bottom.
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"simple-gomoku/game"
+	"simple-gomoku/internal/engineenv"
+)
+
+var commandNames = []string{
+	"protocol_version", "name", "version", "known_command", "list_commands", "quit",
+	"newgame", "clear_board", "boardsize", "play", "genmove", "undo", "showboard", "difficulty",
+}
+
+// errQuit ends the session after the reply to quit
+var errQuit = errors.New("quit")
+
+// session is the state of one engine session
+type session struct {
+	board      *game.Board
+	difficulty game.Difficulty
+}
+
+func main() {
+	engine := flag.String("engine", "expert", "engine: "+game.DifficultyNames())
+	flag.Parse()
+	engineenv.Load("engine")
+
+	difficulty, err := game.ParseDifficulty(*engine)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "engine:", err)
+		os.Exit(1)
+	}
+	s := &session{board: game.NewBoard(), difficulty: difficulty}
+
+	out := bufio.NewWriter(os.Stdout)
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		id := ""
+		if _, err := strconv.Atoi(fields[0]); err == nil {
+			id, fields = fields[0], fields[1:]
+			if len(fields) == 0 {
+				continue
+			}
+		}
+
+		result, err := s.run(strings.ToLower(fields[0]), fields[1:])
+		switch {
+		case err != nil && err != errQuit:
+			fmt.Fprintf(out, "?%s %s\n\n", id, err)
+		case result == "":
+			fmt.Fprintf(out, "=%s\n\n", id)
+		default:
+			fmt.Fprintf(out, "=%s %s\n\n", id, result)
+		}
+		out.Flush()
+		if err == errQuit {
+			return
+		}
+	}
+}
+
+// run executes one command and returns its result
+func (s *session) run(command string, args []string) (string, error) {
+	switch command {
+	case "protocol_version":
+		return "2", nil
+	case "name":
+		return "simple-gomoku", nil
+	case "version":
+		return "1.0", nil
+	case "known_command":
+		if len(args) != 1 {
+			return "", errors.New("syntax error")
+		}
+		for _, name := range commandNames {
+			if name == strings.ToLower(args[0]) {
+				return "true", nil
+			}
+		}
+		return "false", nil
+	case "list_commands":
+		return strings.Join(commandNames, "\n"), nil
+	case "quit":
+		return "", errQuit
+	case "newgame", "clear_board":
+		s.board = game.NewBoard()
+		return "", nil
+	case "boardsize":
+		if len(args) != 1 || args[0] != strconv.Itoa(game.BoardSize) {
+			return "", fmt.Errorf("unacceptable size, only %d is supported", game.BoardSize)
+		}
+		s.board = game.NewBoard()
+		return "", nil
+	case "play":
+		if len(args) != 2 {
+			return "", errors.New("syntax error")
+		}
+		if err := s.checkTurn(args[0]); err != nil {
+			return "", err
+		}
+		row, col, err := game.ParseMove(args[1])
+		if err != nil {
+			return "", err
+		}
+		if [2]int{row, col} == game.PassMove {
+			return "", s.board.Pass()
+		}
+		return "", s.board.PlaceStone(row, col)
+	case "genmove":
+		if len(args) != 1 {
+			return "", errors.New("syntax error")
+		}
+		if err := s.checkTurn(args[0]); err != nil {
+			return "", err
+		}
+		if s.board.IsGameFinished() {
+			return "", errors.New("the game is over")
+		}
+		row, col := game.NewAI(s.board.CurrentTurn, s.difficulty).MakeMove(s.board)
+		if err := s.board.PlaceStone(row, col); err != nil {
+			return "", err
+		}
+		return strings.ToUpper(game.FormatMove(row, col)), nil
+	case "undo":
+		return "", s.board.Undo()
+	case "showboard":
+		return "\n" + showBoard(s.board), nil
+	case "difficulty":
+		if len(args) != 1 {
+			return "", errors.New("syntax error")
+		}
+		difficulty, err := game.ParseDifficulty(args[0])
+		if err != nil {
+			return "", err
+		}
+		s.difficulty = difficulty
+		return "", nil
+	default:
+		return "", errors.New("unknown command")
+	}
+}
+
+// checkTurn reports an error unless color names the side to move
+func (s *session) checkTurn(color string) error {
+	var player game.Player
+	switch strings.ToLower(color) {
+	case "b", "black":
+		player = game.Black
+	case "w", "white":
+		player = game.White
+	default:
+		return errors.New("invalid color")
+	}
+	if player != s.board.CurrentTurn {
+		return errors.New("not that color's turn")
+	}
+	return nil
+}
+
+// showBoard draws the board as text, rows from 15 down to 1, with the last
+// move in brackets
+func showBoard(board *game.Board) string {
+	last := game.PassMove
+	if move, ok := board.LastMove(); ok {
+		last = move.Pos()
+	}
+	header := "  "
+	for col := 0; col < game.BoardSize; col++ {
+		header += fmt.Sprintf(" %c", 'A'+col)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(header + "\n")
+	for row := 0; row < game.BoardSize; row++ {
+		fmt.Fprintf(&sb, "%2d", game.BoardSize-row)
+		for col := 0; col < game.BoardSize; col++ {
+			cell := "."
+			switch board.Grid[row][col] {
+			case game.Black:
+				cell = "X"
+			case game.White:
+				cell = "O"
+			}
+			if [2]int{row, col} == last {
+				sb.WriteString("(" + cell)
+			} else if [2]int{row, col - 1} == last {
+				sb.WriteString(")" + cell)
+			} else {
+				sb.WriteString(" " + cell)
+			}
+		}
+		if last == [2]int{row, game.BoardSize - 1} {
+			sb.WriteString(")")
+		}
+		fmt.Fprintf(&sb, " %d\n", game.BoardSize-row)
+	}
+	sb.WriteString(header)
+	return sb.String()
+}
@@ -0,0 +1,35 @@
+// Command pbrain runs the engine as a Gomocup brain, speaking the pbrain
+// protocol on standard input and output so it can be entered into
+// Piskvork tournaments and played against other brains.
+//
+//	go build -o pbrain_simple-gomoku ./cmd/pbrain
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"simple-gomoku/game"
+	"simple-gomoku/internal/engineenv"
+	"simple-gomoku/pbrain"
+)
+
+func main() {
+	engine := flag.String("engine", "expert", "engine: "+game.DifficultyNames())
+	flag.Parse()
+	engineenv.Load("pbrain")
+
+	difficulty, err := game.ParseDifficulty(*engine)
+	if err != nil {
+		fail(err)
+	}
+	if err := pbrain.NewBrain(difficulty).Run(os.Stdin, os.Stdout); err != nil {
+		fail(err)
+	}
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "pbrain:", err)
+	os.Exit(1)
+}
@@ -0,0 +1,404 @@
+// Command protocol-test checks that an engine speaks the text protocol of
+// cmd/engine correctly, so third-party engines can be scripted the same way
+// as ours. It runs the engine once per check and reports every violation.
+// Like GTP it lets play and genmove name either color, so engines are not
+// asked to refuse a move out of turn as ours does:
+//
+//	go build -o gomoku-engine ./cmd/engine
+//	go run ./cmd/protocol-test ./gomoku-engine -engine hard
+//
+// Arguments after the engine binary are passed to it. The exit status is 1
+// if any check failed.
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"simple-gomoku/game"
+)
+
+// requiredCommands are the commands every engine must know
+var requiredCommands = []string{
+	"protocol_version", "name", "version", "known_command", "list_commands", "quit",
+	"clear_board", "boardsize", "play", "genmove", "undo",
+}
+
+// check is one conformance check, run against a freshly started engine
+type check struct {
+	name string
+	run  func(e *engine) error
+}
+
+var checks = []check{
+	{"protocol_version is a number", func(e *engine) error {
+		result, err := e.succeed("protocol_version")
+		if err != nil {
+			return err
+		}
+		if _, err := strconv.Atoi(result); err != nil {
+			return fmt.Errorf("protocol_version replied %q", result)
+		}
+		return nil
+	}},
+	{"name is not empty", func(e *engine) error {
+		result, err := e.succeed("name")
+		if err == nil && result == "" {
+			err = errors.New("name replied nothing")
+		}
+		return err
+	}},
+	{"list_commands has the required commands", func(e *engine) error {
+		result, err := e.succeed("list_commands")
+		if err != nil {
+			return err
+		}
+		listed := map[string]bool{}
+		for _, name := range strings.Fields(result) {
+			listed[strings.ToLower(name)] = true
+		}
+		var missing []string
+		for _, name := range requiredCommands {
+			if !listed[name] {
+				missing = append(missing, name)
+			}
+		}
+		if len(missing) > 0 {
+			return fmt.Errorf("list_commands leaves out %s", strings.Join(missing, ", "))
+		}
+		return nil
+	}},
+	{"known_command tells known from unknown", func(e *engine) error {
+		for command, want := range map[string]string{"play": "true", "no_such_command": "false"} {
+			result, err := e.succeed("known_command " + command)
+			if err != nil {
+				return err
+			}
+			if result != want {
+				return fmt.Errorf("known_command %s replied %q, want %q", command, result, want)
+			}
+		}
+		return nil
+	}},
+	{"unknown commands fail", func(e *engine) error {
+		return e.fail("no_such_command")
+	}},
+	{"command numbers are echoed", func(e *engine) error {
+		r, err := e.ask("42", "name")
+		if err != nil {
+			return err
+		}
+		if r.id != "42" {
+			return fmt.Errorf("reply to command 42 was numbered %q", r.id)
+		}
+		return nil
+	}},
+	{"boardsize 15 is accepted", func(e *engine) error {
+		_, err := e.succeed(fmt.Sprintf("boardsize %d", game.BoardSize))
+		return err
+	}},
+	{"play alternates colors", func(e *engine) error {
+		return e.play("clear_board", "play B H8", "play W H9", "play black J9", "play white G9")
+	}},
+	{"play on an occupied point fails", func(e *engine) error {
+		if err := e.play("clear_board", "play B H8"); err != nil {
+			return err
+		}
+		return e.fail("play W H8")
+	}},
+	{"play off the board fails", func(e *engine) error {
+		if err := e.play("clear_board"); err != nil {
+			return err
+		}
+		// Only points off the board whether or not the letters skip I, as
+		// they do in GTP but not in our notation
+		for _, move := range []string{"H16", "H0", "Z99", "8H"} {
+			if err := e.fail("play B " + move); err != nil {
+				return err
+			}
+		}
+		return nil
+	}},
+	{"genmove plays a legal move", func(e *engine) error {
+		if err := e.play("clear_board", "play B H8"); err != nil {
+			return err
+		}
+		result, err := e.succeed("genmove W")
+		if err != nil {
+			return err
+		}
+		row, col, err := game.ParseMove(result)
+		if err != nil || [2]int{row, col} == game.PassMove {
+			return fmt.Errorf("genmove replied %q, not a point on the board", result)
+		}
+		if strings.EqualFold(result, "H8") {
+			return errors.New("genmove played on the occupied point H8")
+		}
+		// The engine's move is on its board, so the point is taken
+		return e.fail("play B " + result)
+	}},
+	{"undo takes back the last move", func(e *engine) error {
+		if err := e.play("clear_board", "play B H8", "play W H9", "undo"); err != nil {
+			return err
+		}
+		if err := e.play("play W H9", "undo", "undo", "play B H8"); err != nil {
+			return err
+		}
+		return nil
+	}},
+	{"undo with no moves fails", func(e *engine) error {
+		if err := e.play("clear_board"); err != nil {
+			return err
+		}
+		return e.fail("undo")
+	}},
+	{"no moves after five in a row", func(e *engine) error {
+		moves := []string{"clear_board"}
+		for i := 1; i <= 5; i++ {
+			moves = append(moves, fmt.Sprintf("play B H%d", i))
+			if i < 5 {
+				moves = append(moves, fmt.Sprintf("play W A%d", i))
+			}
+		}
+		if err := e.play(moves...); err != nil {
+			return err
+		}
+		if err := e.fail("play W O15"); err != nil {
+			return err
+		}
+		return e.fail("genmove W")
+	}},
+	{"clear_board starts a new game", func(e *engine) error {
+		return e.play("clear_board", "play B H8", "clear_board", "play B H8")
+	}},
+	{"quit replies and exits", func(e *engine) error {
+		if _, err := e.succeed("quit"); err != nil {
+			return err
+		}
+		if !e.exited(e.timeout) {
+			return errors.New("the engine kept running after quit")
+		}
+		return nil
+	}},
+}
+
+func main() {
+	timeout := flag.Duration("timeout", 30*time.Second, "longest wait for a reply")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: protocol-test [-timeout d] engine-binary [engine args...]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() < 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	failed := 0
+	for _, c := range checks {
+		err := runCheck(c, flag.Args(), *timeout)
+		if err != nil {
+			failed++
+			fmt.Printf("FAIL  %s: %v\n", c.name, err)
+		} else {
+			fmt.Printf("ok    %s\n", c.name)
+		}
+	}
+	if failed > 0 {
+		fmt.Printf("%d of %d checks failed\n", failed, len(checks))
+		os.Exit(1)
+	}
+	fmt.Printf("all %d checks passed\n", len(checks))
+}
+
+// runCheck starts the engine, runs c against it and stops it again
+func runCheck(c check, command []string, timeout time.Duration) error {
+	e, err := startEngine(command, timeout)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "protocol-test: %v\n", err)
+		os.Exit(1)
+	}
+	defer e.close()
+	return c.run(e)
+}
+
+// engine is the engine under test, run as a child process
+type engine struct {
+	cmd     *exec.Cmd
+	stdin   io.WriteCloser
+	lines   chan string // Lines of the engine's output, closed when it ends
+	timeout time.Duration
+	next    int // Number of the next command sent
+}
+
+func startEngine(command []string, timeout time.Duration) (*engine, error) {
+	cmd := exec.Command(command[0], command[1:]...)
+	cmd.Stderr = io.Discard
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	e := &engine{
+		cmd:     cmd,
+		stdin:   stdin,
+		lines:   make(chan string, 64),
+		timeout: timeout,
+	}
+	go func() {
+		scanner := bufio.NewScanner(stdout)
+		for scanner.Scan() {
+			e.lines <- strings.TrimRight(scanner.Text(), "\r")
+		}
+		close(e.lines)
+	}()
+	return e, nil
+}
+
+// reply is an engine's answer to one command
+type reply struct {
+	ok     bool   // "=" rather than "?"
+	id     string // Command number echoed, if any
+	result string // Text after the first space, with any further lines
+}
+
+// ask sends command, numbered id unless id is empty, and reads the reply,
+// checking its framing
+func (e *engine) ask(id, command string) (reply, error) {
+	line := command
+	if id != "" {
+		line = id + " " + command
+	}
+	if _, err := io.WriteString(e.stdin, line+"\n"); err != nil {
+		return reply{}, fmt.Errorf("%s: the engine stopped reading: %w", command, err)
+	}
+
+	var r reply
+	var lines []string
+	timer := time.NewTimer(e.timeout)
+	defer timer.Stop()
+	for {
+		select {
+		case line, open := <-e.lines:
+			if !open {
+				return r, fmt.Errorf("%s: the engine exited before replying", command)
+			}
+			if len(lines) == 0 {
+				if line == "" {
+					continue // Blank lines between replies are allowed
+				}
+				status, rest := line[0], line[1:]
+				if status != '=' && status != '?' {
+					return r, fmt.Errorf("%s: reply %q does not start with = or ?", command, line)
+				}
+				r.ok = status == '='
+				r.id, rest, _ = strings.Cut(rest, " ")
+				if _, err := strconv.Atoi(r.id); r.id != "" && err != nil {
+					return r, fmt.Errorf("%s: reply %q has no space after its status", command, line)
+				}
+				lines = append(lines, strings.TrimSpace(rest))
+				continue
+			}
+			if line == "" {
+				r.result = strings.Join(lines, "\n")
+				return r, nil
+			}
+			lines = append(lines, line)
+		case <-timer.C:
+			if len(lines) > 0 {
+				return r, fmt.Errorf("%s: no blank line ended the reply within %v", command, e.timeout)
+			}
+			return r, fmt.Errorf("%s: no reply within %v", command, e.timeout)
+		}
+	}
+}
+
+// succeed sends command, numbered, and returns its result, which must be a
+// success
+func (e *engine) succeed(command string) (string, error) {
+	r, err := e.numbered(command)
+	if err != nil {
+		return "", err
+	}
+	if !r.ok {
+		return "", fmt.Errorf("%s failed: %s", command, r.result)
+	}
+	return r.result, nil
+}
+
+// fail sends command, numbered, which must fail
+func (e *engine) fail(command string) error {
+	r, err := e.numbered(command)
+	if err != nil {
+		return err
+	}
+	if r.ok {
+		return fmt.Errorf("%s succeeded but should fail", command)
+	}
+	return nil
+}
+
+// play sends commands that must all succeed
+func (e *engine) play(commands ...string) error {
+	for _, command := range commands {
+		if _, err := e.succeed(command); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// numbered sends command with the next command number and checks that the
+// reply echoes it
+func (e *engine) numbered(command string) (reply, error) {
+	e.next++
+	id := strconv.Itoa(e.next)
+	r, err := e.ask(id, command)
+	if err == nil && r.id != id {
+		err = fmt.Errorf("%s: reply to command %s was numbered %q", command, id, r.id)
+	}
+	return r, err
+}
+
+// exited reports whether the engine's output ends within timeout, skipping
+// anything it still writes
+func (e *engine) exited(timeout time.Duration) bool {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	for {
+		select {
+		case _, open := <-e.lines:
+			if !open {
+				return true
+			}
+		case <-timer.C:
+			return false
+		}
+	}
+}
+
+// close ends the engine, killing it if quit does not stop it
+func (e *engine) close() {
+	io.WriteString(e.stdin, "quit\n")
+	e.stdin.Close()
+	if !e.exited(time.Second) {
+		e.cmd.Process.Kill()
+		e.exited(time.Second)
+	}
+	e.cmd.Wait()
+}
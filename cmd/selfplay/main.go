@@ -0,0 +1,79 @@
+// Command selfplay plays engine-versus-engine games without a window and
+// writes every position with the game's outcome as JSON lines, for tuning
+// the evaluation or training a network. See game.SelfPlayRecord for the
+// format.
+//
+//	go run ./cmd/selfplay -games 100 -black hard -white expert -seed 1 -o games.jsonl
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"simple-gomoku/game"
+	"simple-gomoku/internal/engineenv"
+)
+
+func main() {
+	games := flag.Int("games", 10, "number of games to play")
+	black := flag.String("black", "hard", "Black engine: "+game.DifficultyNames())
+	white := flag.String("white", "hard", "White engine, as for -black")
+	depth := flag.Int("depth", 0, "search depth for expert and master, 0 for their default")
+	randomPlies := flag.Int("random-plies", 2, "random opening moves near the center")
+	workers := flag.Int("workers", 0, "games played at once, 0 for one per CPU")
+	seed := flag.Int64("seed", 1, "random seed; the same seed plays the same games")
+	output := flag.String("o", "", "output file, standard output if empty")
+	flag.Parse()
+	engineenv.Load("selfplay")
+
+	cfg := game.SelfPlayConfig{
+		Games:       *games,
+		Depth:       *depth,
+		RandomPlies: *randomPlies,
+		Workers:     *workers,
+		Seed:        *seed,
+	}
+	var err error
+	if cfg.Black, err = game.ParseDifficulty(*black); err != nil {
+		fail(err)
+	}
+	if cfg.White, err = game.ParseDifficulty(*white); err != nil {
+		fail(err)
+	}
+
+	out := os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			fail(err)
+		}
+		defer f.Close()
+		out = f
+	}
+	w := bufio.NewWriter(out)
+	defer w.Flush()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	wins := map[game.Player]int{}
+	err = game.SelfPlay(ctx, cfg, func(g game.SelfPlayGame) error {
+		wins[g.Winner]++
+		fmt.Fprintf(os.Stderr, "game %d: %d moves\n", g.Index+1, len(g.Moves))
+		return game.WriteSelfPlayRecords(w, g)
+	})
+	fmt.Fprintf(os.Stderr, "Black %d, White %d, drawn %d\n", wins[game.Black], wins[game.White], wins[game.Empty])
+	if err != nil {
+		w.Flush()
+		fail(err)
+	}
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "selfplay:", err)
+	os.Exit(1)
+}
@@ -0,0 +1,87 @@
+// Command tournament plays engines against each other, every opening of the
+// book with each color, and prints a results table with Elo estimates.
+//
+//	go run ./cmd/tournament -engines easy,medium,hard -rounds 1 -seed 1
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+
+	"simple-gomoku/game"
+	"simple-gomoku/game/tournament"
+	"simple-gomoku/internal/engineenv"
+)
+
+func main() {
+	engines := flag.String("engines", "easy,medium,hard", "comma-separated engines: "+game.DifficultyNames()+", or a ladder level such as club")
+	format := flag.String("format", "roundrobin", "roundrobin, or gauntlet for the first engine against the others")
+	depth := flag.Int("depth", 0, "search depth for expert and master, 0 for their default with a time limit")
+	rounds := flag.Int("rounds", 1, "times each pairing plays the openings")
+	workers := flag.Int("workers", 0, "games played at once, 0 for one per CPU")
+	seed := flag.Int64("seed", 1, "random seed")
+	flag.Parse()
+	engineenv.Load("tournament")
+
+	cfg := tournament.Config{Rounds: *rounds, Workers: *workers, Seed: *seed}
+	switch strings.ToLower(*format) {
+	case "roundrobin":
+		cfg.Format = tournament.RoundRobin
+	case "gauntlet":
+		cfg.Format = tournament.Gauntlet
+	default:
+		fail(fmt.Errorf("unknown format %q", *format))
+	}
+	for _, name := range strings.Split(*engines, ",") {
+		engine, err := parseEngine(strings.TrimSpace(name), *depth)
+		if err != nil {
+			fail(err)
+		}
+		cfg.Engines = append(cfg.Engines, engine)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	played := 0
+	result, err := tournament.Run(ctx, cfg, func(g tournament.Game) {
+		played++
+		winner := "drawn"
+		switch g.Winner {
+		case game.Black:
+			winner = cfg.Engines[g.Black].Name + " wins"
+		case game.White:
+			winner = cfg.Engines[g.White].Name + " wins"
+		}
+		fmt.Fprintf(os.Stderr, "game %d: %s vs %s, %s in %d moves\n",
+			played, cfg.Engines[g.Black].Name, cfg.Engines[g.White].Name, winner, len(g.Moves))
+	})
+	if err != nil {
+		fail(err)
+	}
+	if err := result.WriteTable(os.Stdout); err != nil {
+		fail(err)
+	}
+}
+
+// parseEngine returns the entrant for a difficulty or ladder level name
+func parseEngine(name string, depth int) (tournament.Engine, error) {
+	if difficulty, err := game.ParseDifficulty(name); err == nil {
+		return tournament.DifficultyEngine(difficulty.String(), difficulty, depth), nil
+	}
+	for _, level := range game.Ladder {
+		if strings.EqualFold(level.Name, name) {
+			return tournament.LadderEngine(level), nil
+		}
+	}
+	return tournament.Engine{}, fmt.Errorf("unknown engine %q", name)
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "tournament:", err)
+	os.Exit(1)
+}
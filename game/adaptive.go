@@ -0,0 +1,140 @@
+package game
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+)
+
+const (
+	// AdaptiveWindow is the number of recent games the strength controller
+	// looks at when deciding whether to change level
+	AdaptiveWindow = 5
+
+	adaptiveMinGames    = 3   // Games needed at a level before it can change
+	adaptivePromoteRate = 0.6 // Win rate above which the engine gets stronger
+	adaptiveDemoteRate  = 0.4 // Win rate below which the engine gets weaker
+)
+
+// Record holds a player's recent results against the adaptive engine and
+// their rating from games against rated engines
+type Record struct {
+	Level   int     `json:"level"`   // Index into Ladder of the current engine strength
+	Results []bool  `json:"results"` // Results at the current level, oldest first, true for a win
+	Games   int     `json:"games"`   // Total games played
+	Wins    int     `json:"wins"`    // Total games won
+	Rating  *Rating `json:"rating,omitempty"`
+}
+
+// AddResult records a game and moves the engine strength up or down when the
+// player's recent win rate calls for it. It returns the change in level.
+func (r *Record) AddResult(won bool) int {
+	r.Games++
+	if won {
+		r.Wins++
+	}
+	r.Results = append(r.Results, won)
+	if len(r.Results) > AdaptiveWindow {
+		r.Results = r.Results[len(r.Results)-AdaptiveWindow:]
+	}
+	if len(r.Results) < adaptiveMinGames {
+		return 0
+	}
+
+	wins := 0
+	for _, result := range r.Results {
+		if result {
+			wins++
+		}
+	}
+	rate := float64(wins) / float64(len(r.Results))
+
+	change := 0
+	if rate > adaptivePromoteRate && r.Level < len(Ladder)-1 {
+		change = 1
+	} else if rate < adaptiveDemoteRate && r.Level > 0 {
+		change = -1
+	}
+	if change != 0 {
+		r.Level += change
+		r.Results = nil
+	}
+	return change
+}
+
+// PlayerRating returns the player's rating as of now
+func (r *Record) PlayerRating(now time.Time) Rating {
+	if r.Rating == nil {
+		return NewRating()
+	}
+	return r.Rating.At(now)
+}
+
+// AddRatedResult updates the player's rating after a game against an engine
+// rated elo. score is 1 for a win, 0.5 for a draw and 0 for a loss.
+func (r *Record) AddRatedResult(elo int, score float64, now time.Time) Rating {
+	rating := r.PlayerRating(now).Update(float64(elo), EngineRD, score, now)
+	r.Rating = &rating
+	return rating
+}
+
+// NewAI creates an AI at the record's current strength
+func (r *Record) NewAI(player Player) *AI {
+	return Ladder[r.CurrentLevel()].NewAI(player)
+}
+
+// CurrentLevel returns the record's level, clamped to the ladder
+func (r *Record) CurrentLevel() int {
+	return max(0, min(r.Level, len(Ladder)-1))
+}
+
+// recordStoreMigrations upgrade saved record stores, see Migrate
+var recordStoreMigrations = []Migration{
+	// 0 to 1: the version field was added
+	func(map[string]any) error { return nil },
+}
+
+// RecordStore keeps adaptive records for each player by name
+type RecordStore struct {
+	Version int                `json:"version"`
+	Players map[string]*Record `json:"players"`
+}
+
+func NewRecordStore() *RecordStore {
+	return &RecordStore{Version: len(recordStoreMigrations), Players: make(map[string]*Record)}
+}
+
+// LoadRecordStore reads a record store saved with Save by this or an older
+// release
+func LoadRecordStore(r io.Reader) (*RecordStore, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if data, err = Migrate(data, recordStoreMigrations); err != nil {
+		return nil, err
+	}
+	store := NewRecordStore()
+	if err := json.Unmarshal(data, store); err != nil {
+		return nil, err
+	}
+	if store.Players == nil {
+		store.Players = make(map[string]*Record)
+	}
+	return store, nil
+}
+
+// Save writes the record store as JSON
+func (s *RecordStore) Save(w io.Writer) error {
+	return json.NewEncoder(w).Encode(s)
+}
+
+// Record returns the record for a player, creating it if needed
+func (s *RecordStore) Record(player string) *Record {
+	record, ok := s.Players[player]
+	if !ok {
+		record = &Record{}
+		s.Players[player] = record
+	}
+	return record
+}
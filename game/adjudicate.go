@@ -0,0 +1,124 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+)
+
+const (
+	AdjudicationDepth     = MasterDepth + 2
+	AdjudicationTimeLimit = 30 * time.Second
+
+	adjudicationScale       = 400.0 // Score at which the favoured side is given about 73%
+	adjudicationForcedPlies = 10    // Forced and book moves followed before searching
+)
+
+// Adjudication is the engine's probable result of an unfinished game
+type Adjudication struct {
+	Winner     Player  // Side the engine favours, Empty when neither is
+	Confidence float64 // Chance the engine gives Winner, from 0.5 to 1
+	Score      int     // Score of the position from Black's perspective
+	Depth      int     // Plies searched, 0 when no search was needed
+}
+
+// Adjudicate searches the final position of an unfinished game, such as an
+// abandoned or imported one, deeper than the Master engine plays, and
+// returns the result it expects. Forced replies and book moves are played
+// out first, since they come without a score. A forced win is certain;
+// otherwise the score sets the confidence.
+func Adjudicate(ctx context.Context, board *Board) (Adjudication, error) {
+	if board.IsGameFinished() {
+		return Adjudication{}, errors.New("the game is already over")
+	}
+	if board.rules.ThreePlayers {
+		return Adjudication{}, errors.New("three-player games cannot be adjudicated")
+	}
+	ai := NewAI(board.CurrentTurn, Master)
+	ai.SetBook(nil)
+	ai.SetDepth(AdjudicationDepth)
+	ai.SetTimeLimit(AdjudicationTimeLimit)
+
+	position := board.Clone()
+	for plies := 0; ; plies++ {
+		switch {
+		case position.IsGameFinished():
+			return adjudicateFinished(position), nil
+		case ai.findWinningMove(position, position.CurrentTurn)[0] >= 0:
+			return newAdjudication(position.CurrentTurn, WinScore, 0), nil
+		case plies == adjudicationForcedPlies:
+			return newAdjudication(position.CurrentTurn, position.eval.Score()*sign(position.CurrentTurn), 0), nil
+		}
+
+		analysis, err := ai.Analyze(ctx, position)
+		if err != nil {
+			return Adjudication{}, err
+		}
+		if analysis.Depth > 0 {
+			return newAdjudication(position.CurrentTurn, analysis.Score, analysis.Depth), nil
+		}
+		if analysis.Move == PassMove {
+			err = position.Pass()
+		} else {
+			err = position.PlaceStone(analysis.Move[0], analysis.Move[1])
+		}
+		if err != nil {
+			return Adjudication{}, err
+		}
+	}
+}
+
+// newAdjudication turns score, for player to move, into a probable result
+func newAdjudication(player Player, score, depth int) Adjudication {
+	a := Adjudication{Score: score * sign(player), Depth: depth, Confidence: 0.5}
+	switch {
+	case a.Score > 0:
+		a.Winner = Black
+	case a.Score < 0:
+		a.Winner = White
+	default:
+		return a
+	}
+	if abs(a.Score) >= WinScore {
+		a.Confidence = 1
+	} else {
+		a.Confidence = 1 / (1 + math.Exp(-float64(abs(a.Score))/adjudicationScale))
+	}
+	return a
+}
+
+// adjudicateFinished returns the certain result of a game played out while
+// following forced moves
+func adjudicateFinished(board *Board) Adjudication {
+	switch board.Result().Winner {
+	case Black:
+		return newAdjudication(Black, WinScore, 0)
+	case White:
+		return newAdjudication(White, WinScore, 0)
+	}
+	return Adjudication{Confidence: 1}
+}
+
+// sign returns 1 for Black and -1 for White, turning scores for player into
+// scores from Black's perspective
+func sign(player Player) int {
+	if player == White {
+		return -1
+	}
+	return 1
+}
+
+// EndByAdjudication ends the game with the result of a, marked as adjudicated
+func (b *Board) EndByAdjudication(a Adjudication) error {
+	if b.result.Finished() {
+		return errors.New("game is already finished")
+	}
+	b.result = Result{
+		Winner:     a.Winner,
+		Draw:       a.Winner == Empty,
+		Reason:     ReasonAdjudicated,
+		Confidence: a.Confidence,
+	}
+	return nil
+}
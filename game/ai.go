@@ -1,8 +1,16 @@
 package game
 
 import (
+	"context"
+	"fmt"
 	"math"
+	"math/bits"
 	"math/rand"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 )
 
 type Difficulty int
@@ -11,193 +19,380 @@ const (
 	Easy Difficulty = iota
 	Medium
 	Hard
+	MonteCarlo
+	Expert
+	Master
 )
 
+// Difficulties lists every difficulty, in the order they are declared
+var Difficulties = []Difficulty{Easy, Medium, Hard, MonteCarlo, Expert, Master}
+
+// difficultyNames are the names of the difficulties in command line flags
+// and requests
+var difficultyNames = [...]string{
+	Easy:       "easy",
+	Medium:     "medium",
+	Hard:       "hard",
+	MonteCarlo: "montecarlo",
+	Expert:     "expert",
+	Master:     "master",
+}
+
+// String returns the name ParseDifficulty reads, such as "montecarlo"
+func (d Difficulty) String() string {
+	if d < 0 || int(d) >= len(difficultyNames) {
+		return fmt.Sprintf("Difficulty(%d)", int(d))
+	}
+	return difficultyNames[d]
+}
+
+// ParseDifficulty returns the difficulty named name, in any case
+func ParseDifficulty(name string) (Difficulty, error) {
+	for _, d := range Difficulties {
+		if strings.EqualFold(name, d.String()) {
+			return d, nil
+		}
+	}
+	return Easy, fmt.Errorf("unknown engine %q", name)
+}
+
+// DifficultyNames lists the difficulties for help text: "easy, medium,
+// hard, montecarlo, expert or master"
+func DifficultyNames() string {
+	names := make([]string, len(Difficulties))
+	for i, d := range Difficulties {
+		names[i] = d.String()
+	}
+	last := len(names) - 1
+	return strings.Join(names[:last], ", ") + " or " + names[last]
+}
+
 type AI struct {
 	player     Player
 	difficulty Difficulty
+	playouts   int           // Number of playouts per move in MonteCarlo mode
+	book       *Book         // Opening book, nil to disable
+	workers    int           // Goroutines used to evaluate candidate positions
+	depth      int           // Search depth in plies in Expert and Master modes
+	timeLimit  time.Duration // Thinking time per move in Expert and Master modes
+	errorRate  float64       // Chance of a mistake in Easy, Expert and Master modes
+	evaluator  Evaluator     // Position evaluation in Expert and Master modes, nil for Evaluate
+	rng        *rand.Rand    // Random source, nil for the shared one
+	elo        int           // Approximate rating, 0 if unknown
+	weights    Weights       // Move scores in Easy, Medium and Hard modes
+	variety    float64       // How far opening moves stray from the best, see SetVariety
+
+	searchReport func(SearchInfo) // Called after each depth of the Expert and Master search
 }
 
 func NewAI(player Player, difficulty Difficulty) *AI {
-	return &AI{
+	ai := &AI{
 		player:     player,
 		difficulty: difficulty,
+		playouts:   DefaultPlayouts,
+		book:       DefaultBook(),
+		workers:    runtime.NumCPU(),
+		depth:      ExpertDepth,
+		timeLimit:  ExpertTimeLimit,
+		evaluator:  defaultEvaluator,
+		weights:    defaultWeights,
+	}
+	switch difficulty {
+	case Easy:
+		ai.errorRate = EasyErrorRate
+	case Master:
+		ai.depth = MasterDepth
+		ai.timeLimit = MasterTimeLimit
 	}
+	ai.elo = ai.ladderElo()
+	return ai
 }
 
-func (ai *AI) MakeMove(board *Board) (int, int) {
-	switch ai.difficulty {
-	case Easy:
-		return ai.makeEasyMove(board)
-	case Medium:
-		return ai.makeMediumMove(board)
-	case Hard:
-		return ai.makeHardMove(board)
-	default:
-		return ai.makeEasyMove(board)
+// SetPlayouts sets the number of playouts the MonteCarlo engine runs per move
+func (ai *AI) SetPlayouts(playouts int) {
+	if playouts < 1 {
+		playouts = 1
 	}
+	ai.playouts = playouts
 }
 
-// Easy mode: Prevents opponent's winning moves and three-in-a-row threats, prefers valuable positions
-func (ai *AI) makeEasyMove(board *Board) (int, int) {
-	// 1. Check if AI can win
-	if move := ai.findWinningMove(board, ai.player); move[0] >= 0 {
-		return move[0], move[1]
+// SetWorkers sets the number of goroutines used to evaluate positions
+func (ai *AI) SetWorkers(workers int) {
+	if workers < 1 {
+		workers = 1
 	}
+	ai.workers = workers
+}
 
-	// 2. Check if need to block opponent's winning move
-	if move := ai.findWinningMove(board, ai.getOpponent()); move[0] >= 0 {
-		return move[0], move[1]
+// SetSeed gives the AI its own random source, so the same positions get the
+// same moves. A seeded AI must not think about two positions at once.
+func (ai *AI) SetSeed(seed int64) {
+	ai.rng = rand.New(rand.NewSource(seed))
+}
+
+// intn returns a random number in [0, n) from the AI's random source
+func (ai *AI) intn(n int) int {
+	if ai.rng != nil {
+		return ai.rng.Intn(n)
 	}
+	return rand.Intn(n)
+}
 
-	// 3. Check opponent's three-in-a-row threats
-	if move := ai.findThreatsMove(board); move[0] >= 0 {
-		return move[0], move[1]
+// float64 returns a random number in [0, 1) from the AI's random source
+func (ai *AI) float64() float64 {
+	if ai.rng != nil {
+		return ai.rng.Float64()
 	}
+	return rand.Float64()
+}
 
-	// 4. Find the range of existing stones
-	minRow, maxRow := BoardSize-1, 0
-	minCol, maxCol := BoardSize-1, 0
-	hasStones := false
+// SetBook sets the opening book used for the first moves, nil disables it
+func (ai *AI) SetBook(book *Book) {
+	ai.book = book
+}
 
-	for i := 0; i < BoardSize; i++ {
-		for j := 0; j < BoardSize; j++ {
-			if board.Grid[i][j] != Empty {
-				hasStones = true
-				if i < minRow {
-					minRow = i
-				}
-				if i > maxRow {
-					maxRow = i
-				}
-				if j < minCol {
-					minCol = j
-				}
-				if j > maxCol {
-					maxCol = j
-				}
+func (ai *AI) MakeMove(board *Board) (int, int) {
+	row, col, _ := ai.MakeMoveCtx(context.Background(), board)
+	return row, col
+}
+
+// MakeMoveCtx is like MakeMove but stops thinking and returns the context's
+// error once ctx is cancelled
+func (ai *AI) MakeMoveCtx(ctx context.Context, board *Board) (int, int, error) {
+	if err := ctx.Err(); err != nil {
+		return -1, -1, err
+	}
+
+	// Play instantly from the opening book when possible
+	if ai.book != nil && len(board.MoveHistory) < BookPlies && bookGame(board) {
+		if row, col, ok := ai.book.lookup(board, ai.intn); ok {
+			return row, col, nil
+		}
+	}
+
+	var row, col int
+	switch {
+	case board.rules.ThreePlayers:
+		row, col = ai.makeThreePlayerMove(ctx, board)
+	case ai.difficulty == Easy:
+		row, col = ai.makeEasyMove(board)
+	case ai.difficulty == Medium:
+		row, col = ai.makeMediumMove(ctx, board)
+	case ai.difficulty == Hard:
+		row, col = ai.makeHardMove(ctx, board)
+	case ai.difficulty == MonteCarlo:
+		row, col = ai.makeMCTSMove(ctx, board)
+	case ai.difficulty == Expert, ai.difficulty == Master:
+		row, col = ai.makeSearchMove(ctx, board)
+	default:
+		row, col = ai.makeEasyMove(board)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return -1, -1, err
+	}
+	if move, ok := ai.varietyMove(board, [2]int{row, col}); ok {
+		row, col = move[0], move[1]
+	}
+	return row, col, nil
+}
+
+// WinScore is the evaluation of a won position
+const WinScore = 100000
+
+// windowValues[length] scores a line of length cells by the number of stones
+// of a single player in it, by how many stones short of a win it is:
+// 1, 10, 100 and 1000 for four, three, two and one short
+var windowValues = func() (values [MaxWinLength + 1][MaxWinLength + 1]int) {
+	short := []int{WinScore, 1000, 100, 10, 1}
+	for length := range values {
+		for n := 1; n <= length; n++ {
+			if length-n < len(short) {
+				values[length][n] = short[length-n]
 			}
 		}
 	}
+	return values
+}()
+
+// Evaluate returns a score for the position from Black's perspective: positive
+// when Black is better, negative when White is, and ±WinScore once a player
+// has won. Every line of winning length holding stones of only one player
+// counts for that player; the board keeps these counts as stones are placed.
+//
+// The score is for two-player games. In a three-player game it weighs only
+// Black's lines against White's, leaving Red's out, and a win for Red scores
+// -WinScore as a loss for Black.
+func (ai *AI) Evaluate(board *Board) int {
+	if board.IsDraw() {
+		return 0
+	}
+	switch board.Result().Winner {
+	case Black:
+		return WinScore
+	case White, Red:
+		return -WinScore
+	}
+	return board.eval.Score()
+}
 
-	// If no stones on board, play near center
-	if !hasStones {
-		center := BoardSize / 2
-		return center, center
-	}
-
-	// Expand search range, but avoid edges
-	minRow = max(2, minRow-2)
-	maxRow = min(BoardSize-3, maxRow+2)
-	minCol = max(2, minCol-2)
-	maxCol = min(BoardSize-3, maxCol+2)
-
-	// 5. Collect possible moves within valid range
-	type moveWithWeight struct {
-		row    int
-		col    int
-		weight int
-	}
-	var moves []moveWithWeight
-
-	// Get last move position
-	lastRow, lastCol := BoardSize/2, BoardSize/2
-	if len(board.MoveHistory) > 0 {
-		lastMove := board.MoveHistory[len(board.MoveHistory)-1]
-		lastRow, lastCol = lastMove[0], lastMove[1]
-	}
-
-	// Check all empty positions within valid range
-	for i := minRow; i <= maxRow; i++ {
-		for j := minCol; j <= maxCol; j++ {
-			if board.Grid[i][j] == Empty {
-				weight := 100
-
-				// Evaluate position value
-				weight += ai.evaluatePosition(board, i, j)
-
-				// Adjust weight based on distance to last move
-				dist := math.Abs(float64(i-lastRow)) + math.Abs(float64(j-lastCol))
-				if dist <= 2 {
-					weight += 100 // Very close to last move
-				} else if dist <= 4 {
-					weight += 50 // Relatively close to last move
-				}
+// Suggestion is a move suggested for the side to move
+type Suggestion struct {
+	Row   int
+	Col   int
+	Score int // Value of the move for the side to move, higher is better
+}
 
-				// Adjust weight based on distance to center
-				centerDist := math.Abs(float64(i-BoardSize/2)) + math.Abs(float64(j-BoardSize/2))
-				if centerDist <= 2 {
-					weight += 150 // Close to center
-				} else if centerDist <= 4 {
-					weight += 80 // Relatively close to center
-				}
+// SuggestMove suggests a move for the player whose turn it is, which need not
+// be the AI's own color, using the AI's difficulty. Suggestions are always
+// the best move, whatever the variety setting.
+func (ai *AI) SuggestMove(board *Board) Suggestion {
+	suggestion, _ := ai.SuggestMoveCtx(context.Background(), board)
+	return suggestion
+}
 
-				// Check for nearby stones
-				hasNearbyStones := false
-				for di := -1; di <= 1; di++ {
-					for dj := -1; dj <= 1; dj++ {
-						ni, nj := i+di, j+dj
-						if ni >= 0 && ni < BoardSize && nj >= 0 && nj < BoardSize {
-							if board.Grid[ni][nj] != Empty {
-								hasNearbyStones = true
-								weight += 30 // Increase weight for each adjacent stone
-							}
-						}
-					}
-				}
+// SuggestMoveCtx is like SuggestMove but stops thinking and returns the
+// context's error once ctx is cancelled
+func (ai *AI) SuggestMoveCtx(ctx context.Context, board *Board) (Suggestion, error) {
+	helper := *ai
+	helper.player = board.GetCurrentPlayer()
+	helper.variety = 0
+
+	row, col, err := helper.MakeMoveCtx(ctx, board)
+	if err != nil || row < 0 || col < 0 {
+		return Suggestion{Row: -1, Col: -1}, err
+	}
+	return Suggestion{
+		Row:   row,
+		Col:   col,
+		Score: helper.moveScore(board, row, col),
+	}, nil
+}
 
-				// Significantly reduce weight if no nearby stones
-				if !hasNearbyStones {
-					weight /= 2
-				}
+// moveScore scores a move for the AI the way suggestions are scored
+func (ai *AI) moveScore(board *Board, row, col int) int {
+	if board.rules.ThreePlayers {
+		return ai.evaluateThreePlayer(board, row, col)
+	}
+	return ai.evaluatePositionHard(board, row, col)
+}
 
-				// Significantly reduce weight for edge positions
-				if i <= 1 || i >= BoardSize-2 || j <= 1 || j >= BoardSize-2 {
-					weight /= 3
-				}
+// TopMoves returns up to n moves for the player whose turn it is, best first,
+// scored as SuggestMove scores its move. Stones are written to board while the
+// moves are scored, so boards in use elsewhere should be passed as a Clone.
+func (ai *AI) TopMoves(board *Board, n int) []Suggestion {
+	if board.IsGameFinished() {
+		return nil
+	}
+	helper := *ai
+	helper.player = board.GetCurrentPlayer()
+
+	var moves []Suggestion
+	for _, move := range board.CandidateMoves(2) {
+		moves = append(moves, Suggestion{
+			Row:   move[0],
+			Col:   move[1],
+			Score: helper.moveScore(board, move[0], move[1]),
+		})
+	}
+	sort.SliceStable(moves, func(i, j int) bool {
+		return moves[i].Score > moves[j].Score
+	})
+	return moves[:min(n, len(moves))]
+}
 
-				moves = append(moves, moveWithWeight{i, j, weight})
-			}
-		}
+// ShouldSwap decides whether the AI, as White, takes over Black's first
+// stone under the swap opening. It swaps when the stone is far enough from
+// every edge to lie on all the winning lines through it, where it is worth
+// more than the move White would get in reply.
+func (ai *AI) ShouldSwap(board *Board) bool {
+	if !board.CanSwap() {
+		return false
 	}
+	first, edge := board.MoveHistory[0], board.rules.WinLength-1
+	return min(first.Row, first.Col) >= edge && max(first.Row, first.Col) <= BoardSize-1-edge
+}
 
-	if len(moves) > 0 {
-		// Calculate total weight
-		totalWeight := 0
-		for _, move := range moves {
-			totalWeight += move.weight
-		}
+// Easy mode: Prevents opponent's winning moves and three-in-a-row threats, prefers valuable positions
+func (ai *AI) makeEasyMove(board *Board) (int, int) {
+	// 1. Check if AI can win
+	if move := ai.findWinningMove(board, ai.player); move[0] >= 0 {
+		return move[0], move[1]
+	}
 
-		// Randomly select a position, higher weight means higher chance
-		randomWeight := rand.Intn(totalWeight)
-		currentWeight := 0
-		for _, move := range moves {
-			currentWeight += move.weight
-			if currentWeight > randomWeight {
-				return move.row, move.col
-			}
+	// 2. Block the opponent's winning move: even a beginner sees a four
+	// about to become five, so this comes before any mistake
+	if move := ai.findWinningMove(board, ai.getOpponent()); move[0] >= 0 {
+		return move[0], move[1]
+	}
+
+	// If no stones on board, play near center
+	if board.stones == 0 {
+		move := board.centerMove()
+		return move[0], move[1]
+	}
+
+	// 3. Now and then play like a distracted beginner: look only around the
+	// last moves and miss threats elsewhere, or chase own chances and forget
+	// about the opponent's
+	candidates := board.CandidateMoves(2)
+	evaluate := ai.evaluatePosition
+	if ai.makesError() {
+		if ai.intn(2) == 0 {
+			candidates = nearLastMoves(board, candidates)
+		} else {
+			evaluate = ai.evaluateAttack
 		}
+	}
 
-		// If no move selected (shouldn't happen), return the highest weighted move
-		bestMove := moves[0]
-		for _, move := range moves {
-			if move.weight > bestMove.weight {
-				bestMove = move
-			}
+	// 4. Play the best of the moves considered, with a little noise so equal
+	// looking moves vary between games
+	best, bestScore := [2]int{-1, -1}, 0
+	for _, move := range candidates {
+		score := evaluate(board, move[0], move[1]) + ai.intn(easyNoise)
+		if best[0] < 0 || score > bestScore {
+			best, bestScore = move, score
 		}
-		return bestMove.row, bestMove.col
 	}
+	return best[0], best[1]
+}
 
-	// If no suitable position found in valid range, find any empty position
-	for i := 0; i < BoardSize; i++ {
-		for j := 0; j < BoardSize; j++ {
-			if board.Grid[i][j] == Empty {
-				return i, j
+// nearLastMoves keeps the candidates within easyFocus rows and columns of
+// either of the last two moves, or all of them if none are that close
+func nearLastMoves(board *Board, candidates [][2]int) [][2]int {
+	history := board.MoveHistory[max(0, len(board.MoveHistory)-2):]
+	var near [][2]int
+	for _, move := range candidates {
+		for _, last := range history {
+			if !last.IsPass() && abs(move[0]-last.Row) <= easyFocus && abs(move[1]-last.Col) <= easyFocus {
+				near = append(near, move)
+				break
 			}
 		}
 	}
+	if len(near) == 0 {
+		return candidates
+	}
+	return near
+}
+
+// evaluateAttack scores a move by the shapes it makes for the AI only,
+// ignoring the opponent's threats
+func (ai *AI) evaluateAttack(board *Board, row, col int) int {
+	board.setCell(row, col, ai.player)
+	counts := patternCounts(board, row, col)
+	board.setCell(row, col, Empty)
 
-	return -1, -1
+	score := counts[PatternOpenFour]*2000 + counts[PatternFour]*800 + counts[PatternThree]*400
+	centerDist := abs(row-BoardSize/2) + abs(col-BoardSize/2)
+	return score - centerDist*10
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
 }
 
 // Find opponent's threats (three-in-a-row, etc.)
@@ -210,58 +405,55 @@ func (ai *AI) findThreatsMove(board *Board) [2]int {
 		{1, -1}, // Anti-diagonal
 	}
 
-	// Check all empty positions
-	for i := 0; i < BoardSize; i++ {
-		for j := 0; j < BoardSize; j++ {
-			if board.Grid[i][j] != Empty {
-				continue
-			}
+	// Check all empty positions next to existing stones
+	reach := board.rules.WinLength - 1
+	for _, candidate := range board.CandidateMoves(1) {
+		i, j := candidate[0], candidate[1]
 
-			// Check each direction
-			for _, dir := range directions {
-				// Check if this position can block opponent's three-in-a-row
-				count := 0
-				blocked := 0
-
-				// Forward check
-				for k := 1; k < 4; k++ {
-					r, c := i+dir[0]*k, j+dir[1]*k
-					if !board.isValidPosition(r, c) {
-						blocked++
-						break
-					}
-					if board.Grid[r][c] == opponent {
-						count++
-					} else if board.Grid[r][c] != Empty {
-						blocked++
-						break
-					} else {
-						break
-					}
-				}
+		// Check each direction
+		for _, dir := range directions {
+			// Check if this position can block opponent's three-in-a-row
+			count := 0
+			blocked := 0
 
-				// Backward check
-				for k := 1; k < 4; k++ {
-					r, c := i-dir[0]*k, j-dir[1]*k
-					if !board.isValidPosition(r, c) {
-						blocked++
-						break
-					}
-					if board.Grid[r][c] == opponent {
-						count++
-					} else if board.Grid[r][c] != Empty {
-						blocked++
-						break
-					} else {
-						break
-					}
+			// Forward check
+			for k := 1; k < reach; k++ {
+				r, c := i+dir[0]*k, j+dir[1]*k
+				if !board.isValidPosition(r, c) {
+					blocked++
+					break
 				}
+				if board.Grid[r][c] == opponent {
+					count++
+				} else if board.Grid[r][c] != Empty {
+					blocked++
+					break
+				} else {
+					break
+				}
+			}
 
-				// If found three-in-a-row threat (one end not blocked), block immediately
-				if count >= 2 && blocked < 2 {
-					return [2]int{i, j}
+			// Backward check
+			for k := 1; k < reach; k++ {
+				r, c := i-dir[0]*k, j-dir[1]*k
+				if !board.isValidPosition(r, c) {
+					blocked++
+					break
+				}
+				if board.Grid[r][c] == opponent {
+					count++
+				} else if board.Grid[r][c] != Empty {
+					blocked++
+					break
+				} else {
+					break
 				}
 			}
+
+			// If found three-in-a-row threat (one end not blocked), block immediately
+			if count >= reach-2 && blocked < 2 {
+				return [2]int{i, j}
+			}
 		}
 	}
 
@@ -283,7 +475,7 @@ func min(a, b int) int {
 }
 
 // Medium mode: Adds offensive capabilities and strategy to easy mode
-func (ai *AI) makeMediumMove(board *Board) (int, int) {
+func (ai *AI) makeMediumMove(ctx context.Context, board *Board) (int, int) {
 	// 1. Check if AI can win
 	if move := ai.findWinningMove(board, ai.player); move[0] >= 0 {
 		return move[0], move[1]
@@ -294,13 +486,13 @@ func (ai *AI) makeMediumMove(board *Board) (int, int) {
 		return move[0], move[1]
 	}
 
-	// 3. Check if AI can create an open four
-	if move := ai.findOpenFourMove(board, ai.player); move[0] >= 0 {
+	// 3. Check if AI can create an open four, double-four or four-three
+	if move := ai.findComboMove(board, ai.player, comboFourThree); move[0] >= 0 {
 		return move[0], move[1]
 	}
 
-	// 4. Check if opponent can create an open four
-	if move := ai.findOpenFourMove(board, ai.getOpponent()); move[0] >= 0 {
+	// 4. Check if opponent can create an open four, double-four or four-three
+	if move := ai.findComboMove(board, ai.getOpponent(), comboFourThree); move[0] >= 0 {
 		return move[0], move[1]
 	}
 
@@ -315,21 +507,7 @@ func (ai *AI) makeMediumMove(board *Board) (int, int) {
 	}
 
 	// 7. Use evaluation function to find best position
-	bestScore := math.MinInt32
-	bestMove := [2]int{-1, -1}
-
-	for i := 0; i < BoardSize; i++ {
-		for j := 0; j < BoardSize; j++ {
-			if board.Grid[i][j] == Empty {
-				score := ai.evaluatePositionMedium(board, i, j)
-				if score > bestScore {
-					bestScore = score
-					bestMove = [2]int{i, j}
-				}
-			}
-		}
-	}
-
+	bestMove := ai.findBestPosition(ctx, board, ai.evaluatePositionMedium)
 	if bestMove[0] >= 0 {
 		return bestMove[0], bestMove[1]
 	}
@@ -339,7 +517,7 @@ func (ai *AI) makeMediumMove(board *Board) (int, int) {
 }
 
 // Hard mode: Uses advanced strategies and deep evaluation
-func (ai *AI) makeHardMove(board *Board) (int, int) {
+func (ai *AI) makeHardMove(ctx context.Context, board *Board) (int, int) {
 	// 1. Check if AI can win
 	if move := ai.findWinningMove(board, ai.player); move[0] >= 0 {
 		return move[0], move[1]
@@ -350,210 +528,212 @@ func (ai *AI) makeHardMove(board *Board) (int, int) {
 		return move[0], move[1]
 	}
 
-	// 3. Check if AI can create an open four or double-three
-	if move := ai.findAdvancedThreatMove(board, ai.player); move[0] >= 0 {
+	// 3. Check if AI can create an open four, double-four or four-three
+	if move := ai.findComboMove(board, ai.player, comboFourThree); move[0] >= 0 {
 		return move[0], move[1]
 	}
 
-	// 4. Check and block opponent's open four or double-three
+	// 4. Check and block opponent's open four, double-four or four-three
+	if move := ai.findComboMove(board, ai.getOpponent(), comboFourThree); move[0] >= 0 {
+		return move[0], move[1]
+	}
+
+	// 5. Check if AI can create a double-three, or block the opponent's
+	if move := ai.findAdvancedThreatMove(board, ai.player); move[0] >= 0 {
+		return move[0], move[1]
+	}
 	if move := ai.findAdvancedThreatMove(board, ai.getOpponent()); move[0] >= 0 {
 		return move[0], move[1]
 	}
 
-	// 5. Check if AI can create a single open three
+	// 6. Check if AI can create a single open three
 	if move := ai.findOpenThreeMove(board, ai.player); move[0] >= 0 {
 		return move[0], move[1]
 	}
 
-	// 6. Check if opponent can create a single open three
+	// 7. Check if opponent can create a single open three
 	if move := ai.findOpenThreeMove(board, ai.getOpponent()); move[0] >= 0 {
 		return move[0], move[1]
 	}
 
-	// 7. Use advanced evaluation function to find best position
-	bestScore := math.MinInt32
-	bestMove := [2]int{-1, -1}
+	// 8. Use advanced evaluation function to find best position
+	bestMove := ai.findBestPosition(ctx, board, ai.evaluatePositionHard)
+	if bestMove[0] >= 0 {
+		return bestMove[0], bestMove[1]
+	}
 
-	for i := 0; i < BoardSize; i++ {
-		for j := 0; j < BoardSize; j++ {
-			if board.Grid[i][j] == Empty {
-				score := ai.evaluatePositionHard(board, i, j)
-				if score > bestScore {
-					bestScore = score
-					bestMove = [2]int{i, j}
+	// 9. If no good moves found, use medium mode strategy
+	return ai.makeMediumMove(ctx, board)
+}
+
+// findBestPosition scores every candidate position with evaluate and returns
+// the best one. The positions are split across worker goroutines, each searching
+// its own copy of the board; ties go to the first position in row order.
+func (ai *AI) findBestPosition(ctx context.Context, board *Board, evaluate func(*Board, int, int) int) [2]int {
+	positions := board.CandidateMoves(2)
+
+	type result struct {
+		score int
+		index int
+	}
+	workers := min(ai.workers, len(positions))
+	results := make([]result, workers)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			local := board.Clone()
+			best := result{math.MinInt32, -1}
+			for k := w; k < len(positions); k += workers {
+				if ctx.Err() != nil {
+					break
+				}
+				if score := evaluate(local, positions[k][0], positions[k][1]); score > best.score {
+					best = result{score, k}
 				}
 			}
-		}
+			results[w] = best
+		}(w)
 	}
+	wg.Wait()
 
-	if bestMove[0] >= 0 {
-		return bestMove[0], bestMove[1]
+	if ctx.Err() != nil {
+		return [2]int{-1, -1}
 	}
 
-	// 8. If no good moves found, use medium mode strategy
-	return ai.makeMediumMove(board)
+	best := result{math.MinInt32, -1}
+	for _, r := range results {
+		if r.index < 0 {
+			continue
+		}
+		if best.index < 0 || r.score > best.score || (r.score == best.score && r.index < best.index) {
+			best = r
+		}
+	}
+	if best.index < 0 {
+		return [2]int{-1, -1}
+	}
+	return positions[best.index]
 }
 
 func (ai *AI) findWinningMove(board *Board, player Player) [2]int {
-	// Check all empty positions to see if any can form five in a row
-	for i := 0; i < BoardSize; i++ {
-		for j := 0; j < BoardSize; j++ {
-			if board.Grid[i][j] == Empty {
-				board.Grid[i][j] = player
-				if board.CheckWin(i, j) {
-					board.Grid[i][j] = Empty
-					return [2]int{i, j}
-				}
-				board.Grid[i][j] = Empty
-			}
+	// Check empty positions next to existing stones to see if any can form five in a row
+	for _, candidate := range board.CandidateMoves(1) {
+		i, j := candidate[0], candidate[1]
+		board.setCell(i, j, player)
+		if board.CheckWin(i, j) {
+			board.setCell(i, j, Empty)
+			return [2]int{i, j}
 		}
+		board.setCell(i, j, Empty)
 	}
 	return [2]int{-1, -1}
 }
 
 // Find positions that can form an open four
 func (ai *AI) findOpenFourMove(board *Board, player Player) [2]int {
-	for i := 0; i < BoardSize; i++ {
-		for j := 0; j < BoardSize; j++ {
-			if board.Grid[i][j] != Empty {
-				continue
-			}
-			board.Grid[i][j] = player
-			if ai.hasOpenFour(board, i, j) {
-				board.Grid[i][j] = Empty
-				return [2]int{i, j}
-			}
-			board.Grid[i][j] = Empty
+	for _, candidate := range board.CandidateMoves(1) {
+		i, j := candidate[0], candidate[1]
+		board.setCell(i, j, player)
+		if ai.hasOpenFour(board, i, j) {
+			board.setCell(i, j, Empty)
+			return [2]int{i, j}
 		}
+		board.setCell(i, j, Empty)
 	}
 	return [2]int{-1, -1}
 }
 
 // Find positions that can form an open three
 func (ai *AI) findOpenThreeMove(board *Board, player Player) [2]int {
-	for i := 0; i < BoardSize; i++ {
-		for j := 0; j < BoardSize; j++ {
-			if board.Grid[i][j] != Empty {
-				continue
-			}
-			board.Grid[i][j] = player
-			if ai.hasOpenThree(board, i, j) {
-				board.Grid[i][j] = Empty
-				return [2]int{i, j}
-			}
-			board.Grid[i][j] = Empty
+	for _, candidate := range board.CandidateMoves(1) {
+		i, j := candidate[0], candidate[1]
+		board.setCell(i, j, player)
+		if ai.hasOpenThree(board, i, j) {
+			board.setCell(i, j, Empty)
+			return [2]int{i, j}
 		}
+		board.setCell(i, j, Empty)
 	}
 	return [2]int{-1, -1}
 }
 
-// Find advanced threats (open four or double-three)
-func (ai *AI) findAdvancedThreatMove(board *Board, player Player) [2]int {
-	for i := 0; i < BoardSize; i++ {
-		for j := 0; j < BoardSize; j++ {
-			if board.Grid[i][j] != Empty {
-				continue
-			}
-			board.Grid[i][j] = player
+// Combination threats, from weakest to strongest
+const (
+	comboNone        = iota
+	comboDoubleThree // Two open threes
+	comboFourThree   // A four and an open three
+	comboWinning     // An open four or two fours, which cannot both be blocked
+)
 
-			// Check for open four
-			if ai.hasOpenFour(board, i, j) {
-				board.Grid[i][j] = Empty
-				return [2]int{i, j}
-			}
+// Find advanced threats (open four, double-four, four-three or double-three),
+// preferring the strongest
+func (ai *AI) findAdvancedThreatMove(board *Board, player Player) [2]int {
+	return ai.findComboMove(board, player, comboDoubleThree)
+}
 
-			// Check for double-three
-			if ai.hasDoubleThree(board, i, j) {
-				board.Grid[i][j] = Empty
-				return [2]int{i, j}
+// findComboMove returns the move making the strongest combination threat of
+// at least minCombo for player, or -1, -1 if there is none
+func (ai *AI) findComboMove(board *Board, player Player, minCombo int) [2]int {
+	best, bestCombo := [2]int{-1, -1}, minCombo-1
+	for _, candidate := range board.CandidateMoves(1) {
+		i, j := candidate[0], candidate[1]
+		board.setCell(i, j, player)
+		combo := ai.comboThreat(board, i, j)
+		board.setCell(i, j, Empty)
+
+		if combo > bestCombo {
+			best, bestCombo = [2]int{i, j}, combo
+			if combo == comboWinning {
+				break
 			}
-
-			board.Grid[i][j] = Empty
 		}
 	}
-	return [2]int{-1, -1}
+	return best
+}
+
+// comboThreat returns the combination the stone at (row, col) makes
+func (ai *AI) comboThreat(board *Board, row, col int) int {
+	counts := patternCounts(board, row, col)
+	fours := counts[PatternFour] + counts[PatternOpenFour]
+	switch {
+	case counts[PatternOpenFour] > 0 || fours >= 2:
+		return comboWinning
+	case fours == 1 && counts[PatternThree] > 0:
+		return comboFourThree
+	case counts[PatternThree] >= 2:
+		return comboDoubleThree
+	}
+	return comboNone
 }
 
 // Check for double-three formation
 func (ai *AI) hasDoubleThree(board *Board, row, col int) bool {
-	directions := [][2]int{{1, 0}, {0, 1}, {1, 1}, {1, -1}}
-	player := board.Grid[row][col]
-	threeCount := 0
-
-	for _, dir := range directions {
-		count := 1
-		space := 0
-		blocked := 0
-
-		// Forward check
-		for i := 1; i < 4; i++ {
-			r, c := row+dir[0]*i, col+dir[1]*i
-			if !board.isValidPosition(r, c) {
-				blocked++
-				break
-			}
-			if board.Grid[r][c] == player {
-				count++
-			} else if board.Grid[r][c] == Empty {
-				space++
-				break
-			} else {
-				blocked++
-				break
-			}
-		}
-
-		// Backward check
-		for i := 1; i < 4; i++ {
-			r, c := row-dir[0]*i, col-dir[1]*i
-			if !board.isValidPosition(r, c) {
-				blocked++
-				break
-			}
-			if board.Grid[r][c] == player {
-				count++
-			} else if board.Grid[r][c] == Empty {
-				space++
-				break
-			} else {
-				blocked++
-				break
-			}
-		}
-
-		// If an open three is formed in this direction
-		if count == 3 && space == 2 && blocked == 0 {
-			threeCount++
-		}
-	}
+	return patternCounts(board, row, col)[PatternThree] >= 2
+}
 
-	return threeCount >= 2
+// Check for a double-four or four-three formation
+func (ai *AI) hasFourCombo(board *Board, row, col int) bool {
+	combo := ai.comboThreat(board, row, col)
+	return combo == comboWinning || combo == comboFourThree
 }
 
 // Medium difficulty position evaluation
 func (ai *AI) evaluatePositionMedium(board *Board, row, col int) int {
 	score := ai.evaluatePosition(board, row, col)
+	weights := ai.weights.Medium
 
 	// Check for potential open three or four formations
-	board.Grid[row][col] = ai.player
-	if ai.hasOpenFour(board, row, col) {
-		score += 800
-	}
-	if ai.hasOpenThree(board, row, col) {
-		score += 400
-	}
-	board.Grid[row][col] = Empty
+	board.setCell(row, col, ai.player)
+	score += ai.threatScore(board, row, col, weights.Attack)
+	board.setCell(row, col, Empty)
 
 	// Check for blocking opponent's open three or four
-	opponent := ai.getOpponent()
-	board.Grid[row][col] = opponent
-	if ai.hasOpenFour(board, row, col) {
-		score += 700
-	}
-	if ai.hasOpenThree(board, row, col) {
-		score += 300
-	}
-	board.Grid[row][col] = Empty
+	board.setCell(row, col, ai.getOpponent())
+	score += ai.threatScore(board, row, col, weights.Defense)
+	board.setCell(row, col, Empty)
 
 	return score
 }
@@ -561,61 +741,42 @@ func (ai *AI) evaluatePositionMedium(board *Board, row, col int) int {
 // Hard difficulty position evaluation
 func (ai *AI) evaluatePositionHard(board *Board, row, col int) int {
 	score := ai.evaluatePosition(board, row, col)
+	weights := ai.weights.Hard
 
 	// Check offensive potential
-	board.Grid[row][col] = ai.player
-	if ai.hasOpenFour(board, row, col) {
-		score += 1200
-	}
-	if ai.hasDoubleThree(board, row, col) {
-		score += 1000
-	}
-	if ai.hasOpenThree(board, row, col) {
-		score += 600
-	}
-	board.Grid[row][col] = Empty
+	board.setCell(row, col, ai.player)
+	score += ai.threatScore(board, row, col, weights.Attack)
+	board.setCell(row, col, Empty)
 
 	// Check defensive needs
-	opponent := ai.getOpponent()
-	board.Grid[row][col] = opponent
-	if ai.hasOpenFour(board, row, col) {
-		score += 1000
-	}
-	if ai.hasDoubleThree(board, row, col) {
-		score += 800
-	}
-	if ai.hasOpenThree(board, row, col) {
-		score += 500
-	}
-	board.Grid[row][col] = Empty
+	board.setCell(row, col, ai.getOpponent())
+	score += ai.threatScore(board, row, col, weights.Defense)
+	board.setCell(row, col, Empty)
 
 	// Consider strategic value
 	// 1. Center proximity value
 	centerDist := math.Abs(float64(row-BoardSize/2)) + math.Abs(float64(col-BoardSize/2))
-	score -= int(centerDist * 15)
+	score -= int(centerDist * float64(weights.CenterDistance))
 
 	// 2. Value proximity to existing stones
-	nearbyStones := 0
 	for i := -2; i <= 2; i++ {
 		for j := -2; j <= 2; j++ {
 			r, c := row+i, col+j
 			if r >= 0 && r < BoardSize && c >= 0 && c < BoardSize {
-				if board.Grid[r][c] != Empty {
-					dist := math.Abs(float64(i)) + math.Abs(float64(j))
-					if dist <= 1 {
-						nearbyStones += 3
+				if cell := board.Grid[r][c]; cell != Empty && cell != Blocked {
+					if abs(i)+abs(j) <= 1 {
+						score += weights.AdjacentStone
 					} else {
-						nearbyStones++
+						score += weights.NearbyStone
 					}
 				}
 			}
 		}
 	}
-	score += nearbyStones * 10
 
 	// 3. Reduce value for edge positions
 	if row <= 1 || row >= BoardSize-2 || col <= 1 || col >= BoardSize-2 {
-		score /= 2
+		score /= weights.EdgeDivisor
 	}
 
 	return score
@@ -623,6 +784,7 @@ func (ai *AI) evaluatePositionHard(board *Board, row, col int) int {
 
 func (ai *AI) evaluatePosition(board *Board, row, col int) int {
 	score := 0
+	weights := &ai.weights
 	directions := [][2]int{
 		{1, 0},  // Vertical
 		{0, 1},  // Horizontal
@@ -631,21 +793,21 @@ func (ai *AI) evaluatePosition(board *Board, row, col int) int {
 	}
 
 	// Check for winning move
-	board.Grid[row][col] = ai.player
+	board.setCell(row, col, ai.player)
 	if board.CheckWin(row, col) {
-		board.Grid[row][col] = Empty
-		return 10000
+		board.setCell(row, col, Empty)
+		return weights.Win
 	}
-	board.Grid[row][col] = Empty
+	board.setCell(row, col, Empty)
 
 	// Check for blocking opponent's win
 	opponent := ai.getOpponent()
-	board.Grid[row][col] = opponent
+	board.setCell(row, col, opponent)
 	if board.CheckWin(row, col) {
-		board.Grid[row][col] = Empty
-		return 9000
+		board.setCell(row, col, Empty)
+		return weights.BlockWin
 	}
-	board.Grid[row][col] = Empty
+	board.setCell(row, col, Empty)
 
 	// Evaluate each direction
 	for _, dir := range directions {
@@ -654,13 +816,12 @@ func (ai *AI) evaluatePosition(board *Board, row, col int) int {
 
 	// Prefer positions closer to center
 	centerDist := math.Abs(float64(row-BoardSize/2)) + math.Abs(float64(col-BoardSize/2))
-	score -= int(centerDist * 10)
+	score -= int(centerDist * float64(weights.CenterDistance))
 
 	// Prefer positions closer to last move
-	if len(board.MoveHistory) > 0 {
-		lastMove := board.MoveHistory[len(board.MoveHistory)-1]
-		lastDist := math.Abs(float64(row-lastMove[0])) + math.Abs(float64(col-lastMove[1]))
-		score -= int(lastDist * 5)
+	if lastMove, ok := board.LastMove(); ok && !lastMove.IsPass() {
+		lastDist := math.Abs(float64(row-lastMove.Row)) + math.Abs(float64(col-lastMove.Col))
+		score -= int(lastDist * float64(weights.LastMoveDistance))
 	}
 
 	return score
@@ -668,158 +829,45 @@ func (ai *AI) evaluatePosition(board *Board, row, col int) int {
 
 func (ai *AI) evaluateDirection(board *Board, row, col, dRow, dCol int) int {
 	score := 0
-	myCount := 0
-	oppCount := 0
-	empty := 0
-	maxMySeq := 0  // Maximum consecutive own stones
-	maxOppSeq := 0 // Maximum consecutive opponent stones
-	currentMySeq := 0
-	currentOppSeq := 0
+	weights := &ai.weights.Line
 
 	// Check 4 positions in both directions
-	for i := -4; i <= 4; i++ {
-		r, c := row+dRow*i, col+dCol*i
-		if r < 0 || r >= BoardSize || c < 0 || c >= BoardSize {
-			continue
-		}
-
-		current := board.Grid[r][c]
-		if current == ai.player {
-			myCount++
-			currentMySeq++
-			currentOppSeq = 0
-			if currentMySeq > maxMySeq {
-				maxMySeq = currentMySeq
-			}
-		} else if current == Empty {
-			empty++
-			currentMySeq = 0
-			currentOppSeq = 0
-		} else {
-			oppCount++
-			currentOppSeq++
-			currentMySeq = 0
-			if currentOppSeq > maxOppSeq {
-				maxOppSeq = currentOppSeq
-			}
-		}
-	}
+	length := board.rules.WinLength
+	own, open, other := board.bits.lineWindow(row, col, dRow, dCol, length-1, ai.player)
+	myCount := bits.OnesCount16(own)
+	empty := bits.OnesCount16(open)
+	maxMySeq := longestRun(own)    // Maximum consecutive own stones
+	maxOppSeq := longestRun(other) // Maximum consecutive opponent stones
 
 	// Scoring rules
-	if maxMySeq >= 4 {
-		score += 2000
-	} else if maxMySeq == 3 && empty >= 2 {
-		score += 1000
-	} else if maxMySeq == 2 && empty >= 3 {
-		score += 100
+	if maxMySeq >= length-1 {
+		score += weights.Four
+	} else if maxMySeq == length-2 && empty >= 2 {
+		score += weights.Three
+	} else if maxMySeq == length-3 && empty >= 3 {
+		score += weights.Two
 	}
 
 	// Defensive scoring
-	if maxOppSeq >= 3 {
-		score += 1500
-	} else if maxOppSeq == 2 && empty >= 3 {
-		score += 200
+	if maxOppSeq >= length-2 {
+		score += weights.BlockThree
+	} else if maxOppSeq == length-3 && empty >= 3 {
+		score += weights.BlockTwo
 	}
 
 	// Consider total stone count
-	score += myCount * 10
-	score += empty * 2
+	score += myCount * weights.Stone
+	score += empty * weights.Empty
 
 	return score
 }
 
 func (ai *AI) hasOpenFour(board *Board, row, col int) bool {
-	directions := [][2]int{{1, 0}, {0, 1}, {1, 1}, {1, -1}}
-	player := board.Grid[row][col]
-
-	for _, dir := range directions {
-		count := 1
-		space := 0
-
-		// Forward check
-		for i := 1; i < 5; i++ {
-			r, c := row+dir[0]*i, col+dir[1]*i
-			if !board.isValidPosition(r, c) {
-				break
-			}
-			if board.Grid[r][c] == player {
-				count++
-			} else if board.Grid[r][c] == Empty {
-				space++
-				break
-			} else {
-				break
-			}
-		}
-
-		// Backward check
-		for i := 1; i < 5; i++ {
-			r, c := row-dir[0]*i, col-dir[1]*i
-			if !board.isValidPosition(r, c) {
-				break
-			}
-			if board.Grid[r][c] == player {
-				count++
-			} else if board.Grid[r][c] == Empty {
-				space++
-				break
-			} else {
-				break
-			}
-		}
-
-		if count == 4 && space == 2 {
-			return true
-		}
-	}
-	return false
+	return patternCounts(board, row, col)[PatternOpenFour] > 0
 }
 
 func (ai *AI) hasOpenThree(board *Board, row, col int) bool {
-	directions := [][2]int{{1, 0}, {0, 1}, {1, 1}, {1, -1}}
-	player := board.Grid[row][col]
-
-	for _, dir := range directions {
-		count := 1
-		space := 0
-
-		// Forward check
-		for i := 1; i < 4; i++ {
-			r, c := row+dir[0]*i, col+dir[1]*i
-			if !board.isValidPosition(r, c) {
-				break
-			}
-			if board.Grid[r][c] == player {
-				count++
-			} else if board.Grid[r][c] == Empty {
-				space++
-				break
-			} else {
-				break
-			}
-		}
-
-		// Backward check
-		for i := 1; i < 4; i++ {
-			r, c := row-dir[0]*i, col-dir[1]*i
-			if !board.isValidPosition(r, c) {
-				break
-			}
-			if board.Grid[r][c] == player {
-				count++
-			} else if board.Grid[r][c] == Empty {
-				space++
-				break
-			} else {
-				break
-			}
-		}
-
-		if count == 3 && space == 2 {
-			return true
-		}
-	}
-	return false
+	return patternCounts(board, row, col)[PatternThree] > 0
 }
 
 func (ai *AI) getOpponent() Player {
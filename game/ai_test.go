@@ -0,0 +1,36 @@
+package game
+
+import "testing"
+
+func TestEasyBlocksFive(t *testing.T) {
+	board := playMoves(t, "h4 h3 h5 a1 h6 a15 h7")
+	for seed := int64(0); seed < 50; seed++ {
+		ai := NewAI(White, Easy)
+		ai.SetBook(nil)
+		ai.SetErrorRate(1) // A mistake every move
+		ai.SetSeed(seed)
+		if row, col := ai.MakeMove(board); FormatMove(row, col) != "h8" {
+			t.Fatalf("seed %d: played %s, not the block at h8", seed, FormatMove(row, col))
+		}
+	}
+}
+
+func TestEvaluateRedWin(t *testing.T) {
+	board, err := NewBoardWithRules(Rules{WinLength: 4, ThreePlayers: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 4; i++ {
+		for _, move := range [][2]int{{0, 2 * i}, {14, 2 * i}, {7, 7 + i}} {
+			if err := board.PlaceStone(move[0], move[1]); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if winner := board.Result().Winner; winner != Red {
+		t.Fatalf("%v won, want Red", winner)
+	}
+	if score := NewAI(Black, Expert).Evaluate(board); score != -WinScore {
+		t.Errorf("score is %d after Red won, want %d", score, -WinScore)
+	}
+}
@@ -0,0 +1,199 @@
+package game
+
+import "sync"
+
+// lineSlots is the number of diagonal lines in each diagonal direction
+const lineSlots = 2*BoardSize - 1
+
+// bitboard holds each player's stones as bits, one uint16 per line of the
+// board in each direction, so a line scan is a few shifts and masks. The bit
+// of a cell is its column in a row and its row in every other direction.
+type bitboard struct {
+	rows  [5][BoardSize]uint16
+	cols  [5][BoardSize]uint16
+	diags [5][lineSlots]uint16 // (1, 1) lines, by row-col+BoardSize-1
+	antis [5][lineSlots]uint16 // (1, -1) lines, by row+col
+}
+
+// Masks of the cells on the board in each diagonal line
+var diagMasks, antiMasks = func() (diag, anti [lineSlots]uint16) {
+	for i := 0; i < BoardSize; i++ {
+		for j := 0; j < BoardSize; j++ {
+			diag[i-j+BoardSize-1] |= 1 << i
+			anti[i+j] |= 1 << i
+		}
+	}
+	return diag, anti
+}()
+
+const fullLine = 1<<BoardSize - 1
+
+// toggle flips the bit of player's stone at (row, col)
+func (bb *bitboard) toggle(row, col int, player Player) {
+	bb.rows[player][row] ^= 1 << col
+	bb.cols[player][col] ^= 1 << row
+	bb.diags[player][row-col+BoardSize-1] ^= 1 << row
+	bb.antis[player][row+col] ^= 1 << row
+}
+
+// line returns the bits of player's stones on the line through (row, col)
+// in the direction (dRow, dCol), the mask of cells on the board in that line,
+// and the bit of (row, col) itself
+func (bb *bitboard) line(row, col, dRow, dCol int, player Player) (stones, cells uint16, pos int) {
+	if dRow < 0 || dRow == 0 && dCol < 0 {
+		dRow, dCol = -dRow, -dCol // Same line, scanned the other way
+	}
+	switch {
+	case dRow == 0:
+		return bb.rows[player][row], fullLine, col
+	case dCol == 0:
+		return bb.cols[player][col], fullLine, row
+	case dRow == dCol:
+		d := row - col + BoardSize - 1
+		return bb.diags[player][d], diagMasks[d], row
+	default:
+		s := row + col
+		return bb.antis[player][s], antiMasks[s], row
+	}
+}
+
+// lineWindow returns the cells within reach of (row, col) along a direction:
+// player's stones, the empty cells, and the other players' stones. Blocked
+// points are in none of them. Bit k is the cell k-reach steps along the
+// direction.
+func (bb *bitboard) lineWindow(row, col, dRow, dCol, reach int, player Player) (own, empty, other uint16) {
+	own, cells, pos := bb.line(row, col, dRow, dCol, player)
+	for opponent := Black; opponent <= Red; opponent++ {
+		if opponent != player {
+			stones, _, _ := bb.line(row, col, dRow, dCol, opponent)
+			other |= stones
+		}
+	}
+	blocked, _, _ := bb.line(row, col, dRow, dCol, Blocked)
+	empty = cells &^ own &^ other &^ blocked
+	mask := uint32(1)<<(2*reach+1) - 1
+	window := func(line uint16) uint16 {
+		return uint16(uint32(line) << reach >> pos & mask)
+	}
+	return window(own), window(empty), window(other)
+}
+
+// hasRun reports whether player has length or more stones in a row through
+// (row, col) in any direction
+func (bb *bitboard) hasRun(row, col int, player Player, length int) bool {
+	return runThrough(bb.rows[player][row], col, length) ||
+		runThrough(bb.cols[player][col], row, length) ||
+		runThrough(bb.diags[player][row-col+BoardSize-1], row, length) ||
+		runThrough(bb.antis[player][row+col], row, length)
+}
+
+// hasExactRun reports whether player has exactly length stones in a row
+// through (row, col) in some direction
+func (bb *bitboard) hasExactRun(row, col int, player Player, length int) bool {
+	exact := func(stones uint16, pos int) bool {
+		return runThrough(stones, pos, length) && !runThrough(stones, pos, length+1)
+	}
+	return exact(bb.rows[player][row], col) ||
+		exact(bb.cols[player][col], row) ||
+		exact(bb.diags[player][row-col+BoardSize-1], row) ||
+		exact(bb.antis[player][row+col], row)
+}
+
+// runThrough reports whether the stones of a line hold length or more in a
+// row through bit pos
+func runThrough(stones uint16, pos, length int) bool {
+	runs := uint32(stones) // Bit k set: a run of length starts at k
+	for i := 1; i < length; i++ {
+		runs &= uint32(stones) >> i
+	}
+	through := uint32(1)<<length - 1
+	through = through << pos >> (length - 1)
+	return runs&through != 0
+}
+
+// longestRun returns the length of the longest run of set bits
+func longestRun(x uint16) int {
+	run := 0
+	for x != 0 {
+		x &= x << 1
+		run++
+	}
+	return run
+}
+
+// patternTables map the stones and empty cells around a stone, excluding
+// the stone itself, to the pattern the stone forms, one table per win length
+// with and without exact rows. Indexed by patternKey, they are built when a
+// game first needs them.
+var patternTables [2][MaxWinLength + 1]struct {
+	once  sync.Once
+	table []Pattern
+}
+
+// windowPatterns returns the pattern table for the win length and exactness
+// of rules
+func windowPatterns(rules Rules) []Pattern {
+	exact := 0
+	if rules.Exact {
+		exact = 1
+	}
+	t := &patternTables[exact][rules.WinLength]
+	t.once.Do(func() {
+		t.table = buildPatterns(rules)
+	})
+	return t.table
+}
+
+// patternReach returns how far each way from a stone its patterns are
+// scanned: one cell short of the win length, or, for exact rows, up to the
+// cell that would make an overline
+func patternReach(rules Rules) int {
+	if rules.Exact {
+		return rules.WinLength
+	}
+	return rules.WinLength - 1
+}
+
+func buildPatterns(rules Rules) []Pattern {
+	reach := patternReach(rules)
+	table := make([]Pattern, 1<<(4*reach))
+	line := patternLine{cells: make([]Player, 2*reach+1), rules: rules}
+	var fill func(k int)
+	fill = func(k int) {
+		if k == len(line.cells) {
+			var own, empty uint16
+			for i, cell := range line.cells {
+				switch cell {
+				case Black:
+					own |= 1 << i
+				case Empty:
+					empty |= 1 << i
+				}
+			}
+			table[patternKey(own, empty, reach)] = line.pattern(Black)
+			return
+		}
+		if k == reach {
+			line.cells[k] = Black
+			fill(k + 1)
+			return
+		}
+		for _, cell := range []Player{Black, Empty, wall} {
+			line.cells[k] = cell
+			fill(k + 1)
+		}
+	}
+	fill(0)
+	return table
+}
+
+// patternKey packs a window of own stones and empty cells reaching reach
+// cells each way, leaving out the middle cell, into an index of a pattern
+// table
+func patternKey(own, empty uint16, reach int) int {
+	low := uint16(1)<<reach - 1
+	pack := func(x uint16) int {
+		return int(x&low) | int(x>>(reach+1))<<reach
+	}
+	return pack(own) | pack(empty)<<(2*reach)
+}
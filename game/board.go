@@ -1,31 +1,80 @@
 package game
 
-import "errors"
+import (
+	"errors"
+	"time"
+)
 
 const (
 	BoardSize    = 15
-	WinCondition = 5
+	WinCondition = 5 // Stones in a row that win under StandardRules
+
+	// MaxCandidateRadius is the largest radius supported by CandidateMoves
+	MaxCandidateRadius = 3
 )
 
+// PassMove is recorded in MoveHistory when a player passes
+var PassMove = [2]int{-1, -1}
+
 type Player int
 
 const (
 	Empty Player = iota
 	Black
 	White
+	Red     // Third player, only in three-player games
+	Blocked // A point no player may take, see Board.SetupObstacles
 )
 
+// String names the player, e.g. "Black"
+func (p Player) String() string {
+	switch p {
+	case Black:
+		return "Black"
+	case White:
+		return "White"
+	case Red:
+		return "Red"
+	case Blocked:
+		return "Blocked"
+	}
+	return "none"
+}
+
 type Board struct {
-	Grid         [BoardSize][BoardSize]Player
-	CurrentTurn  Player
-	MoveHistory  [][2]int
-	GameFinished bool
+	Grid        [BoardSize][BoardSize]Player
+	CurrentTurn Player
+	MoveHistory []Move
+
+	rules  Rules
+	result Result
+	stones int // Stones on the board, to spot a full board
+
+	handicap  [][2]int // Black's handicap stones, placed before the first move
+	obstacles [][2]int // Blocked points, set before the first move
+
+	// redo holds the moves taken back by Undo, the next to replay last. It
+	// is kept while the same moves are played again and dropped otherwise.
+	redo [][2]int
+
+	// nearby[r-1][i][j] counts the stones within r rows and columns of (i, j)
+	nearby [MaxCandidateRadius][BoardSize][BoardSize]uint8
+
+	// eval follows the stones placed and undone, for the static evaluation
+	eval Eval
+
+	// bits mirrors Grid for fast line scans; cells must be written with setCell
+	bits bitboard
+
+	hash uint64 // Zobrist hash of the stones, kept by setCell
 }
 
 func NewBoard() *Board {
 	return &Board{
 		CurrentTurn: Black,
-		MoveHistory: make([][2]int, 0),
+		MoveHistory: make([]Move, 0),
+		rules:       StandardRules,
+		eval:        Eval{length: WinCondition},
 	}
 }
 
@@ -34,23 +83,58 @@ func (b *Board) PlaceStone(row, col int) error {
 		return errors.New("position out of bounds")
 	}
 
+	if b.rules.Gravity {
+		if row = b.DropRow(col); row < 0 {
+			return errors.New("column is full")
+		}
+	}
+
+	if b.Grid[row][col] == Blocked {
+		return errors.New("position is blocked")
+	}
 	if b.Grid[row][col] != Empty {
 		return errors.New("position already occupied")
 	}
 
-	if b.GameFinished {
+	if b.result.Finished() {
 		return errors.New("game is already finished")
 	}
 
-	b.Grid[row][col] = b.CurrentTurn
-	b.MoveHistory = append(b.MoveHistory, [2]int{row, col})
+	if err := b.checkOpening(row, col); err != nil {
+		return err
+	}
+
+	b.setCell(row, col, b.CurrentTurn)
+	b.recordMove(row, col)
+	b.stones++
+	b.updateNearby(row, col, 1)
+	b.eval.place(row, col, b.CurrentTurn)
 
 	if b.CheckWin(row, col) {
-		b.GameFinished = true
+		b.result = Result{Winner: b.CurrentTurn, Reason: ReasonRow}
 		return nil
 	}
+	if b.full() {
+		b.result = Result{Draw: true, Reason: ReasonFullBoard}
+	}
+
+	b.CurrentTurn = b.nextPlayer()
+	return nil
+}
+
+// Pass gives up the turn without placing a stone. Once every player has
+// passed in a row the game ends as a draw.
+func (b *Board) Pass() error {
+	if b.result.Finished() {
+		return errors.New("game is already finished")
+	}
 
+	passes := b.trailingPasses() + 1
+	b.recordMove(PassMove[0], PassMove[1])
 	b.CurrentTurn = b.nextPlayer()
+	if passes == len(b.rules.Players()) {
+		b.result = Result{Draw: true, Reason: ReasonPasses}
+	}
 	return nil
 }
 
@@ -60,56 +144,190 @@ func (b *Board) Undo() error {
 	}
 
 	lastMove := b.MoveHistory[len(b.MoveHistory)-1]
-	b.Grid[lastMove[0]][lastMove[1]] = Empty
+	b.redo = append(b.redo, lastMove.Pos())
 	b.MoveHistory = b.MoveHistory[:len(b.MoveHistory)-1]
-	b.CurrentTurn = b.nextPlayer()
-	b.GameFinished = false
+	b.CurrentTurn = lastMove.Player // A winning move does not pass the turn
+	b.result = Result{}
+	if len(b.MoveHistory) == 0 && b.rules.Opening == SwapOpening && lastMove.Player == White {
+		// A swapped first stone; the game starts over with Black
+		b.CurrentTurn = Black
+		b.redo = b.redo[:0]
+	}
+	if lastMove.IsPass() {
+		return nil
+	}
+	b.setCell(lastMove.Row, lastMove.Col, Empty)
+	b.stones--
+	b.updateNearby(lastMove.Row, lastMove.Col, -1)
+	b.eval.remove(lastMove.Row, lastMove.Col, lastMove.Player)
 	return nil
 }
 
-func (b *Board) CheckWin(row, col int) bool {
-	directions := [][2]int{
-		{1, 0},  // vertical
-		{0, 1},  // horizontal
-		{1, 1},  // diagonal
-		{1, -1}, // anti-diagonal
+// Redo plays the last move taken back by Undo again
+func (b *Board) Redo() error {
+	if len(b.redo) == 0 {
+		return errors.New("no moves to redo")
+	}
+	move := b.redo[len(b.redo)-1]
+	if move == PassMove {
+		return b.Pass()
 	}
+	return b.PlaceStone(move[0], move[1])
+}
 
+// CanUndo reports whether there is a move to take back
+func (b *Board) CanUndo() bool {
+	return len(b.MoveHistory) > 0
+}
+
+// CanRedo reports whether there is a move taken back that can be replayed
+func (b *Board) CanRedo() bool {
+	return len(b.redo) > 0
+}
+
+// recordMove adds a move by the side to move to the history. Replaying the
+// next move to redo keeps the rest; any other move starts a new line and
+// clears them.
+func (b *Board) recordMove(row, col int) {
+	b.MoveHistory = append(b.MoveHistory, Move{
+		Row:       row,
+		Col:       col,
+		Player:    b.CurrentTurn,
+		Number:    len(b.MoveHistory) + 1,
+		Timestamp: time.Now(),
+	})
+	move := [2]int{row, col}
+	if n := len(b.redo); n > 0 {
+		if b.redo[n-1] == move {
+			b.redo = b.redo[:n-1]
+		} else {
+			b.redo = b.redo[:0]
+		}
+	}
+}
+
+func (b *Board) CheckWin(row, col int) bool {
 	player := b.Grid[row][col]
-	for _, dir := range directions {
-		count := 1
-		// Check forward direction
-		for i := 1; i < WinCondition; i++ {
-			r, c := row+dir[0]*i, col+dir[1]*i
-			if !b.isValidPosition(r, c) || b.Grid[r][c] != player {
-				break
+	if player == Empty {
+		return false
+	}
+	if b.rules.Exact {
+		return b.bits.hasExactRun(row, col, player, b.rules.WinLength)
+	}
+	return b.bits.hasRun(row, col, player, b.rules.WinLength)
+}
+
+// setCell writes a cell of Grid and its bitboard together. The AI uses it to
+// try stones without playing them; these are not seen by the Eval.
+func (b *Board) setCell(row, col int, player Player) {
+	if old := b.Grid[row][col]; old != Empty {
+		b.bits.toggle(row, col, old)
+		b.hash ^= zobristKeys[row][col][old]
+	}
+	b.Grid[row][col] = player
+	if player != Empty {
+		b.bits.toggle(row, col, player)
+		b.hash ^= zobristKeys[row][col][player]
+	}
+}
+
+// CandidateMoves returns the empty positions within radius rows and columns of
+// any stone, in row order, that the opening rule allows. On a board without
+// stones it returns the center. Under gravity it returns the cell each
+// column with room drops a stone to, whatever the radius.
+func (b *Board) CandidateMoves(radius int) [][2]int {
+	radius = max(1, min(radius, MaxCandidateRadius))
+	if b.rules.Gravity {
+		return b.dropMoves()
+	}
+	if b.stones == 0 {
+		return [][2]int{b.centerMove()}
+	}
+
+	var moves [][2]int
+	nearby := &b.nearby[radius-1]
+	for i := 0; i < BoardSize; i++ {
+		for j := 0; j < BoardSize; j++ {
+			if nearby[i][j] > 0 && b.Grid[i][j] == Empty {
+				moves = append(moves, [2]int{i, j})
 			}
-			count++
 		}
-		// Check backward direction
-		for i := 1; i < WinCondition; i++ {
-			r, c := row-dir[0]*i, col-dir[1]*i
-			if !b.isValidPosition(r, c) || b.Grid[r][c] != player {
-				break
+	}
+	if len(moves) == 0 && b.Grid[BoardSize/2][BoardSize/2] == Empty && nearby[BoardSize/2][BoardSize/2] == 0 {
+		// Only passes have been played
+		return [][2]int{{BoardSize / 2, BoardSize / 2}}
+	}
+	return b.openingMoves(moves)
+}
+
+// updateNearby adds delta to the nearby counts around a placed or removed stone
+func (b *Board) updateNearby(row, col, delta int) {
+	for radius := 1; radius <= MaxCandidateRadius; radius++ {
+		nearby := &b.nearby[radius-1]
+		for i := max(0, row-radius); i <= min(BoardSize-1, row+radius); i++ {
+			for j := max(0, col-radius); j <= min(BoardSize-1, col+radius); j++ {
+				nearby[i][j] = uint8(int(nearby[i][j]) + delta)
 			}
-			count++
 		}
-		if count >= WinCondition {
-			return true
+	}
+}
+
+// WinningLine returns the stones in a row through the last move if they form
+// a win, or nil otherwise
+func (b *Board) WinningLine() [][2]int {
+	if len(b.MoveHistory) == 0 {
+		return nil
+	}
+	lastMove := b.MoveHistory[len(b.MoveHistory)-1]
+	if lastMove.IsPass() {
+		return nil
+	}
+	row, col, player := lastMove.Row, lastMove.Col, lastMove.Player
+
+	directions := [][2]int{{1, 0}, {0, 1}, {1, 1}, {1, -1}}
+	for _, dir := range directions {
+		line := [][2]int{{row, col}}
+		for _, sign := range []int{1, -1} {
+			for i := 1; ; i++ {
+				r, c := row+sign*dir[0]*i, col+sign*dir[1]*i
+				if !b.isValidPosition(r, c) || b.Grid[r][c] != player {
+					break
+				}
+				line = append(line, [2]int{r, c})
+			}
+		}
+		if b.rules.wins(len(line)) {
+			return line
 		}
 	}
-	return false
+	return nil
 }
 
 func (b *Board) isValidPosition(row, col int) bool {
-	return row >= 0 && row < BoardSize && col >= 0 && col < BoardSize
+	return inBounds(row, col)
 }
 
 func (b *Board) nextPlayer() Player {
-	if b.CurrentTurn == Black {
-		return White
-	}
-	return Black
+	return b.rules.NextPlayer(b.CurrentTurn)
+}
+
+// Clone returns a deep copy of the board, its stones, history, turn, result
+// and redo moves, that can be played on without affecting the original. The
+// AI writes stones while it thinks, so analysis running alongside the UI
+// should work on a clone rather than the board being shown.
+func (b *Board) Clone() *Board {
+	c := *b
+	c.MoveHistory = append(make([]Move, 0, len(b.MoveHistory)), b.MoveHistory...)
+	c.redo = append([][2]int(nil), b.redo...)
+	c.handicap = append([][2]int(nil), b.handicap...)
+	c.obstacles = append([][2]int(nil), b.obstacles...)
+	return &c
+}
+
+// Eval returns the incremental evaluation state of the board. It follows
+// PlaceStone and Undo only, not stones written to Grid directly.
+func (b *Board) Eval() *Eval {
+	return &b.eval
 }
 
 func (b *Board) GetCurrentPlayer() Player {
@@ -117,5 +335,29 @@ func (b *Board) GetCurrentPlayer() Player {
 }
 
 func (b *Board) IsGameFinished() bool {
-	return b.GameFinished
+	return b.result.Finished()
+}
+
+// IsDraw reports whether the game ended without a winner
+func (b *Board) IsDraw() bool {
+	return b.result.Draw
+}
+
+// HasPasses reports whether any player has passed this game
+func (b *Board) HasPasses() bool {
+	for _, move := range b.MoveHistory {
+		if move.IsPass() {
+			return true
+		}
+	}
+	return false
+}
+
+// trailingPasses counts the passes at the end of the move history
+func (b *Board) trailingPasses() int {
+	passes := 0
+	for i := len(b.MoveHistory) - 1; i >= 0 && b.MoveHistory[i].IsPass(); i-- {
+		passes++
+	}
+	return passes
 }
@@ -0,0 +1,516 @@
+package game
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// BookPlies is the number of moves at the start of a game played from the book
+const BookPlies = 8
+
+//go:embed books/default.json
+var defaultBookData string
+
+// embeddedBook is the built-in book, defaultBook the one new engines use
+var (
+	embeddedBook = mustLoadBook(strings.NewReader(defaultBookData))
+	defaultBook  = embeddedBook
+)
+
+// Book is an opening book mapping positions to candidate replies.
+//
+// Books are stored as JSON with a list of lines, each line being the moves of
+// an opening in coordinate notation starting with Black:
+//
+//	{
+//	  "name": "Default",
+//	  "lines": ["h8 h9 i9 g7", "h8 i9 j10 i10"]
+//	}
+//
+// Every prefix of a line is a book position whose reply is the next move.
+// Replies shared by several lines are chosen proportionally more often.
+// Lookups also match rotated and mirrored versions of book positions.
+//
+// Books may also hold the openings of finished games with their results,
+// added with Learn and saved in a "learned" list:
+//
+//	"learned": [{"moves": "h8 h9 i9 g7", "black": 2, "white": 1, "draws": 0}]
+//
+// Once a reply has been played in a learned game, replies are chosen more
+// often the better the games after them went for the side playing them.
+// Learned replies outside the book lines are only played if they won at
+// least as often as they lost.
+type Book struct {
+	Name    string
+	mu      sync.RWMutex // Guards entries and learned, which Learn and Merge change
+	entries map[string][]bookMove
+	lines   [][][2]int // The lines the book was built from
+	learned []*learnedLine
+
+	// positions indexes the positions of the lines by canonicalHash, with
+	// the first line reaching each
+	positions map[uint64]linePrefix
+}
+
+// linePrefix is the first plies moves of a book line
+type linePrefix struct {
+	line, plies int
+}
+
+type bookMove struct {
+	row    int
+	col    int
+	weight int // Book lines through the reply
+
+	// Learned results for the side playing the reply
+	wins, draws, losses int
+}
+
+// learnedLine is the opening of finished games and their results, indexed
+// by the winner with Empty for draws
+type learnedLine struct {
+	moves   [][2]int
+	results [3]int
+}
+
+type bookFile struct {
+	Name    string            `json:"name"`
+	Lines   []string          `json:"lines"`
+	Learned []learnedLineFile `json:"learned,omitempty"`
+}
+
+type learnedLineFile struct {
+	Moves string `json:"moves"`
+	Black int    `json:"black"`
+	White int    `json:"white"`
+	Draws int    `json:"draws"`
+}
+
+// NewBook returns an empty opening book
+func NewBook(name string) *Book {
+	return &Book{
+		Name:      name,
+		entries:   make(map[string][]bookMove),
+		positions: make(map[uint64]linePrefix),
+	}
+}
+
+// DefaultBook returns the opening book new engines use, the one embedded in
+// the binary unless SetDefaultBook replaced it
+func DefaultBook() *Book {
+	return defaultBook
+}
+
+// SetDefaultBook sets the opening book new engines use, nil for the one
+// embedded in the binary
+func SetDefaultBook(book *Book) {
+	if book == nil {
+		book = embeddedBook
+	}
+	defaultBook = book
+}
+
+// LoadBook reads an opening book in JSON format
+func LoadBook(r io.Reader) (*Book, error) {
+	var file bookFile
+	if err := json.NewDecoder(r).Decode(&file); err != nil {
+		return nil, fmt.Errorf("invalid opening book: %w", err)
+	}
+
+	book := NewBook(file.Name)
+	for i, line := range file.Lines {
+		if err := book.addLine(line); err != nil {
+			return nil, fmt.Errorf("opening book line %d: %w", i+1, err)
+		}
+	}
+	for i, line := range file.Learned {
+		moves, err := parseMoveList(line.Moves)
+		if err == nil {
+			err = book.learn(moves, [3]int{line.Draws, line.Black, line.White})
+		}
+		if err != nil {
+			return nil, fmt.Errorf("opening book learned line %d: %w", i+1, err)
+		}
+	}
+	return book, nil
+}
+
+// LoadBookFile reads an opening book from a JSON file
+func LoadBookFile(path string) (*Book, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return LoadBook(f)
+}
+
+func mustLoadBook(r io.Reader) *Book {
+	book, err := LoadBook(r)
+	if err != nil {
+		panic(err)
+	}
+	return book
+}
+
+func (book *Book) addLine(line string) error {
+	moves, err := parseMoveList(line)
+	if err != nil {
+		return err
+	}
+	return book.addMoves(moves)
+}
+
+func (book *Book) addMoves(moves [][2]int) error {
+	board := NewBoard()
+	for i, move := range moves {
+		book.addReply(positionKey(board, 0), move[0], move[1])
+		if err := board.PlaceStone(move[0], move[1]); err != nil {
+			return fmt.Errorf("%s: %w", FormatMove(move[0], move[1]), err)
+		}
+		if _, ok := book.positions[canonicalHash(board)]; !ok {
+			book.positions[canonicalHash(board)] = linePrefix{len(book.lines), i + 1}
+		}
+	}
+	book.lines = append(book.lines, moves)
+	return nil
+}
+
+func (book *Book) addReply(key string, row, col int) {
+	replies := book.entries[key]
+	for i := range replies {
+		if replies[i].row == row && replies[i].col == col {
+			replies[i].weight++
+			return
+		}
+	}
+	book.entries[key] = append(replies, bookMove{row: row, col: col, weight: 1})
+}
+
+// Save writes the book in JSON format
+func (book *Book) Save(w io.Writer) error {
+	book.mu.RLock()
+	file := bookFile{Name: book.Name, Lines: make([]string, 0, len(book.lines))}
+	for _, line := range book.lines {
+		file.Lines = append(file.Lines, formatMoveList(line))
+	}
+	for _, line := range book.learned {
+		file.Learned = append(file.Learned, learnedLineFile{
+			Moves: formatMoveList(line.moves),
+			Black: line.results[Black],
+			White: line.results[White],
+			Draws: line.results[Empty],
+		})
+	}
+	book.mu.RUnlock()
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(file)
+}
+
+// Clone returns a copy of the book that can learn without changing the
+// original
+func (book *Book) Clone() *Book {
+	clone := NewBook(book.Name)
+	clone.Merge(book)
+	return clone
+}
+
+// Merge adds the lines of other missing from the book, and its learned
+// games
+func (book *Book) Merge(other *Book) {
+	other.mu.RLock()
+	defer other.mu.RUnlock()
+	book.mu.Lock()
+	defer book.mu.Unlock()
+	for _, line := range other.lines {
+		if !slices.ContainsFunc(book.lines, func(l [][2]int) bool { return slices.Equal(l, line) }) {
+			book.addMoves(line) // Already checked when other was built
+		}
+	}
+	for _, line := range other.learned {
+		book.learn(line.moves, line.results)
+	}
+}
+
+// Learn adds the first plies moves of a finished game, at most BookPlies,
+// and its result to the book. winner is Empty for a draw. The opening stops
+// at the first pass.
+func (book *Book) Learn(moves [][2]int, winner Player, plies int) error {
+	plies = min(min(plies, BookPlies), len(moves))
+	if i := slices.Index(moves[:plies], PassMove); i >= 0 {
+		plies = i
+	}
+	if plies == 0 {
+		return nil
+	}
+	var results [3]int
+	results[winner]++
+
+	book.mu.Lock()
+	defer book.mu.Unlock()
+	return book.learn(moves[:plies], results)
+}
+
+// learn adds the results of games opening with moves
+func (book *Book) learn(moves [][2]int, results [3]int) error {
+	board := NewBoard()
+	for _, move := range moves {
+		if err := board.PlaceStone(move[0], move[1]); err != nil {
+			return fmt.Errorf("%s: %w", FormatMove(move[0], move[1]), err)
+		}
+	}
+	board = NewBoard()
+	for _, move := range moves {
+		book.addResult(board, move, results)
+		board.PlaceStone(move[0], move[1])
+	}
+
+	var line *learnedLine
+	for _, l := range book.learned {
+		if slices.Equal(l.moves, moves) {
+			line = l
+			break
+		}
+	}
+	if line == nil {
+		line = &learnedLine{moves: moves}
+		book.learned = append(book.learned, line)
+	}
+	for i := range results {
+		line.results[i] += results[i]
+	}
+	return nil
+}
+
+// addResult counts learned results for the reply move on board. Results go
+// to the same reply seen under another symmetry if the book has it, or next
+// to the other replies of the position, so lookups find them together.
+func (book *Book) addResult(board *Board, move [2]int, results [3]int) {
+	key, reply := positionKey(board, 0), move
+	found := false
+	for symmetry := 0; symmetry < 8 && !found; symmetry++ {
+		k := positionKey(board, symmetry)
+		row, col := transform(move[0], move[1], symmetry)
+		replies := book.entries[k]
+		if slices.ContainsFunc(replies, func(m bookMove) bool { return m.row == row && m.col == col }) {
+			key, reply, found = k, [2]int{row, col}, true
+		} else if len(replies) > 0 && len(book.entries[key]) == 0 {
+			key, reply = k, [2]int{row, col}
+		}
+	}
+
+	replies := book.entries[key]
+	i := slices.IndexFunc(replies, func(m bookMove) bool { return m.row == reply[0] && m.col == reply[1] })
+	if i < 0 {
+		replies = append(replies, bookMove{row: reply[0], col: reply[1]})
+		i = len(replies) - 1
+	}
+	mover, opponent := board.CurrentTurn, board.nextPlayer()
+	replies[i].wins += results[mover]
+	replies[i].losses += results[opponent]
+	replies[i].draws += results[Empty]
+	book.entries[key] = replies
+}
+
+func formatMoveList(moves [][2]int) string {
+	notation := make([]string, len(moves))
+	for i, move := range moves {
+		notation[i] = FormatMove(move[0], move[1])
+	}
+	return strings.Join(notation, " ")
+}
+
+// played reports whether the reply may be played: every book line may, and
+// learned replies when they won at least as often as they lost
+func (m bookMove) played() bool {
+	return m.weight > 0 || m.wins >= m.losses
+}
+
+// replyWeights returns how likely each reply is to be chosen. Replies count
+// once per book line through them; once any has learned results, each is
+// scaled by its learned score, from 0 to 2 with 1 for an even score.
+func replyWeights(replies []bookMove) []int {
+	weights := make([]int, len(replies))
+	learned := slices.ContainsFunc(replies, func(m bookMove) bool { return m.wins+m.draws+m.losses > 0 })
+	for i, reply := range replies {
+		if !learned {
+			weights[i] = reply.weight
+			continue
+		}
+		games := float64(reply.wins + reply.draws + reply.losses)
+		score := (float64(reply.wins) + float64(reply.draws)/2 + 1) / (games + 2)
+		weights[i] = max(int(float64(max(reply.weight, 1))*score*2*1000+0.5), 1)
+	}
+	return weights
+}
+
+// playedReplies returns the replies stored for a position that may be
+// played, with their weights
+func (book *Book) playedReplies(key string) ([]bookMove, []int) {
+	var replies []bookMove
+	for _, reply := range book.entries[key] {
+		if reply.played() {
+			replies = append(replies, reply)
+		}
+	}
+	return replies, replyWeights(replies)
+}
+
+// Lookup returns a book reply for the current position, if there is one
+func (book *Book) Lookup(board *Board) (int, int, bool) {
+	return book.lookup(board, rand.Intn)
+}
+
+// lookup is Lookup choosing among the replies with intn
+func (book *Book) lookup(board *Board, intn func(int) int) (int, int, bool) {
+	book.mu.RLock()
+	defer book.mu.RUnlock()
+	for symmetry := 0; symmetry < 8; symmetry++ {
+		replies, weights := book.playedReplies(positionKey(board, symmetry))
+		if len(replies) == 0 {
+			continue
+		}
+
+		totalWeight := 0
+		for _, weight := range weights {
+			totalWeight += weight
+		}
+		randomWeight := intn(totalWeight)
+		for i, reply := range replies {
+			randomWeight -= weights[i]
+			if randomWeight < 0 {
+				row, col := untransform(reply.row, reply.col, symmetry)
+				if board.Grid[row][col] != Empty {
+					break
+				}
+				return row, col, true
+			}
+		}
+	}
+	return -1, -1, false
+}
+
+// Replies returns every book reply for the current position, under any
+// symmetry, or nil when the position is out of book
+func (book *Book) Replies(board *Board) [][2]int {
+	book.mu.RLock()
+	defer book.mu.RUnlock()
+	var moves [][2]int
+	for symmetry := 0; symmetry < 8; symmetry++ {
+		replies, _ := book.playedReplies(positionKey(board, symmetry))
+		for _, reply := range replies {
+			row, col := untransform(reply.row, reply.col, symmetry)
+			move := [2]int{row, col}
+			if board.Grid[row][col] == Empty && !slices.Contains(moves, move) {
+				moves = append(moves, move)
+			}
+		}
+	}
+	return moves
+}
+
+// BookPosition is where a position stands in the book
+type BookPosition struct {
+	Line       [][2]int // Moves of a book line reaching the position, as the book has them
+	Transposed bool     // The game reached the position by another move order
+}
+
+// Position finds the book line that reaches the position on board, under any
+// symmetry and in any move order, and reports whether the game's own moves
+// took a different order from it. ok is false when the position is out of
+// book, and in games the book does not cover, see bookGame.
+func (book *Book) Position(board *Board) (position BookPosition, ok bool) {
+	if !bookGame(board) {
+		return BookPosition{}, false
+	}
+	book.mu.RLock()
+	defer book.mu.RUnlock()
+	prefix, ok := book.positions[canonicalHash(board)]
+	if !ok || len(board.MoveHistory) != prefix.plies {
+		return BookPosition{}, false
+	}
+	line := book.lines[prefix.line][:prefix.plies]
+	position = BookPosition{Line: slices.Clone(line), Transposed: true}
+	for symmetry := 0; symmetry < 8 && position.Transposed; symmetry++ {
+		position.Transposed = !slices.EqualFunc(board.MoveHistory, line, func(move Move, want [2]int) bool {
+			row, col := transform(move.Row, move.Col, symmetry)
+			return !move.IsPass() && [2]int{row, col} == want
+		})
+	}
+	return position, true
+}
+
+// bookGame reports whether the game on board is played as the book's games
+// are: by the standard rules, without passes, handicap stones or blocked
+// points
+func bookGame(board *Board) bool {
+	return board.rules.Standard() && !board.HasPasses() && board.handicap == nil && board.obstacles == nil
+}
+
+// canonicalHash returns the same Zobrist hash for every rotation and
+// reflection of the stones on board: the smallest of their hashes
+func canonicalHash(board *Board) uint64 {
+	var hashes [8]uint64
+	for i := 0; i < BoardSize; i++ {
+		for j := 0; j < BoardSize; j++ {
+			player := board.Grid[i][j]
+			if player == Empty {
+				continue
+			}
+			for symmetry := range hashes {
+				r, c := transform(i, j, symmetry)
+				hashes[symmetry] ^= zobristKeys[r][c][player]
+			}
+		}
+	}
+	return slices.Min(hashes[:])
+}
+
+// positionKey encodes the board, viewed under the given symmetry, as a string
+func positionKey(board *Board, symmetry int) string {
+	var key [BoardSize * BoardSize]byte
+	for i := 0; i < BoardSize; i++ {
+		for j := 0; j < BoardSize; j++ {
+			r, c := transform(i, j, symmetry)
+			key[r*BoardSize+c] = byte('0' + board.Grid[i][j])
+		}
+	}
+	return string(key[:])
+}
+
+// transform maps a position by one of the eight board symmetries
+func transform(row, col, symmetry int) (int, int) {
+	if symmetry&4 != 0 {
+		row, col = col, row
+	}
+	if symmetry&1 != 0 {
+		row = BoardSize - 1 - row
+	}
+	if symmetry&2 != 0 {
+		col = BoardSize - 1 - col
+	}
+	return row, col
+}
+
+// untransform is the inverse of transform
+func untransform(row, col, symmetry int) (int, int) {
+	if symmetry&2 != 0 {
+		col = BoardSize - 1 - col
+	}
+	if symmetry&1 != 0 {
+		row = BoardSize - 1 - row
+	}
+	if symmetry&4 != 0 {
+		row, col = col, row
+	}
+	return row, col
+}
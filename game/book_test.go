@@ -0,0 +1,38 @@
+package game
+
+import "testing"
+
+func TestBookPositionOnlyInBookGames(t *testing.T) {
+	book := DefaultBook()
+	line := book.lines[0][:3]
+	for _, c := range []struct {
+		name   string
+		rules  Rules
+		setup  func(*Board) error
+		inBook bool
+	}{
+		{name: "standard", rules: StandardRules, inBook: true},
+		{name: "four in a row", rules: Rules{WinLength: 4}},
+		{name: "exact five", rules: Rules{WinLength: WinCondition, Exact: true}},
+		{name: "handicap", rules: StandardRules, setup: func(b *Board) error { return b.SetupHandicap([][2]int{{0, 0}}) }},
+		{name: "blocked point", rules: StandardRules, setup: func(b *Board) error { return b.SetupObstacles([][2]int{{0, 0}}) }},
+	} {
+		board, err := NewBoardWithRules(c.rules)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c.setup != nil {
+			if err := c.setup(board); err != nil {
+				t.Fatalf("%s: %v", c.name, err)
+			}
+		}
+		for _, move := range line {
+			if err := board.PlaceStone(move[0], move[1]); err != nil {
+				t.Fatalf("%s: %v", c.name, err)
+			}
+		}
+		if _, ok := book.Position(board); ok != c.inBook {
+			t.Errorf("%s: in book is %v, want %v", c.name, ok, c.inBook)
+		}
+	}
+}
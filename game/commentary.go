@@ -0,0 +1,85 @@
+package game
+
+// commentSwing is how far the opponent's move must shift the evaluation in
+// their favor for the engine to remark on it
+const commentSwing = 300
+
+// Remarks the engine makes, a few ways of saying each
+var (
+	remarksCombo       = []string{"That's a nasty combination…", "Two threats at once? Well played."}
+	remarksBlockedFour = []string{"Nice block!", "You saw that one coming."}
+	remarksFour        = []string{"A four! I'd better answer that.", "Careful, you're close."}
+	remarksThree       = []string{"I didn't see that three…", "An open three. Noted."}
+	remarksStrongMove  = []string{"Good move.", "Hmm, that's annoying."}
+	remarksOwnCombo    = []string{"Try stopping both of these!", "I think this is it."}
+	remarksOwnBlock    = []string{"Not so fast!", "Not on my watch."}
+	remarksOwnFour     = []string{"Your turn to defend.", "That's four. Your move."}
+)
+
+// Comment returns a short remark the engine makes about the last move on
+// board, or "" when the move is unremarkable. It notices fours and
+// combinations by either side, fours blocked, the opponent's open threes and
+// opponent's moves that swing the evaluation; routine moves such as blocking
+// a three pass without comment.
+func (ai *AI) Comment(board *Board) string {
+	move, ok := board.LastMove()
+	if !ok || move.IsPass() || board.IsGameFinished() {
+		return ""
+	}
+	row, col, player := move.Row, move.Col, move.Player
+	other := Black
+	if player == Black {
+		other = White
+	}
+
+	counts := patternCounts(board, row, col)
+	fours := counts[PatternFour] + counts[PatternOpenFour]
+	combo := counts[PatternOpenFour] > 0 || fours >= 2 || (fours == 1 && counts[PatternThree] > 0)
+
+	// Whether the other side would have made five on the same point
+	board.setCell(row, col, other)
+	blockedFive := board.CheckWin(row, col)
+	board.setCell(row, col, player)
+
+	var remarks []string
+	if player == ai.player {
+		switch {
+		case combo:
+			remarks = remarksOwnCombo
+		case blockedFive:
+			remarks = remarksOwnBlock
+		case fours > 0:
+			remarks = remarksOwnFour
+		}
+	} else {
+		switch {
+		case combo:
+			remarks = remarksCombo
+		case blockedFive:
+			remarks = remarksBlockedFour
+		case fours > 0:
+			remarks = remarksFour
+		case counts[PatternThree] > 0:
+			remarks = remarksThree
+		case ai.evaluationSwing(board) >= commentSwing:
+			remarks = remarksStrongMove
+		}
+	}
+	if remarks == nil {
+		return ""
+	}
+	return remarks[ai.intn(len(remarks))]
+}
+
+// evaluationSwing returns how much the last move improved the evaluation
+// for the side that played it
+func (ai *AI) evaluationSwing(board *Board) int {
+	after := board.eval.Score()
+	before := board.Clone()
+	before.Undo()
+	swing := after - before.eval.Score()
+	if before.CurrentTurn == White {
+		swing = -swing
+	}
+	return swing
+}
@@ -0,0 +1,77 @@
+package game
+
+import "math"
+
+const (
+	// goodMoveMargin is how far below the best score a move may be and still
+	// count as a reasonable choice
+	goodMoveMargin = 150
+
+	difficultyMaxGoodMoves = 6 // Good moves at which choosing is hardest
+	difficultyMaxThreats   = 8 // Threats at which a position is fully tactical
+)
+
+// PositionDifficulty estimates how hard a position is for the side to move
+type PositionDifficulty struct {
+	GoodMoves int     // Moves scoring close to the best one
+	Threats   int     // Moves making a four or open three, for either player
+	Forced    bool    // The side to move must win or block at once
+	Rating    float64 // From 0 for trivial positions to 1 for very hard ones
+}
+
+// Label describes the rating in a word
+func (d PositionDifficulty) Label() string {
+	switch {
+	case d.Rating < 0.33:
+		return "easy"
+	case d.Rating < 0.66:
+		return "moderate"
+	}
+	return "hard"
+}
+
+// RateDifficulty rates the position for the side to move, from how many moves
+// look almost equally good and how many threats are on the board
+func (ai *AI) RateDifficulty(board *Board) PositionDifficulty {
+	var d PositionDifficulty
+	if board.IsGameFinished() {
+		return d
+	}
+
+	mover := *ai
+	mover.player = board.CurrentTurn
+	if mover.findWinningMove(board, mover.player)[0] >= 0 ||
+		mover.findWinningMove(board, mover.getOpponent())[0] >= 0 {
+		d.Forced = true
+		return d
+	}
+
+	candidates := board.CandidateMoves(2)
+	scores := make([]int, len(candidates))
+	best := math.MinInt32
+	for i, move := range candidates {
+		scores[i] = mover.evaluatePositionHard(board, move[0], move[1])
+		best = max(best, scores[i])
+	}
+	for _, score := range scores {
+		if score >= best-goodMoveMargin {
+			d.GoodMoves++
+		}
+	}
+
+	for _, player := range board.rules.Players() {
+		for _, move := range board.CandidateMoves(1) {
+			board.setCell(move[0], move[1], player)
+			counts := patternCounts(board, move[0], move[1])
+			board.setCell(move[0], move[1], Empty)
+			if counts[PatternThree]+counts[PatternFour]+counts[PatternOpenFour] > 0 {
+				d.Threats++
+			}
+		}
+	}
+
+	choice := float64(min(d.GoodMoves, difficultyMaxGoodMoves)-1) / float64(difficultyMaxGoodMoves-1)
+	tactics := float64(min(d.Threats, difficultyMaxThreats)) / float64(difficultyMaxThreats)
+	d.Rating = (choice + tactics) / 2
+	return d
+}
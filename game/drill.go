@@ -0,0 +1,155 @@
+package game
+
+import (
+	"slices"
+	"strings"
+)
+
+// openingPlies is the number of moves that name an opening
+const openingPlies = 2
+
+// Opening is a group of book lines starting with the same moves
+type Opening struct {
+	Name  string
+	Moves [][2]int
+}
+
+// Openings returns the openings of the book, in the order their first lines
+// appear
+func (book *Book) Openings() []Opening {
+	var openings []Opening
+	for _, line := range book.lines {
+		if len(line) <= openingPlies {
+			continue
+		}
+		moves := line[:openingPlies]
+		if slices.ContainsFunc(openings, func(o Opening) bool { return slices.Equal(o.Moves, moves) }) {
+			continue
+		}
+		openings = append(openings, Opening{Name: openingName(moves), Moves: moves})
+	}
+	return openings
+}
+
+// openingName names an opening by how the second stone touches the first
+func openingName(moves [][2]int) string {
+	var notation []string
+	for _, move := range moves {
+		notation = append(notation, FormatMove(move[0], move[1]))
+	}
+	kind := "Opening"
+	if len(moves) == 2 {
+		dRow, dCol := moves[1][0]-moves[0][0], moves[1][1]-moves[0][1]
+		switch {
+		case dRow*dRow+dCol*dCol == 1:
+			kind = "Direct opening"
+		case dRow*dRow == 1 && dCol*dCol == 1:
+			kind = "Indirect opening"
+		}
+	}
+	return kind + " (" + strings.Join(notation, " ") + ")"
+}
+
+// Key identifies the opening, e.g. in a review schedule
+func (o Opening) Key() string {
+	var notation []string
+	for _, move := range o.Moves {
+		notation = append(notation, FormatMove(move[0], move[1]))
+	}
+	return "drill:" + strings.Join(notation, " ")
+}
+
+// Drill trains the moves of one opening: the player plays one color through
+// the book lines of the opening while the engine varies its replies among
+// them, and every move of the player is graded
+type Drill struct {
+	Opening Opening
+	Player  Player // Color the player trains
+	Correct int    // Moves graded correct
+	Graded  int    // Moves graded
+
+	book   *Book // Lines of the opening only
+	grader *AI
+}
+
+// DrillGrade is the grade of one move in a drill
+type DrillGrade struct {
+	Correct bool
+	InBook  bool   // Graded against the book rather than the engine
+	Better  [2]int // A correct move, when the move was not
+}
+
+// NewDrill starts a drill of the opening's lines in book for player
+func NewDrill(book *Book, opening Opening, player Player) *Drill {
+	drill := &Drill{
+		Opening: opening,
+		Player:  player,
+		book:    &Book{Name: opening.Name, entries: make(map[string][]bookMove)},
+		grader:  NewAI(player, Hard),
+	}
+	for _, line := range book.lines {
+		if len(line) > len(opening.Moves) && slices.Equal(line[:len(opening.Moves)], opening.Moves) {
+			drill.book.addMoves(line)
+		}
+	}
+	return drill
+}
+
+// NewAI creates the drill's opponent, which replies from the opening's lines
+// and plays on at Hard level once out of book
+func (d *Drill) NewAI() *AI {
+	opponent := Black
+	if d.Player == Black {
+		opponent = White
+	}
+	ai := NewAI(opponent, Hard)
+	ai.SetBook(d.book)
+	return ai
+}
+
+// Grade grades the player's move at (row, col) before it is played. In book
+// positions only book moves are correct; out of book, any move the engine
+// rates nearly as high as its best is.
+func (d *Drill) Grade(board *Board, row, col int) DrillGrade {
+	grade := DrillGrade{Better: [2]int{-1, -1}}
+	if replies := d.book.Replies(board); len(replies) > 0 {
+		grade.InBook = true
+		grade.Correct = slices.Contains(replies, [2]int{row, col})
+		if !grade.Correct {
+			grade.Better = replies[0]
+		}
+	} else {
+		best, bestScore := [2]int{-1, -1}, 0
+		for _, move := range board.CandidateMoves(2) {
+			score := d.grader.evaluatePositionHard(board, move[0], move[1])
+			if best[0] < 0 || score > bestScore {
+				best, bestScore = move, score
+			}
+		}
+		score := d.grader.evaluatePositionHard(board, row, col)
+		grade.Correct = best[0] < 0 || score >= bestScore-goodMoveMargin
+		if !grade.Correct {
+			grade.Better = best
+		}
+	}
+
+	d.Graded++
+	if grade.Correct {
+		d.Correct++
+	}
+	return grade
+}
+
+// Done reports whether the player has played all their moves of the book
+// depth
+func (d *Drill) Done() bool {
+	return d.Graded >= BookPlies/2
+}
+
+// Accuracy returns the fraction of graded moves that were correct
+func (d *Drill) Accuracy() float64 {
+	if d.Graded == 0 {
+		return 0
+	}
+	return float64(d.Correct) / float64(d.Graded)
+}
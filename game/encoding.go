@@ -0,0 +1,278 @@
+package game
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+)
+
+// encodingVersion is the first byte of an encoded game, for the format to
+// change without old codes being misread
+const encodingVersion = 1
+
+// gridBytes is the size of a packed grid's occupancy bitmap, one bit a point
+const gridBytes = (BoardSize*BoardSize + 7) / 8
+
+const (
+	encodedSetup       = 1 << iota // Setup stones follow the header
+	encodedSwapped                 // White took over the first stone
+	encodedAdjudicated             // The engine's result follows the moves
+)
+
+// EncodePosition packs the stones of grid and the side to move into a few
+// dozen bytes, for keys and sharing where a game's moves are not needed.
+// The side to move comes first, then a bitmap of the occupied points in
+// row order, then two bits for each occupied point in the same order:
+// Black, White, Red or blocked. Equal positions always encode equally.
+func EncodePosition(grid *[BoardSize][BoardSize]Player, toMove Player) []byte {
+	data := []byte{byte(toMove)}
+	return appendGrid(data, grid)
+}
+
+// DecodePosition unpacks a position packed by EncodePosition
+func DecodePosition(data []byte) (*[BoardSize][BoardSize]Player, Player, error) {
+	if len(data) == 0 {
+		return nil, Empty, errors.New("empty position")
+	}
+	toMove := Player(data[0])
+	if toMove < Black || toMove > Red {
+		return nil, Empty, fmt.Errorf("invalid side to move %d", data[0])
+	}
+	grid, rest, err := readGrid(data[1:])
+	if err != nil {
+		return nil, Empty, err
+	}
+	if len(rest) > 0 {
+		return nil, Empty, errors.New("trailing data after the position")
+	}
+	return grid, toMove, nil
+}
+
+// EncodeGame packs a game into bytes: the rules, any handicap stones and
+// blocked points, and the moves played. After a version byte come a byte
+// of rules, a byte of flags, the setup stones packed as by EncodePosition
+// when there are any, and the moves as uvarints, a count followed by each
+// point's row*BoardSize+col+1, 0 for a pass, so a move takes one or two
+// bytes. A result the moves lead to is not stored. An adjudicated result
+// follows the moves as two bytes, the winner, 0 for a draw, and the
+// confidence in 255ths. Handicap stones come back in row order.
+func EncodeGame(board *Board) []byte {
+	rules := board.rules
+	packed := byte(rules.WinLength) | byte(rules.Opening)<<4
+	if rules.Exact {
+		packed |= 1 << 3
+	}
+	if rules.Gravity {
+		packed |= 1 << 6
+	}
+	if rules.ThreePlayers {
+		packed |= 1 << 7
+	}
+
+	var flags byte
+	if len(board.handicap) > 0 || len(board.obstacles) > 0 {
+		flags |= encodedSetup
+	}
+	if board.Swapped() {
+		flags |= encodedSwapped
+	}
+	if board.result.Reason == ReasonAdjudicated {
+		flags |= encodedAdjudicated
+	}
+	data := []byte{encodingVersion, packed, flags}
+	if flags&encodedSetup != 0 {
+		var setup [BoardSize][BoardSize]Player
+		for _, point := range board.handicap {
+			setup[point[0]][point[1]] = Black
+		}
+		for _, point := range board.obstacles {
+			setup[point[0]][point[1]] = Blocked
+		}
+		data = appendGrid(data, &setup)
+	}
+
+	data = binary.AppendUvarint(data, uint64(len(board.MoveHistory)))
+	for _, move := range board.MoveHistory {
+		code := uint64(0)
+		if !move.IsPass() {
+			code = uint64(move.Row*BoardSize + move.Col + 1)
+		}
+		data = binary.AppendUvarint(data, code)
+	}
+	if flags&encodedAdjudicated != 0 {
+		data = append(data, byte(board.result.Winner), byte(math.Round(board.result.Confidence*255)))
+	}
+	return data
+}
+
+// DecodeGame replays a game packed by EncodeGame on a new board
+func DecodeGame(data []byte) (*Board, error) {
+	if len(data) < 3 {
+		return nil, errors.New("encoded game is too short")
+	}
+	if data[0] != encodingVersion {
+		return nil, fmt.Errorf("unknown encoding version %d", data[0])
+	}
+	packed, flags := data[1], data[2]
+	rules := Rules{
+		WinLength:    int(packed & 7),
+		Exact:        packed&(1<<3) != 0,
+		Opening:      OpeningRule(packed >> 4 & 3),
+		Gravity:      packed&(1<<6) != 0,
+		ThreePlayers: packed&(1<<7) != 0,
+	}
+	board, err := NewBoardWithRules(rules)
+	if err != nil {
+		return nil, err
+	}
+
+	data = data[3:]
+	if flags&encodedSetup != 0 {
+		var setup *[BoardSize][BoardSize]Player
+		if setup, data, err = readGrid(data); err != nil {
+			return nil, err
+		}
+		if err := board.setUpStones(setup); err != nil {
+			return nil, err
+		}
+	}
+
+	count, n := binary.Uvarint(data)
+	if n <= 0 || count > uint64(len(data)) {
+		return nil, errors.New("invalid move count")
+	}
+	data = data[n:]
+	for i := uint64(0); i < count; i++ {
+		code, n := binary.Uvarint(data)
+		if n <= 0 || code > BoardSize*BoardSize {
+			return nil, fmt.Errorf("move %d: invalid point", i+1)
+		}
+		data = data[n:]
+		if code == 0 {
+			err = board.Pass()
+		} else {
+			err = board.PlaceStone(int(code-1)/BoardSize, int(code-1)%BoardSize)
+		}
+		if err == nil && i == 0 && flags&encodedSwapped != 0 {
+			err = board.Swap()
+		}
+		if err != nil {
+			return nil, fmt.Errorf("move %d: %w", i+1, err)
+		}
+	}
+	if flags&encodedAdjudicated != 0 {
+		if len(data) < 2 || Player(data[0]) > Red {
+			return nil, errors.New("invalid adjudicated result")
+		}
+		a := Adjudication{Winner: Player(data[0]), Confidence: float64(data[1]) / 255}
+		if err := board.EndByAdjudication(a); err != nil {
+			return nil, fmt.Errorf("adjudicated result: %w", err)
+		}
+		data = data[2:]
+	}
+	if len(data) > 0 {
+		return nil, errors.New("trailing data after the moves")
+	}
+	return board, nil
+}
+
+// FormatGameCode returns the game on board as text for links and QR codes:
+// EncodeGame's bytes in unpadded URL-safe base64
+func FormatGameCode(board *Board) string {
+	return base64.RawURLEncoding.EncodeToString(EncodeGame(board))
+}
+
+// ParseGameCode replays a game from text made by FormatGameCode
+func ParseGameCode(code string) (*Board, error) {
+	data, err := base64.RawURLEncoding.DecodeString(code)
+	if err != nil {
+		return nil, fmt.Errorf("invalid game code: %w", err)
+	}
+	return DecodeGame(data)
+}
+
+// setUpStones places the handicap stones and blocked points of setup on
+// the empty board
+func (b *Board) setUpStones(setup *[BoardSize][BoardSize]Player) error {
+	var handicap, obstacles [][2]int
+	for i := range setup {
+		for j, cell := range setup[i] {
+			switch cell {
+			case Black:
+				handicap = append(handicap, [2]int{i, j})
+			case Blocked:
+				obstacles = append(obstacles, [2]int{i, j})
+			case White, Red:
+				return errors.New("setup stones other than Black handicap stones are not supported")
+			}
+		}
+	}
+	if len(obstacles) > 0 {
+		if err := b.SetupObstacles(obstacles); err != nil {
+			return err
+		}
+	}
+	if len(handicap) > 0 {
+		return b.SetupHandicap(handicap)
+	}
+	return nil
+}
+
+// appendGrid appends the packed stones of grid to data: the occupancy
+// bitmap, then two bits for each occupied point
+func appendGrid(data []byte, grid *[BoardSize][BoardSize]Player) []byte {
+	var occupied [gridBytes]byte
+	var colors []byte
+	stones := 0
+	for i := range grid {
+		for j, cell := range grid[i] {
+			if cell == Empty {
+				continue
+			}
+			k := i*BoardSize + j
+			occupied[k/8] |= 1 << (k % 8)
+			if stones%4 == 0 {
+				colors = append(colors, 0)
+			}
+			colors[stones/4] |= byte(cell-Black) << (2 * (stones % 4))
+			stones++
+		}
+	}
+	data = append(data, occupied[:]...)
+	return append(data, colors...)
+}
+
+// readGrid unpacks a grid packed by appendGrid from the start of data and
+// returns the data after it
+func readGrid(data []byte) (*[BoardSize][BoardSize]Player, []byte, error) {
+	if len(data) < gridBytes {
+		return nil, nil, errors.New("packed stones are too short")
+	}
+	occupied, data := data[:gridBytes], data[gridBytes:]
+	if occupied[gridBytes-1]>>(BoardSize*BoardSize%8) != 0 {
+		return nil, nil, errors.New("packed stones mark points off the board")
+	}
+	stones := 0
+	for _, b := range occupied {
+		stones += bits.OnesCount8(b)
+	}
+	size := (stones + 3) / 4
+	if len(data) < size {
+		return nil, nil, errors.New("packed stones are too short")
+	}
+	colors, data := data[:size], data[size:]
+
+	var grid [BoardSize][BoardSize]Player
+	stone := 0
+	for k := 0; k < BoardSize*BoardSize; k++ {
+		if occupied[k/8]&(1<<(k%8)) == 0 {
+			continue
+		}
+		grid[k/BoardSize][k%BoardSize] = Black + Player(colors[stone/4]>>(2*(stone%4))&3)
+		stone++
+	}
+	return &grid, data, nil
+}
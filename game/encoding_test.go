@@ -0,0 +1,197 @@
+package game_test
+
+import (
+	"bytes"
+	"math"
+	"math/rand"
+	"reflect"
+	"slices"
+	"testing"
+
+	"simple-gomoku/game"
+	"simple-gomoku/internal/gametest"
+)
+
+// sortedPoints returns points in row order, as a decoded game lists them
+func sortedPoints(points [][2]int) [][2]int {
+	sorted := slices.Clone(points)
+	slices.SortFunc(sorted, func(a, b [2]int) int {
+		return (a[0]*game.BoardSize + a[1]) - (b[0]*game.BoardSize + b[1])
+	})
+	return sorted
+}
+
+// checkSameGame fails the test unless got replays want
+func checkSameGame(t *testing.T, got, want *game.Board) {
+	t.Helper()
+	switch {
+	case got.Rules() != want.Rules():
+		t.Fatalf("rules are %v, want %v", got.Rules(), want.Rules())
+	case got.Grid != want.Grid:
+		t.Fatal("stones differ")
+	case !reflect.DeepEqual(got.Positions(), want.Positions()):
+		t.Fatalf("moves are %v, want %v", got.Positions(), want.Positions())
+	case got.CurrentTurn != want.CurrentTurn:
+		t.Fatalf("%v to move, want %v", got.CurrentTurn, want.CurrentTurn)
+	case !sameResult(got.Result(), want.Result()):
+		t.Fatalf("result is %+v, want %+v", got.Result(), want.Result())
+	case got.Swapped() != want.Swapped():
+		t.Fatalf("swapped is %v, want %v", got.Swapped(), want.Swapped())
+	case !reflect.DeepEqual(got.Handicap(), sortedPoints(want.Handicap())):
+		t.Fatalf("handicap is %v, want %v", got.Handicap(), want.Handicap())
+	case !reflect.DeepEqual(got.Obstacles(), sortedPoints(want.Obstacles())):
+		t.Fatalf("blocked points are %v, want %v", got.Obstacles(), want.Obstacles())
+	}
+	for i, move := range got.MoveHistory {
+		if move.Player != want.MoveHistory[i].Player {
+			t.Fatalf("move %d is %v's, want %v's", i+1, move.Player, want.MoveHistory[i].Player)
+		}
+	}
+}
+
+// sameResult reports whether got is want, with an adjudicated result's
+// confidence kept to the 255ths it is encoded in
+func sameResult(got, want game.Result) bool {
+	if math.Abs(got.Confidence-want.Confidence) > 0.5/255 {
+		return false
+	}
+	got.Confidence = want.Confidence
+	return got == want
+}
+
+func TestGameCodeRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, variant := range gametest.Variants {
+		t.Run(variant.Name, func(t *testing.T) {
+			for i := 0; i < 250; i++ {
+				board := variant.RandomGame(t, rng, rng.Intn(game.BoardSize*game.BoardSize))
+				code := game.FormatGameCode(board)
+				decoded, err := game.ParseGameCode(code)
+				if err != nil {
+					t.Fatalf("game %d: %v", i, err)
+				}
+				checkSameGame(t, decoded, board)
+				if again := game.FormatGameCode(decoded); again != code {
+					t.Fatalf("game %d: encoded again as %s, want %s", i, again, code)
+				}
+			}
+		})
+	}
+}
+
+func TestGameCodeKeepsAdjudication(t *testing.T) {
+	rng := rand.New(rand.NewSource(4))
+	winners := []game.Player{game.Empty, game.Black, game.White}
+	for _, variant := range gametest.Variants[:3] { // Standard rules, with and without setup stones
+		t.Run(variant.Name, func(t *testing.T) {
+			for i := 0; i < 50; i++ {
+				board := variant.RandomGame(t, rng, rng.Intn(game.BoardSize*game.BoardSize))
+				if board.IsGameFinished() {
+					continue
+				}
+				a := game.Adjudication{Winner: winners[rng.Intn(len(winners))], Confidence: 0.5 + rng.Float64()/2}
+				if err := board.EndByAdjudication(a); err != nil {
+					t.Fatal(err)
+				}
+				code := game.FormatGameCode(board)
+				decoded, err := game.ParseGameCode(code)
+				if err != nil {
+					t.Fatalf("game %d: %v", i, err)
+				}
+				checkSameGame(t, decoded, board)
+				if again := game.FormatGameCode(decoded); again != code {
+					t.Fatalf("game %d: encoded again as %s, want %s", i, again, code)
+				}
+			}
+		})
+	}
+}
+
+func TestPositionRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	for _, variant := range gametest.Variants {
+		t.Run(variant.Name, func(t *testing.T) {
+			for i := 0; i < 100; i++ {
+				board := variant.RandomGame(t, rng, rng.Intn(game.BoardSize*game.BoardSize))
+				data := game.EncodePosition(&board.Grid, board.CurrentTurn)
+				grid, toMove, err := game.DecodePosition(data)
+				if err != nil {
+					t.Fatalf("position %d: %v", i, err)
+				}
+				if *grid != board.Grid || toMove != board.CurrentTurn {
+					t.Fatalf("position %d did not come back the same", i)
+				}
+				if again := game.EncodePosition(grid, toMove); !bytes.Equal(again, data) {
+					t.Fatalf("position %d encoded again differently", i)
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeGameRejectsBadData(t *testing.T) {
+	board := game.NewBoard()
+	if err := board.PlaceStone(7, 7); err != nil {
+		t.Fatal(err)
+	}
+	data := game.EncodeGame(board)
+	noAdjudication := slices.Clone(data)
+	noAdjudication[2] |= game.EncodedAdjudicated
+	for name, bad := range map[string][]byte{
+		"empty":           nil,
+		"unknown version": append([]byte{game.EncodingVersion + 1}, data[1:]...),
+		"truncated":       data[:len(data)-1],
+		"trailing data":   append(slices.Clone(data), 0),
+		"invalid rules":   {game.EncodingVersion, 0, 0, 0},
+		"no adjudication": noAdjudication,
+	} {
+		if _, err := game.DecodeGame(bad); err == nil {
+			t.Errorf("%s: decoded without an error", name)
+		}
+	}
+}
+
+// fuzzSeeds adds a few encoded games of each variant to the fuzz corpus.
+// Decoding accepts some data EncodeGame never writes, such as unused flag
+// bits, so the fuzz targets check what is decoded survives another round
+// trip rather than that it encodes back to the same bytes.
+func fuzzSeeds(f *testing.F, encode func(*game.Board) []byte) {
+	rng := rand.New(rand.NewSource(3))
+	for _, variant := range gametest.Variants {
+		for i := 0; i < 3; i++ {
+			f.Add(encode(variant.RandomGame(f, rng, rng.Intn(game.BoardSize*game.BoardSize))))
+		}
+	}
+}
+
+func FuzzDecodeGame(f *testing.F) {
+	fuzzSeeds(f, game.EncodeGame)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		board, err := game.DecodeGame(data)
+		if err != nil {
+			return
+		}
+		again, err := game.DecodeGame(game.EncodeGame(board))
+		if err != nil {
+			t.Fatalf("decoded game does not decode once encoded again: %v", err)
+		}
+		checkSameGame(t, again, board)
+	})
+}
+
+func FuzzDecodePosition(f *testing.F) {
+	fuzzSeeds(f, func(board *game.Board) []byte { return game.EncodePosition(&board.Grid, board.CurrentTurn) })
+	f.Fuzz(func(t *testing.T, data []byte) {
+		grid, toMove, err := game.DecodePosition(data)
+		if err != nil {
+			return
+		}
+		again, againToMove, err := game.DecodePosition(game.EncodePosition(grid, toMove))
+		if err != nil {
+			t.Fatalf("decoded position does not decode once encoded again: %v", err)
+		}
+		if *again != *grid || againToMove != toMove {
+			t.Fatal("decoded position changes once encoded again")
+		}
+	})
+}
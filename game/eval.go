@@ -0,0 +1,96 @@
+package game
+
+// evalDirections are the directions a window of winning length runs in
+var evalDirections = [4][2]int{{1, 0}, {0, 1}, {1, 1}, {1, -1}}
+
+// evalWindows is the number of window slots, one per direction and start
+// position; slots whose window would run off the board stay unused
+const evalWindows = len(evalDirections) * BoardSize * BoardSize
+
+// Eval keeps the stone counts of every window of winning length on the
+// board, updated as stones are placed and removed, so the static evaluation
+// does not have to scan the whole board.
+type Eval struct {
+	stones [evalWindows][5]uint8 // stones[w][player] in window w
+	length int                   // Cells in a window, the board's win length
+	exact  bool                  // Only rows of exactly length win
+
+	// lines[player][n] counts the windows holding n stones of player and
+	// none of the others
+	lines [4][MaxWinLength + 1]int
+}
+
+// Lines returns the number of windows holding n stones of player and none of
+// the other players'
+func (e *Eval) Lines(player Player, n int) int {
+	return e.lines[player][n]
+}
+
+// Score returns the window score from Black's perspective, as described for
+// AI.Evaluate
+func (e *Eval) Score() int {
+	score := 0
+	values := &windowValues[e.length]
+	full := e.length
+	if e.exact {
+		// A full window while the game goes on is part of an overline,
+		// which does not win
+		full--
+	}
+	for n := 1; n <= full; n++ {
+		score += values[n] * (e.lines[Black][n] - e.lines[White][n])
+	}
+	return max(-WinScore, min(WinScore, score))
+}
+
+// place adds a stone of player at (row, col)
+func (e *Eval) place(row, col int, player Player) {
+	e.update(row, col, player, 1)
+}
+
+// remove takes the stone of player at (row, col) away
+func (e *Eval) remove(row, col int, player Player) {
+	e.update(row, col, player, -1)
+}
+
+func (e *Eval) update(row, col int, player Player, delta int) {
+	for d, dir := range evalDirections {
+		for k := 0; k < e.length; k++ {
+			// The window starting k cells back along dir
+			i, j := row-dir[0]*k, col-dir[1]*k
+			endRow, endCol := i+dir[0]*(e.length-1), j+dir[1]*(e.length-1)
+			if !inBounds(i, j) || !inBounds(endRow, endCol) {
+				continue
+			}
+			window := &e.stones[(d*BoardSize+i)*BoardSize+j]
+			e.count(window, -1)
+			window[player] = uint8(int(window[player]) + delta)
+			e.count(window, 1)
+		}
+	}
+}
+
+// count adds delta to the line count a window belongs to, if any: that of
+// the only player with stones in it, unless a blocked point spoils it
+func (e *Eval) count(window *[5]uint8, delta int) {
+	if window[Blocked] > 0 {
+		return
+	}
+	owner := Empty
+	for player := Black; player <= Red; player++ {
+		if window[player] == 0 {
+			continue
+		}
+		if owner != Empty {
+			return // Shared, so no one can win here
+		}
+		owner = player
+	}
+	if owner != Empty {
+		e.lines[owner][window[owner]] += delta
+	}
+}
+
+func inBounds(row, col int) bool {
+	return row >= 0 && row < BoardSize && col >= 0 && col < BoardSize
+}
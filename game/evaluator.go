@@ -0,0 +1,38 @@
+package game
+
+// Evaluator scores positions for the Expert and Master search in place of
+// the built-in heuristic
+type Evaluator interface {
+	// Evaluate returns a score for the position from Black's perspective,
+	// between -WinScore and WinScore, as AI.Evaluate does
+	Evaluate(board *Board) int
+}
+
+// PolicyEvaluator is an Evaluator that also rates candidate moves for the
+// side to move, which the search uses to try the likeliest moves first
+type PolicyEvaluator interface {
+	Evaluator
+
+	// Policy returns the probability of each move being the best one
+	Policy(board *Board, moves [][2]int) []float64
+}
+
+// policyWeight scales policy probabilities to the move ordering scores
+const policyWeight = 2000
+
+// defaultEvaluator is used by new Expert and Master engines; nil means the
+// built-in heuristic
+var defaultEvaluator Evaluator
+
+// SetDefaultEvaluator sets the evaluation new Expert and Master engines
+// search with, nil for the built-in heuristic. Engines already created keep
+// theirs, so it is meant to be called once at startup.
+func SetDefaultEvaluator(evaluator Evaluator) {
+	defaultEvaluator = evaluator
+}
+
+// SetEvaluator sets the evaluation the Expert and Master engines search
+// with, nil for the built-in heuristic
+func (ai *AI) SetEvaluator(evaluator Evaluator) {
+	ai.evaluator = evaluator
+}
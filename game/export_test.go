@@ -0,0 +1,7 @@
+package game
+
+// Encoding details the tests in package game_test corrupt data with
+const (
+	EncodingVersion    = encodingVersion
+	EncodedAdjudicated = encodedAdjudicated
+)
@@ -0,0 +1,132 @@
+package game
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Glicko-2 rating parameters
+const (
+	DefaultRating     = 1500
+	DefaultRD         = 350  // Deviation of a new player's rating
+	DefaultVolatility = 0.06 // Expected fluctuation of a player's strength
+	ProvisionalRD     = 110  // Ratings with a larger deviation are provisional
+
+	// EngineRD is the deviation given to the engines' approximate ratings
+	EngineRD = 50
+
+	// RatingPeriod is how long a player may be inactive before the
+	// uncertainty of their rating grows by one step
+	RatingPeriod = 7 * 24 * time.Hour
+
+	glickoScale   = 173.7178 // Converts between the Glicko and Glicko-2 scales
+	glickoTau     = 0.5      // Constrains changes in volatility
+	glickoEpsilon = 1e-6     // Convergence tolerance of the volatility iteration
+)
+
+// Rating is a Glicko-2 rating: an estimate of strength, the deviation of the
+// estimate and the volatility of the player's results
+type Rating struct {
+	Rating     float64   `json:"rating"`
+	RD         float64   `json:"rd"`
+	Volatility float64   `json:"volatility"`
+	Games      int       `json:"games"`
+	Updated    time.Time `json:"updated"` // Time of the last rated game
+}
+
+// NewRating returns the rating of a player with no rated games
+func NewRating() Rating {
+	return Rating{Rating: DefaultRating, RD: DefaultRD, Volatility: DefaultVolatility}
+}
+
+// Provisional reports whether too few games have been played for the rating
+// to be reliable
+func (r Rating) Provisional() bool {
+	return r.RD > ProvisionalRD
+}
+
+func (r Rating) String() string {
+	s := fmt.Sprintf("%.0f ± %.0f", r.Rating, r.RD)
+	if r.Provisional() {
+		s += "?"
+	}
+	return s
+}
+
+// At returns the rating as of now. The deviation grows for every rating
+// period without a game, up to that of a new player.
+func (r Rating) At(now time.Time) Rating {
+	if r.Updated.IsZero() || !now.After(r.Updated) {
+		return r
+	}
+	periods := float64(now.Sub(r.Updated) / RatingPeriod)
+	phi := r.RD / glickoScale
+	phi = math.Sqrt(phi*phi + periods*r.Volatility*r.Volatility)
+	r.RD = math.Min(phi*glickoScale, DefaultRD)
+	return r
+}
+
+// Update returns the rating after a game at time now against an opponent
+// with the given rating and deviation. score is 1 for a win, 0.5 for a draw
+// and 0 for a loss.
+func (r Rating) Update(opponent, opponentRD, score float64, now time.Time) Rating {
+	r = r.At(now)
+	mu := (r.Rating - DefaultRating) / glickoScale
+	phi := r.RD / glickoScale
+	muOpponent := (opponent - DefaultRating) / glickoScale
+	phiOpponent := opponentRD / glickoScale
+
+	g := 1 / math.Sqrt(1+3*phiOpponent*phiOpponent/(math.Pi*math.Pi))
+	expected := 1 / (1 + math.Exp(-g*(mu-muOpponent)))
+	v := 1 / (g * g * expected * (1 - expected))
+	delta := v * g * (score - expected)
+
+	sigma := newVolatility(phi, r.Volatility, v, delta)
+	phiStar := math.Sqrt(phi*phi + sigma*sigma)
+	phi = 1 / math.Sqrt(1/(phiStar*phiStar)+1/v)
+	mu += phi * phi * g * (score - expected)
+
+	return Rating{
+		Rating:     mu*glickoScale + DefaultRating,
+		RD:         math.Min(phi*glickoScale, DefaultRD),
+		Volatility: sigma,
+		Games:      r.Games + 1,
+		Updated:    now,
+	}
+}
+
+// newVolatility solves for the new volatility with the Illinois algorithm,
+// step 5 of Glickman's description of Glicko-2
+func newVolatility(phi, sigma, v, delta float64) float64 {
+	a := math.Log(sigma * sigma)
+	f := func(x float64) float64 {
+		ex := math.Exp(x)
+		d := phi*phi + v + ex
+		return ex*(delta*delta-phi*phi-v-ex)/(2*d*d) - (x-a)/(glickoTau*glickoTau)
+	}
+
+	A := a
+	var B float64
+	if delta*delta > phi*phi+v {
+		B = math.Log(delta*delta - phi*phi - v)
+	} else {
+		k := 1.0
+		for f(a-k*glickoTau) < 0 {
+			k++
+		}
+		B = a - k*glickoTau
+	}
+	fA, fB := f(A), f(B)
+	for math.Abs(B-A) > glickoEpsilon {
+		C := A + (A-B)*fA/(fB-fA)
+		fC := f(C)
+		if fC*fB <= 0 {
+			A, fA = B, fB
+		} else {
+			fA /= 2
+		}
+		B, fB = C, fC
+	}
+	return math.Exp(A / 2)
+}
@@ -0,0 +1,65 @@
+package game
+
+// DropRow returns the row a stone dropped in col comes to rest on under
+// gravity: just above the highest stone or blocked point of the column, or
+// the bottom row of an empty one. It is -1 when the column is full.
+func (b *Board) DropRow(col int) int {
+	for row := 0; row < BoardSize; row++ {
+		if b.Grid[row][col] != Empty {
+			return row - 1
+		}
+	}
+	return BoardSize - 1
+}
+
+// dropMoves returns the cell every column with room drops a stone to
+func (b *Board) dropMoves() [][2]int {
+	var moves [][2]int
+	for col := 0; col < BoardSize; col++ {
+		if row := b.DropRow(col); row >= 0 {
+			moves = append(moves, [2]int{row, col})
+		}
+	}
+	return moves
+}
+
+// centerMove returns the first move engines play on a board without stones:
+// the center, or under gravity where a stone dropped down the middle column
+// lands. Blocked points push it to the nearest point that is free.
+func (b *Board) centerMove() [2]int {
+	center := BoardSize / 2
+	if b.rules.Gravity {
+		for distance := 0; distance <= center; distance++ {
+			for _, col := range []int{center - distance, center + distance} {
+				if row := b.DropRow(col); row >= 0 {
+					return [2]int{row, col}
+				}
+			}
+		}
+		return PassMove
+	}
+	for distance := 0; distance <= center; distance++ {
+		for i := center - distance; i <= center+distance; i++ {
+			for j := center - distance; j <= center+distance; j++ {
+				if b.Grid[i][j] == Empty {
+					return [2]int{i, j}
+				}
+			}
+		}
+	}
+	return PassMove
+}
+
+// floatingStones counts the stones of grid with an empty cell below them.
+// Blocked points stay where they are set, so they do not count.
+func floatingStones(grid [BoardSize][BoardSize]Player) int {
+	floating := 0
+	for row := 0; row < BoardSize-1; row++ {
+		for col := 0; col < BoardSize; col++ {
+			if cell := grid[row][col]; cell != Empty && cell != Blocked && grid[row+1][col] == Empty {
+				floating++
+			}
+		}
+	}
+	return floating
+}
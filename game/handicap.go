@@ -0,0 +1,69 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MaxHandicap is the most handicap stones a game may start with, one on
+// each star point
+const MaxHandicap = 5
+
+// HandicapPoints returns the usual points for n handicap stones: the center
+// first, then the corner star points, opposite corners in pairs
+func HandicapPoints(n int) [][2]int {
+	center, near, far := BoardSize/2, 3, BoardSize-4
+	points := [][2]int{{center, center}, {near, near}, {far, far}, {near, far}, {far, near}}
+	return points[:max(0, min(n, len(points)))]
+}
+
+// SetupHandicap places Black handicap stones on points of an empty board,
+// after which White moves first. The stones are not moves: they stay out of
+// MoveHistory, so Undo never takes them back, and are listed by Handicap.
+// Handicap games use a free opening, and the stones must not already win.
+func (b *Board) SetupHandicap(points [][2]int) error {
+	switch {
+	case len(b.MoveHistory) > 0 || b.stones > 0:
+		return errors.New("handicap stones go on an empty board")
+	case b.rules.Opening != FreeOpening:
+		return fmt.Errorf("handicap stones cannot be used with the %s", b.rules.Opening)
+	case b.rules.Gravity:
+		return errors.New("handicap stones cannot be used with gravity")
+	case b.rules.ThreePlayers:
+		return errors.New("handicap stones cannot be used in three-player games")
+	case len(b.obstacles) > 0:
+		return errors.New("handicap stones cannot be used with blocked points")
+	case len(points) == 0 || len(points) > MaxHandicap:
+		return fmt.Errorf("a handicap is 1 to %d stones, not %d", MaxHandicap, len(points))
+	}
+	var grid [BoardSize][BoardSize]Player
+	for _, point := range points {
+		row, col := point[0], point[1]
+		if !b.isValidPosition(row, col) {
+			return fmt.Errorf("handicap point %s is off the board", FormatMove(row, col))
+		}
+		if grid[row][col] != Empty {
+			return fmt.Errorf("handicap point %s is given twice", FormatMove(row, col))
+		}
+		grid[row][col] = Black
+	}
+	if len(winningStones(grid, Black, b.rules)) > 0 {
+		return errors.New("the handicap stones must not make a winning row")
+	}
+
+	for _, point := range points {
+		b.setCell(point[0], point[1], Black)
+		b.stones++
+		b.updateNearby(point[0], point[1], 1)
+		b.eval.place(point[0], point[1], Black)
+	}
+	b.handicap = append([][2]int(nil), points...)
+	b.CurrentTurn = White
+	return nil
+}
+
+// Handicap returns the points of Black's handicap stones, nil for a game
+// without a handicap
+func (b *Board) Handicap() [][2]int {
+	return b.handicap
+}
@@ -0,0 +1,42 @@
+package game
+
+import "math/rand"
+
+// zobristKeys holds a random key for each player's stone on each position.
+// The hash of a position is the XOR of the keys of its stones.
+var zobristKeys = func() [BoardSize][BoardSize][5]uint64 {
+	var keys [BoardSize][BoardSize][5]uint64
+	rng := rand.New(rand.NewSource(20240501)) // Fixed so hashes are stable
+	for i := range keys {
+		for j := range keys[i] {
+			keys[i][j][Black] = rng.Uint64()
+			keys[i][j][White] = rng.Uint64()
+		}
+	}
+	for i := range keys {
+		for j := range keys[i] {
+			keys[i][j][Red] = rng.Uint64() // Drawn last, so two-player hashes are unchanged
+		}
+	}
+	for i := range keys {
+		for j := range keys[i] {
+			keys[i][j][Blocked] = rng.Uint64() // Likewise after Red
+		}
+	}
+	return keys
+}()
+
+// Hash returns the Zobrist hash of the stones on the board, kept up to date
+// as stones are placed and undone. Boards with the same stones hash the same
+// whatever order they were played in. Like Eval, it does not follow stones
+// written to Grid directly. Hashes are the same from run to run, so they
+// may be stored.
+func (b *Board) Hash() uint64 {
+	return b.hash
+}
+
+// Equal reports whether b and other hold the same stones with the same side
+// to move, however they were reached
+func (b *Board) Equal(other *Board) bool {
+	return b.hash == other.hash && b.CurrentTurn == other.CurrentTurn && b.Grid == other.Grid
+}
@@ -0,0 +1,76 @@
+package game
+
+import (
+	"bytes"
+	_ "embed"
+)
+
+// ladderWeightsData are the weights the ladder levels score moves with, a
+// copy of the built-in weights when the ladder was rated. Tuning the
+// built-in weights must not change the levels.
+//
+//go:embed weights/ladder.json
+var ladderWeightsData []byte
+
+var ladderWeights = mustLoadWeights(bytes.NewReader(ladderWeightsData))
+
+// LadderLevel is a predefined engine configuration with an approximate
+// rating. Its settings are given in full rather than taken from the
+// difficulty's defaults, so a level plays the same way when they change.
+type LadderLevel struct {
+	Name       string
+	Elo        int
+	Difficulty Difficulty
+	ErrorRate  float64 // Chance of a mistake, see AI.SetErrorRate
+	Weights    Weights // Move scores, see AI.SetWeights
+	Playouts   int     // Only used by MonteCarlo levels
+	Depth      int     // Search plies, only used by Expert and Master levels
+}
+
+// Ladder lists the built-in levels from weakest to strongest. The parameters
+// are frozen so a level plays the same way across releases.
+var Ladder = []LadderLevel{
+	{Name: "Rookie", Elo: 800, Difficulty: Easy, ErrorRate: 0.35, Weights: ladderWeights},
+	{Name: "Amateur", Elo: 1100, Difficulty: Medium, Weights: ladderWeights},
+	{Name: "Club", Elo: 1400, Difficulty: Hard, Weights: ladderWeights},
+	{Name: "Contender", Elo: 1800, Difficulty: MonteCarlo, Weights: ladderWeights, Playouts: 2000},
+	{Name: "Champion", Elo: 2200, Difficulty: MonteCarlo, Weights: ladderWeights, Playouts: 8000},
+}
+
+// NewAI creates an AI configured for this ladder level
+func (l LadderLevel) NewAI(player Player) *AI {
+	ai := NewAI(player, l.Difficulty)
+	ai.SetErrorRate(l.ErrorRate)
+	ai.SetWeights(l.Weights)
+	if l.Playouts > 0 {
+		ai.SetPlayouts(l.Playouts)
+	}
+	if l.Depth > 0 {
+		ai.SetDepth(l.Depth)
+	}
+	ai.elo = l.Elo
+	return ai
+}
+
+// plays reports whether ai has the level's settings
+func (l LadderLevel) plays(ai *AI) bool {
+	return ai.difficulty == l.Difficulty && ai.errorRate == l.ErrorRate && ai.weights == l.Weights &&
+		(l.Playouts == 0 || ai.playouts == l.Playouts) && (l.Depth == 0 || ai.depth == l.Depth)
+}
+
+// ladderElo returns the rating of the ladder level the engine's settings
+// match, 0 if none does
+func (ai *AI) ladderElo() int {
+	for _, level := range Ladder {
+		if level.plays(ai) {
+			return level.Elo
+		}
+	}
+	return 0
+}
+
+// Elo returns the engine's approximate rating, 0 if it is not known. Changing
+// the engine's settings does not change it.
+func (ai *AI) Elo() int {
+	return ai.elo
+}
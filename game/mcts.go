@@ -0,0 +1,144 @@
+package game
+
+import (
+	"context"
+	"math"
+)
+
+const (
+	DefaultPlayouts = 2000
+
+	mctsExploration  = 1.4 // UCT exploration constant
+	mctsRolloutDepth = 40  // Maximum moves played in a single rollout
+)
+
+type mctsNode struct {
+	move     [2]int
+	player   Player // Player who made the move leading to this node
+	parent   *mctsNode
+	children []*mctsNode
+	untried  [][2]int
+	visits   int
+	wins     float64
+}
+
+func newMCTSNode(parent *mctsNode, move [2]int, player Player, board *Board) *mctsNode {
+	node := &mctsNode{
+		move:   move,
+		player: player,
+		parent: parent,
+	}
+	if !board.IsGameFinished() {
+		node.untried = board.CandidateMoves(2)
+	}
+	return node
+}
+
+// selectChild picks the child with the highest UCT value
+func (n *mctsNode) selectChild() *mctsNode {
+	var best *mctsNode
+	bestValue := math.Inf(-1)
+	logVisits := math.Log(float64(n.visits))
+	for _, child := range n.children {
+		value := child.wins/float64(child.visits) +
+			mctsExploration*math.Sqrt(logVisits/float64(child.visits))
+		if value > bestValue {
+			bestValue = value
+			best = child
+		}
+	}
+	return best
+}
+
+// Monte Carlo mode: Uses tree search with random playouts instead of heuristics
+func (ai *AI) makeMCTSMove(ctx context.Context, board *Board) (int, int) {
+	// 1. Check if AI can win
+	if move := ai.findWinningMove(board, ai.player); move[0] >= 0 {
+		return move[0], move[1]
+	}
+
+	// 2. Check if need to block opponent's winning move
+	if move := ai.findWinningMove(board, ai.getOpponent()); move[0] >= 0 {
+		return move[0], move[1]
+	}
+
+	// If no stones on board, play center
+	if board.stones == 0 {
+		move := board.centerMove()
+		return move[0], move[1]
+	}
+
+	// 3. Run playouts from the current position
+	root := newMCTSNode(nil, [2]int{-1, -1}, ai.getOpponent(), board)
+	for i := 0; i < ai.playouts; i++ {
+		if ctx.Err() != nil {
+			return -1, -1
+		}
+		sim := board.Clone()
+		node := root
+
+		// Selection
+		for len(node.untried) == 0 && len(node.children) > 0 {
+			node = node.selectChild()
+			sim.PlaceStone(node.move[0], node.move[1])
+		}
+
+		// Expansion
+		if len(node.untried) > 0 {
+			k := ai.intn(len(node.untried))
+			move := node.untried[k]
+			node.untried[k] = node.untried[len(node.untried)-1]
+			node.untried = node.untried[:len(node.untried)-1]
+
+			mover := sim.CurrentTurn
+			sim.PlaceStone(move[0], move[1])
+			child := newMCTSNode(node, move, mover, sim)
+			node.children = append(node.children, child)
+			node = child
+		}
+
+		// Simulation
+		winner := rollout(sim, ai.intn)
+
+		// Backpropagation
+		for n := node; n != nil; n = n.parent {
+			n.visits++
+			if winner == n.player {
+				n.wins++
+			} else if winner == Empty {
+				n.wins += 0.5
+			}
+		}
+	}
+
+	// 4. Play the most visited move
+	var best *mctsNode
+	for _, child := range root.children {
+		if best == nil || child.visits > best.visits {
+			best = child
+		}
+	}
+	if best != nil {
+		return best.move[0], best.move[1]
+	}
+
+	// If the tree is empty, use hard mode strategy
+	return ai.makeHardMove(ctx, board)
+}
+
+// rollout plays random moves near existing stones, chosen with intn, and
+// returns the winner, or Empty if the rollout ends without one
+func rollout(board *Board, intn func(int) int) Player {
+	for depth := 0; !board.IsGameFinished(); depth++ {
+		if depth == mctsRolloutDepth {
+			return Empty
+		}
+		moves := board.CandidateMoves(1)
+		if len(moves) == 0 {
+			return Empty
+		}
+		move := moves[intn(len(moves))]
+		board.PlaceStone(move[0], move[1])
+	}
+	return board.Result().Winner
+}
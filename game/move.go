@@ -0,0 +1,43 @@
+package game
+
+import "time"
+
+// Move is a move recorded in a game's history: a stone placed, or a pass
+type Move struct {
+	Row, Col  int // PassMove's for a pass
+	Player    Player
+	Number    int       // Position in the game, 1 for the first move
+	Timestamp time.Time // When the move was played
+}
+
+// Pos returns the position of the move, PassMove for a pass
+func (m Move) Pos() [2]int {
+	return [2]int{m.Row, m.Col}
+}
+
+// IsPass reports whether the move is a pass
+func (m Move) IsPass() bool {
+	return m.Pos() == PassMove
+}
+
+// String returns the move in coordinate notation, see FormatMove
+func (m Move) String() string {
+	return FormatMove(m.Row, m.Col)
+}
+
+// LastMove returns the last move of the game, ok is false before the first
+func (b *Board) LastMove() (move Move, ok bool) {
+	if len(b.MoveHistory) == 0 {
+		return Move{}, false
+	}
+	return b.MoveHistory[len(b.MoveHistory)-1], true
+}
+
+// Positions returns the positions of the moves played, PassMove for passes
+func (b *Board) Positions() [][2]int {
+	positions := make([][2]int, len(b.MoveHistory))
+	for i, move := range b.MoveHistory {
+		positions[i] = move.Pos()
+	}
+	return positions
+}
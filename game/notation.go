@@ -0,0 +1,39 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FormatMove returns the coordinate notation of a position, e.g. "h8" for the
+// center. Columns are lettered from the left and rows numbered from the bottom.
+// A pass is written as "pass".
+func FormatMove(row, col int) string {
+	if [2]int{row, col} == PassMove {
+		return "pass"
+	}
+	return fmt.Sprintf("%c%d", 'a'+col, BoardSize-row)
+}
+
+// ParseMove parses coordinate notation produced by FormatMove
+func ParseMove(s string) (int, int, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "pass" {
+		return PassMove[0], PassMove[1], nil
+	}
+	if len(s) < 2 {
+		return -1, -1, errors.New("invalid move notation")
+	}
+	col := int(s[0] - 'a')
+	number, err := strconv.Atoi(s[1:])
+	if err != nil {
+		return -1, -1, errors.New("invalid move notation")
+	}
+	row := BoardSize - number
+	if row < 0 || row >= BoardSize || col < 0 || col >= BoardSize {
+		return -1, -1, errors.New("position out of bounds")
+	}
+	return row, col, nil
+}
@@ -0,0 +1,95 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+)
+
+// MaxObstacles is the most blocked points a board may have
+const MaxObstacles = 40
+
+// obstacleMargin is how far from the center random blocked points are kept,
+// in rows or columns, so the usual first moves stay open
+const obstacleMargin = 2
+
+// RandomObstacles returns n points to block, drawn from seed: the same seed
+// gives the same points. They are kept clear of the center.
+func RandomObstacles(n int, seed int64) [][2]int {
+	n = max(0, min(n, MaxObstacles))
+	center := BoardSize / 2
+	rng := rand.New(rand.NewSource(seed))
+	var points [][2]int
+	for _, cell := range rng.Perm(BoardSize * BoardSize) {
+		if len(points) == n {
+			break
+		}
+		row, col := cell/BoardSize, cell%BoardSize
+		if max(abs(row-center), abs(col-center)) > obstacleMargin {
+			points = append(points, [2]int{row, col})
+		}
+	}
+	return points
+}
+
+// SetupObstacles blocks points of an empty board, which no player may then
+// play. Like handicap stones they are not moves: they stay out of
+// MoveHistory and are listed by Obstacles. A blocked point breaks any row
+// through it, and the board is full once every other point has a stone, or
+// under gravity once no column has room above its top stone or blocked point.
+// Under a Pro opening the center must stay open.
+func (b *Board) SetupObstacles(points [][2]int) error {
+	switch {
+	case len(b.MoveHistory) > 0 || b.stones > 0:
+		return errors.New("blocked points go on an empty board")
+	case len(b.obstacles) > 0:
+		return errors.New("the board already has blocked points")
+	case len(points) == 0 || len(points) > MaxObstacles:
+		return fmt.Errorf("a board has 1 to %d blocked points, not %d", MaxObstacles, len(points))
+	}
+	var grid [BoardSize][BoardSize]bool
+	for _, point := range points {
+		row, col := point[0], point[1]
+		if !b.isValidPosition(row, col) {
+			return fmt.Errorf("blocked point %s is off the board", FormatMove(row, col))
+		}
+		if grid[row][col] {
+			return fmt.Errorf("blocked point %s is given twice", FormatMove(row, col))
+		}
+		grid[row][col] = true
+	}
+	if center := BoardSize / 2; grid[center][center] && b.rules.ThirdMoveDistance() > 0 {
+		return fmt.Errorf("the center cannot be blocked under the %s", b.rules.Opening)
+	}
+
+	for _, point := range points {
+		b.setCell(point[0], point[1], Blocked)
+		b.eval.place(point[0], point[1], Blocked)
+	}
+	b.obstacles = append([][2]int(nil), points...)
+	return nil
+}
+
+// full reports whether no point is left to play: every point is taken, or
+// under gravity every column is filled up to its top or a blocked point,
+// since stones cannot drop past one to the points below
+func (b *Board) full() bool {
+	if b.stones+len(b.obstacles) == BoardSize*BoardSize {
+		return true
+	}
+	if !b.rules.Gravity || len(b.obstacles) == 0 {
+		return false
+	}
+	for col := 0; col < BoardSize; col++ {
+		if b.DropRow(col) >= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Obstacles returns the blocked points of the board, nil for a board
+// without any
+func (b *Board) Obstacles() [][2]int {
+	return b.obstacles
+}
@@ -0,0 +1,151 @@
+//go:build onnx
+
+package game
+
+import (
+	"math"
+	"sync"
+
+	ort "github.com/yalue/onnxruntime_go"
+)
+
+// onnxValueScale converts the network's value, from -1 to 1, to the scale of
+// Evaluate; it stays well below WinScore so real wins always rank higher
+const onnxValueScale = WinScore / 10
+
+// LoadONNXEvaluator loads the ONNX model at path, see NewONNXEvaluator.
+// Builds without the onnx tag return an error instead.
+func LoadONNXEvaluator(path, library string) (Evaluator, error) {
+	evaluator, err := NewONNXEvaluator(path, library)
+	if err != nil {
+		return nil, err
+	}
+	return evaluator, nil
+}
+
+// ONNXEvaluator evaluates positions with a policy/value network in ONNX
+// format. The network takes an input "board" of shape 1x3x15x15 holding the
+// stones of the side to move, the stones of the other side and the empty
+// cells as 0/1 planes. It returns "policy", 1x225 move logits in row order,
+// and "value", 1x1 from -1 (lost) to 1 (won) for the side to move.
+type ONNXEvaluator struct {
+	mu      sync.Mutex // The session and its tensors serve one position at a time
+	session *ort.AdvancedSession
+	input   *ort.Tensor[float32]
+	policy  *ort.Tensor[float32]
+	value   *ort.Tensor[float32]
+}
+
+// NewONNXEvaluator loads the model at path. library is the path of the
+// onnxruntime shared library, or empty for the system default.
+func NewONNXEvaluator(path, library string) (*ONNXEvaluator, error) {
+	if !ort.IsInitialized() {
+		if library != "" {
+			ort.SetSharedLibraryPath(library)
+		}
+		if err := ort.InitializeEnvironment(); err != nil {
+			return nil, err
+		}
+	}
+
+	e := &ONNXEvaluator{}
+	var err error
+	if e.input, err = ort.NewEmptyTensor[float32](ort.NewShape(1, 3, BoardSize, BoardSize)); err != nil {
+		return nil, err
+	}
+	if e.policy, err = ort.NewEmptyTensor[float32](ort.NewShape(1, BoardSize*BoardSize)); err != nil {
+		e.Close()
+		return nil, err
+	}
+	if e.value, err = ort.NewEmptyTensor[float32](ort.NewShape(1, 1)); err != nil {
+		e.Close()
+		return nil, err
+	}
+	e.session, err = ort.NewAdvancedSession(path,
+		[]string{"board"}, []string{"policy", "value"},
+		[]ort.Value{e.input}, []ort.Value{e.policy, e.value}, nil)
+	if err != nil {
+		e.Close()
+		return nil, err
+	}
+	return e, nil
+}
+
+// Close releases the session and its tensors
+func (e *ONNXEvaluator) Close() error {
+	if e.session != nil {
+		e.session.Destroy()
+	}
+	for _, tensor := range []*ort.Tensor[float32]{e.input, e.policy, e.value} {
+		if tensor != nil {
+			tensor.Destroy()
+		}
+	}
+	return nil
+}
+
+// run feeds the board to the network; the caller must hold e.mu
+func (e *ONNXEvaluator) run(board *Board) error {
+	planes := e.input.GetData()
+	cells := BoardSize * BoardSize
+	mover := board.CurrentTurn
+	for i := 0; i < BoardSize; i++ {
+		for j := 0; j < BoardSize; j++ {
+			cell := i*BoardSize + j
+			planes[cell], planes[cells+cell], planes[2*cells+cell] = 0, 0, 0
+			switch board.Grid[i][j] {
+			case mover:
+				planes[cell] = 1
+			case Empty:
+				planes[2*cells+cell] = 1
+			default:
+				planes[cells+cell] = 1
+			}
+		}
+	}
+	return e.session.Run()
+}
+
+// Evaluate returns the network's value of the position from Black's
+// perspective, or 0 if the network fails
+func (e *ONNXEvaluator) Evaluate(board *Board) int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if err := e.run(board); err != nil {
+		return 0
+	}
+	score := int(e.value.GetData()[0] * onnxValueScale)
+	if board.CurrentTurn == White {
+		score = -score
+	}
+	return score
+}
+
+// Policy returns the network's probabilities for the given moves, normalised
+// over those moves, or equal ones if the network fails
+func (e *ONNXEvaluator) Policy(board *Board, moves [][2]int) []float64 {
+	probabilities := make([]float64, len(moves))
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if err := e.run(board); err != nil {
+		for i := range probabilities {
+			probabilities[i] = 1 / float64(len(moves))
+		}
+		return probabilities
+	}
+
+	logits := e.policy.GetData()
+	highest := math.Inf(-1)
+	for _, move := range moves {
+		highest = math.Max(highest, float64(logits[move[0]*BoardSize+move[1]]))
+	}
+	total := 0.0
+	for i, move := range moves {
+		probabilities[i] = math.Exp(float64(logits[move[0]*BoardSize+move[1]]) - highest)
+		total += probabilities[i]
+	}
+	for i := range probabilities {
+		probabilities[i] /= total
+	}
+	return probabilities
+}
@@ -0,0 +1,11 @@
+//go:build !onnx
+
+package game
+
+import "errors"
+
+// LoadONNXEvaluator loads the ONNX model at path. This build has no ONNX
+// support, see the onnx build tag, so it always returns an error.
+func LoadONNXEvaluator(path, library string) (Evaluator, error) {
+	return nil, errors.New("built without ONNX support, rebuild with -tags onnx")
+}
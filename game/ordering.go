@@ -0,0 +1,97 @@
+package game
+
+import "sort"
+
+const (
+	searchBreadth = 12 // Candidate moves searched at each node, best first
+
+	hashMoveBonus   = 1 << 24 // Puts the best move from an earlier search first
+	killerMoveBonus = 1 << 20 // Puts recent cutoff moves at this ply next
+	historyLimit    = 2000    // Cap on the history bonus, so it only breaks near ties
+
+	maxHashMoves = 1 << 18 // Entries kept before the hash move table is cleared
+)
+
+// moveOrdering remembers which moves did well earlier in a search, so they
+// are tried first and alpha-beta prunes more:
+//   - the hash move, the best move found for the same position before
+//   - killer moves, which caused a cutoff at the same ply in a sibling position
+//   - the history table, counting cutoffs by position across the whole search
+type moveOrdering struct {
+	hashMoves map[uint64][2]int
+	killers   [][2][2]int // Two killer moves per ply
+	history   [BoardSize][BoardSize]int
+}
+
+func newMoveOrdering() *moveOrdering {
+	return &moveOrdering{hashMoves: make(map[uint64][2]int)}
+}
+
+// storeBest records the best move found for a position
+func (o *moveOrdering) storeBest(hash uint64, move [2]int) {
+	if len(o.hashMoves) >= maxHashMoves {
+		clear(o.hashMoves)
+	}
+	o.hashMoves[hash] = move
+}
+
+// storeCutoff records a move that caused a beta cutoff at the given ply,
+// weighting the history by the depth searched below it
+func (o *moveOrdering) storeCutoff(move [2]int, depth, ply int) {
+	for len(o.killers) <= ply {
+		o.killers = append(o.killers, [2][2]int{{-1, -1}, {-1, -1}})
+	}
+	if o.killers[ply][0] != move {
+		o.killers[ply][1] = o.killers[ply][0]
+		o.killers[ply][0] = move
+	}
+	o.history[move[0]][move[1]] += depth * depth
+}
+
+// order returns the most promising candidate moves for the side to move, best
+// first: the hash move, then killers, then by static score plus history and
+// the evaluator's policy, if it has one
+func (o *moveOrdering) order(ai *AI, board *Board, hash uint64, ply int) [][2]int {
+	mover := *ai
+	mover.player = board.CurrentTurn
+
+	hashMove, hasHashMove := o.hashMoves[hash]
+	var killers [2][2]int
+	if ply < len(o.killers) {
+		killers = o.killers[ply]
+	}
+
+	candidates := board.CandidateMoves(2)
+	var policy []float64
+	if evaluator, ok := ai.evaluator.(PolicyEvaluator); ok {
+		policy = evaluator.Policy(board, candidates)
+	}
+	scores := make([]int, len(candidates))
+	for i, move := range candidates {
+		score := mover.evaluatePositionHard(board, move[0], move[1])
+		if policy != nil {
+			score += int(policy[i] * policyWeight)
+		}
+		score += min(o.history[move[0]][move[1]], historyLimit)
+		switch {
+		case hasHashMove && move == hashMove:
+			score += hashMoveBonus
+		case move == killers[0] || move == killers[1]:
+			score += killerMoveBonus
+		}
+		scores[i] = score
+	}
+	order := make([]int, len(candidates))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return scores[order[a]] > scores[order[b]]
+	})
+
+	moves := make([][2]int, 0, searchBreadth)
+	for _, i := range order[:min(searchBreadth, len(order))] {
+		moves = append(moves, candidates[i])
+	}
+	return moves
+}
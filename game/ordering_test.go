@@ -0,0 +1,183 @@
+package game
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// orderingPosition is the middlegame position the ordering is tested on
+const orderingPosition = "h8 i9 g9 i7 i8 g8 j9 f10 g7 h6"
+
+// playMoves plays moves in coordinate notation on a new board
+func playMoves(t *testing.T, moves string) *Board {
+	t.Helper()
+	board := NewBoard()
+	for _, move := range strings.Fields(moves) {
+		row, col, err := ParseMove(move)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := board.PlaceStone(row, col); err != nil {
+			t.Fatalf("%s: %v", move, err)
+		}
+	}
+	return board
+}
+
+// unlistedMoves returns the candidate moves order leaves out for being
+// outside the searchBreadth best, which only a bonus can bring back
+func unlistedMoves(ai *AI, board *Board) [][2]int {
+	listed := newMoveOrdering().order(ai, board, board.Hash(), 0)
+	var moves [][2]int
+	for _, move := range board.CandidateMoves(2) {
+		if !slices.Contains(listed, move) {
+			moves = append(moves, move)
+		}
+	}
+	return moves
+}
+
+func TestOrderingHashMoveFirst(t *testing.T) {
+	board := playMoves(t, orderingPosition)
+	ai := NewAI(board.CurrentTurn, Expert)
+	unlisted := unlistedMoves(ai, board)
+	if len(unlisted) == 0 {
+		t.Fatal("every candidate move is listed")
+	}
+
+	ordering := newMoveOrdering()
+	ordering.storeBest(board.Hash(), unlisted[0])
+	if moves := ordering.order(ai, board, board.Hash(), 0); moves[0] != unlisted[0] {
+		t.Errorf("first move is %v, want the hash move %v", moves[0], unlisted[0])
+	}
+	if moves := ordering.order(ai, board, board.Hash()+1, 0); slices.Contains(moves, unlisted[0]) {
+		t.Error("the hash move is put first in another position")
+	}
+}
+
+func TestOrderingKillersPerPly(t *testing.T) {
+	board := playMoves(t, orderingPosition)
+	ai := NewAI(board.CurrentTurn, Expert)
+	unlisted := unlistedMoves(ai, board)
+	if len(unlisted) < 4 {
+		t.Fatalf("only %d candidate moves are unlisted", len(unlisted))
+	}
+	hashMove, first, second, third := unlisted[0], unlisted[1], unlisted[2], unlisted[3]
+
+	ordering := newMoveOrdering()
+	ordering.storeBest(board.Hash(), hashMove)
+	ordering.storeCutoff(first, 1, 3)
+	ordering.storeCutoff(second, 1, 3)
+
+	moves := ordering.order(ai, board, board.Hash(), 3)
+	if moves[0] != hashMove {
+		t.Errorf("first move is %v, want the hash move %v", moves[0], hashMove)
+	}
+	if killers := moves[1:3]; !slices.Contains(killers, first) || !slices.Contains(killers, second) {
+		t.Errorf("moves after the hash move are %v, want the killers %v and %v", killers, first, second)
+	}
+	for _, ply := range []int{2, 4} {
+		if moves := ordering.order(ai, board, board.Hash(), ply); slices.Contains(moves, first) {
+			t.Errorf("the killer move at ply 3 is listed at ply %d", ply)
+		}
+	}
+
+	// Only two killers are kept: a third cutoff drops the oldest
+	ordering.storeCutoff(third, 1, 3)
+	moves = ordering.order(ai, board, board.Hash(), 3)
+	if slices.Contains(moves, first) || !slices.Contains(moves, third) {
+		t.Errorf("after a third cutoff the moves are %v, want %v in place of %v", moves, third, first)
+	}
+}
+
+func TestOrderingHistory(t *testing.T) {
+	ordering := newMoveOrdering()
+	move := [2]int{7, 7}
+	ordering.storeCutoff(move, 2, 0)
+	ordering.storeCutoff(move, 3, 1)
+	if got := ordering.history[7][7]; got != 2*2+3*3 {
+		t.Errorf("history is %d after cutoffs at depths 2 and 3, want %d", got, 2*2+3*3)
+	}
+
+	// A quiet move a little behind another moves ahead of it once it has
+	// caused enough cutoffs elsewhere in the tree
+	board := playMoves(t, orderingPosition)
+	ai := NewAI(board.CurrentTurn, Expert)
+	mover := *ai
+	mover.player = board.CurrentTurn
+	moves := newMoveOrdering().order(ai, board, board.Hash(), 0)
+	for i := 0; i+1 < len(moves); i++ {
+		ahead, behind := moves[i], moves[i+1]
+		gap := mover.evaluatePositionHard(board, ahead[0], ahead[1]) -
+			mover.evaluatePositionHard(board, behind[0], behind[1])
+		if gap <= 0 || gap >= historyLimit/2 {
+			continue
+		}
+
+		ordering := newMoveOrdering()
+		depth := 1
+		for depth*depth <= gap {
+			depth++
+		}
+		ordering.storeCutoff(behind, depth, 9) // A killer at ply 9 only, not ply 0
+		reordered := ordering.order(ai, board, board.Hash(), 0)
+		if slices.Index(reordered, behind) > slices.Index(reordered, ahead) {
+			t.Errorf("%v is still behind %v after a cutoff at depth %d", behind, ahead, depth)
+		}
+		return
+	}
+	t.Fatal("no two moves are close enough for the history to reorder")
+}
+
+// unorderedNegamax is searcher.negamax trying the same moves without
+// ordering them: by position on the board, with nothing learned from
+// earlier cutoffs
+func (s *searcher) unorderedNegamax(depth, alpha, beta, ply int) int {
+	if s.board.IsDraw() {
+		return 0
+	}
+	if s.board.IsGameFinished() {
+		return -WinScore - depth
+	}
+	if depth == 0 {
+		return s.quiesce(alpha, beta, 0)
+	}
+	s.nodes++
+
+	moves := newMoveOrdering().order(s.ai, s.board, s.board.Hash(), ply)
+	slices.SortFunc(moves, func(a, b [2]int) int {
+		return (a[0]*BoardSize + a[1]) - (b[0]*BoardSize + b[1])
+	})
+	for _, move := range moves {
+		if s.play(move) != nil {
+			continue
+		}
+		score := -s.unorderedNegamax(depth-1, -beta, -alpha, ply+1)
+		s.undo()
+		alpha = max(alpha, score)
+		if alpha >= beta {
+			break
+		}
+	}
+	return alpha
+}
+
+func TestOrderingPrunesMore(t *testing.T) {
+	const depth = 3
+	board := playMoves(t, orderingPosition)
+	ai := NewAI(board.CurrentTurn, Expert)
+	ai.SetEvaluator(nil)
+
+	ordered := newSearcher(context.Background(), ai, board.Clone())
+	for d := 1; d <= depth; d++ {
+		ordered.root(d)
+	}
+	unordered := newSearcher(context.Background(), ai, board.Clone())
+	unordered.unorderedNegamax(depth, -WinScore*2, WinScore*2, 0)
+
+	if ordered.nodes >= unordered.nodes {
+		t.Errorf("ordered search to depth %d visited %d nodes, unordered %d", depth, ordered.nodes, unordered.nodes)
+	}
+}
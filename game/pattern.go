@@ -0,0 +1,119 @@
+package game
+
+// Pattern is the strongest shape a stone forms along one line, counting
+// broken shapes such as X_XX or XX_X_ as well as unbroken runs. Patterns are
+// named for five in a row; under other win lengths a four is one move from
+// winning, an open four two different moves, and so on.
+type Pattern int
+
+const (
+	PatternNone     Pattern = iota
+	PatternThree            // One move from an open four, e.g. _XXX_ or _X_XX_
+	PatternFour             // One move from five, e.g. OXXXX_ or XX_XX
+	PatternOpenFour         // Two different moves make five, e.g. _XXXX_
+	PatternFive             // A winning row
+)
+
+// wall marks cells in a scanned line that the stone cannot use: beyond the
+// edge of the board or held by the other player
+const wall Player = -1
+
+// patternLine is a scanned line centred on a stone, reaching one cell short
+// of the winning length each way; no winning row through the stone can extend
+// further. Under exact rows it reaches one cell more, to see overlines.
+// Patterns are classified on it once, for every line, to fill the pattern
+// tables.
+type patternLine struct {
+	cells []Player
+	rules Rules
+}
+
+// linePattern returns the pattern the stone at (row, col) forms along the
+// direction (dRow, dCol)
+func linePattern(board *Board, row, col, dRow, dCol int) Pattern {
+	player := board.Grid[row][col]
+	if player == Empty {
+		return PatternNone
+	}
+
+	reach := patternReach(board.rules)
+	own, empty, _ := board.bits.lineWindow(row, col, dRow, dCol, reach, player)
+	return windowPatterns(board.rules)[patternKey(own, empty, reach)]
+}
+
+func (line *patternLine) pattern(player Player) Pattern {
+	stones := 0
+	for _, cell := range line.cells {
+		if cell == player {
+			stones++
+		}
+	}
+	if stones < line.rules.WinLength-2 {
+		return PatternNone // Too few stones for even a three
+	}
+	if line.rules.wins(line.runLength(player)) {
+		return PatternFive
+	}
+	switch line.fiveMoves(player) {
+	case 0:
+	case 1:
+		return PatternFour
+	default:
+		return PatternOpenFour
+	}
+
+	// A three is one move away from an open four
+	for i, cell := range line.cells {
+		if cell != Empty {
+			continue
+		}
+		line.cells[i] = player
+		openFour := line.fiveMoves(player) >= 2
+		line.cells[i] = Empty
+		if openFour {
+			return PatternThree
+		}
+	}
+	return PatternNone
+}
+
+// runLength returns the length of the unbroken run through the middle cell
+func (line *patternLine) runLength(player Player) int {
+	cells, middle := line.cells, len(line.cells)/2
+	count := 1
+	for i := middle + 1; i < len(cells) && cells[i] == player; i++ {
+		count++
+	}
+	for i := middle - 1; i >= 0 && cells[i] == player; i-- {
+		count++
+	}
+	return count
+}
+
+// fiveMoves counts the empty cells that would complete a winning row through
+// the middle cell
+func (line *patternLine) fiveMoves(player Player) int {
+	moves := 0
+	for i, cell := range line.cells {
+		if cell != Empty {
+			continue
+		}
+		line.cells[i] = player
+		if line.rules.wins(line.runLength(player)) {
+			moves++
+		}
+		line.cells[i] = Empty
+	}
+	return moves
+}
+
+// patternCounts counts the directions in which the stone at (row, col) forms
+// each pattern
+func patternCounts(board *Board, row, col int) [PatternFive + 1]int {
+	var counts [PatternFive + 1]int
+	directions := [][2]int{{1, 0}, {0, 1}, {1, 1}, {1, -1}}
+	for _, dir := range directions {
+		counts[linePattern(board, row, col, dir[0], dir[1])]++
+	}
+	return counts
+}
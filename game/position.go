@@ -0,0 +1,164 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+)
+
+// PositionProblem is a reason a set-up position cannot be reached in a game
+type PositionProblem int
+
+const (
+	ProblemStoneCount PositionProblem = iota // Black must have as many stones as White, or one more
+	ProblemSideToMove                        // The side to move does not follow from the stone counts
+	ProblemWinningRow                        // A player already has a winning row
+	ProblemFloating                          // Stones hang over empty cells under gravity
+)
+
+// PositionIssue is a problem found in a position, with a description
+type PositionIssue struct {
+	Problem PositionProblem
+	Message string
+}
+
+// CheckPosition returns the problems with playing on from the stones of grid
+// with toMove to play under rules. A winning row is only a problem if it was
+// not intended: such a position can still be played, as a finished game,
+// when the winner made the last move.
+func CheckPosition(grid [BoardSize][BoardSize]Player, toMove Player, rules Rules) []PositionIssue {
+	var issues []PositionIssue
+	var count [5]int
+	for _, row := range grid {
+		for _, cell := range row {
+			count[cell]++
+		}
+	}
+	black, white := count[Black], count[White]
+	switch {
+	case black != white && black != white+1:
+		issues = append(issues, PositionIssue{ProblemStoneCount, fmt.Sprintf(
+			"Black has %d stones and White %d; Black moves first, so it needs as many as White or one more.", black, white)})
+	case black == white && toMove != Black:
+		issues = append(issues, PositionIssue{ProblemSideToMove, fmt.Sprintf(
+			"With %d stones each it is Black's turn.", black)})
+	case black == white+1 && toMove != White:
+		issues = append(issues, PositionIssue{ProblemSideToMove,
+			"With one more black stone than white it is White's turn."})
+	}
+	if rules.Gravity && floatingStones(grid) > 0 {
+		issues = append(issues, PositionIssue{ProblemFloating,
+			"Some stones hang over empty cells; with gravity every stone rests on the bottom row or on another stone."})
+	}
+	movedLast := White
+	if black > white {
+		movedLast = Black
+	}
+	for _, player := range []Player{Black, White} {
+		if len(winningStones(grid, player, rules)) == 0 {
+			continue
+		}
+		message := fmt.Sprintf("%s already has %s, so the game is over.", player, rules)
+		if movedLast != player {
+			message = fmt.Sprintf("%s already has %s, but %s moved last, so the game would have ended earlier.",
+				player, rules, movedLast)
+		}
+		issues = append(issues, PositionIssue{ProblemWinningRow, message})
+	}
+	return issues
+}
+
+// NewBoardFromPosition returns a board under rules holding the stones of
+// grid, placed as alternating moves from Black so a game can go on from the
+// position. Black must have as many stones as White, or one more. A position
+// with a winning row gives a finished game, provided the winner moved last.
+// Blocked points of grid are set up as the board's obstacles.
+func NewBoardFromPosition(grid [BoardSize][BoardSize]Player, rules Rules) (*Board, error) {
+	if rules.ThreePlayers {
+		return nil, errors.New("positions can only be set up for two players")
+	}
+	var stones [3][][2]int
+	for _, player := range []Player{Black, White} {
+		// Stones in winning rows go last, so the game ends on the last move
+		winning := winningStones(grid, player, rules)
+		for i := 0; i < BoardSize; i++ {
+			for j := 0; j < BoardSize; j++ {
+				if grid[i][j] == player && !winning[[2]int{i, j}] {
+					stones[player] = append(stones[player], [2]int{i, j})
+				}
+			}
+		}
+		for i := 0; i < BoardSize; i++ {
+			for j := 0; j < BoardSize; j++ {
+				if winning[[2]int{i, j}] {
+					stones[player] = append(stones[player], [2]int{i, j})
+				}
+			}
+		}
+	}
+	black, white := stones[Black], stones[White]
+	if len(black) != len(white) && len(black) != len(white)+1 {
+		return nil, errors.New("black must have as many stones as white, or one more")
+	}
+
+	// Set-up stones are not openings, so the opening rule does not restrict
+	// them, and they are placed where they are rather than dropped
+	free := rules
+	free.Opening = FreeOpening
+	free.Gravity = false
+	board, err := NewBoardWithRules(free)
+	if err != nil {
+		return nil, err
+	}
+	var obstacles [][2]int
+	for i := 0; i < BoardSize; i++ {
+		for j := 0; j < BoardSize; j++ {
+			if grid[i][j] == Blocked {
+				obstacles = append(obstacles, [2]int{i, j})
+			}
+		}
+	}
+	if len(obstacles) > 0 {
+		if err := board.SetupObstacles(obstacles); err != nil {
+			return nil, err
+		}
+	}
+	for k, move := range black {
+		moves := [][2]int{move}
+		if k < len(white) {
+			moves = append(moves, white[k])
+		}
+		for _, move := range moves {
+			if board.IsGameFinished() {
+				return nil, errors.New("the position cannot be reached: the game would be over before every stone is placed")
+			}
+			board.PlaceStone(move[0], move[1])
+		}
+	}
+	board.rules = rules
+	return board, nil
+}
+
+// winningStones returns the stones of player that are part of a winning row
+func winningStones(grid [BoardSize][BoardSize]Player, player Player, rules Rules) map[[2]int]bool {
+	stones := make(map[[2]int]bool)
+	for _, dir := range evalDirections {
+		for i := 0; i < BoardSize; i++ {
+			for j := 0; j < BoardSize; j++ {
+				// Runs are measured from their first stone only
+				if prev := [2]int{i - dir[0], j - dir[1]}; inBounds(prev[0], prev[1]) && grid[prev[0]][prev[1]] == player {
+					continue
+				}
+				run := 0
+				for inBounds(i+dir[0]*run, j+dir[1]*run) && grid[i+dir[0]*run][j+dir[1]*run] == player {
+					run++
+				}
+				if rules.wins(run) {
+					for k := 0; k < run; k++ {
+						stones[[2]int{i + dir[0]*k, j + dir[1]*k}] = true
+					}
+				}
+			}
+		}
+	}
+	return stones
+}
@@ -0,0 +1,60 @@
+package game
+
+// ResultReason is why a game ended
+type ResultReason int
+
+const (
+	InProgress        ResultReason = iota
+	ReasonRow                      // A player made a winning row
+	ReasonPasses                   // Every player passed in a row
+	ReasonFullBoard                // No empty cells remain
+	ReasonAdjudicated              // The engine decided an unfinished game
+)
+
+func (r ResultReason) String() string {
+	switch r {
+	case ReasonRow:
+		return "winning row"
+	case ReasonPasses:
+		return "all players passed"
+	case ReasonFullBoard:
+		return "board full"
+	case ReasonAdjudicated:
+		return "adjudicated"
+	}
+	return "in progress"
+}
+
+// Result is the outcome of a game, the zero value while it is being played
+type Result struct {
+	Winner Player // Empty unless a player won
+	Draw   bool
+	Reason ResultReason
+
+	Confidence float64 // Chance the engine gave an adjudicated result
+}
+
+// Finished reports whether the game is over
+func (r Result) Finished() bool {
+	return r.Reason != InProgress
+}
+
+// String describes the result, e.g. "Black wins" or "draw, board full"
+func (r Result) String() string {
+	switch {
+	case !r.Finished():
+		return r.Reason.String()
+	case r.Draw:
+		return "draw, " + r.Reason.String()
+	}
+	winner := r.Winner.String() + " wins"
+	if r.Reason == ReasonAdjudicated {
+		winner += ", adjudicated"
+	}
+	return winner
+}
+
+// Result returns the outcome of the game on the board
+func (b *Board) Result() Result {
+	return b.result
+}
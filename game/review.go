@@ -0,0 +1,131 @@
+package game
+
+import (
+	"encoding/json"
+	"io"
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	reviewStartEase = 2.5 // Ease factor of a new card
+	reviewMinEase   = 1.3 // Lowest ease factor, so hard cards still spread out
+	reviewPass      = 3   // Lowest quality that counts as remembered
+
+	// MaxReviewQuality is the quality of a perfect answer
+	MaxReviewQuality = 5
+)
+
+// Card is an item of training content, such as an opening drill, scheduled
+// for review with the SM-2 spaced repetition algorithm
+type Card struct {
+	ID       string    `json:"id"`       // Identifies the content, e.g. "drill:<opening>"
+	Title    string    `json:"title"`    // Shown in the review list
+	Interval int       `json:"interval"` // Days until the next review
+	Ease     float64   `json:"ease"`     // Growth factor of the interval
+	Reps     int       `json:"reps"`     // Reviews passed in a row
+	Due      time.Time `json:"due"`
+}
+
+// scheduleMigrations upgrade saved schedules, see Migrate
+var scheduleMigrations = []Migration{
+	// 0 to 1: the version field was added
+	func(map[string]any) error { return nil },
+}
+
+// Schedule holds the cards of one player, keyed by ID
+type Schedule struct {
+	Version int              `json:"version"`
+	Cards   map[string]*Card `json:"cards"`
+}
+
+func NewSchedule() *Schedule {
+	return &Schedule{Version: len(scheduleMigrations), Cards: make(map[string]*Card)}
+}
+
+// LoadSchedule reads a schedule saved with Save by this or an older release
+func LoadSchedule(r io.Reader) (*Schedule, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if data, err = Migrate(data, scheduleMigrations); err != nil {
+		return nil, err
+	}
+	schedule := NewSchedule()
+	if err := json.Unmarshal(data, schedule); err != nil {
+		return nil, err
+	}
+	if schedule.Cards == nil {
+		schedule.Cards = make(map[string]*Card)
+	}
+	return schedule, nil
+}
+
+// Save writes the schedule as JSON
+func (s *Schedule) Save(w io.Writer) error {
+	return json.NewEncoder(w).Encode(s)
+}
+
+// Add returns the card with the given ID, creating it due now if needed
+func (s *Schedule) Add(id, title string, now time.Time) *Card {
+	card, ok := s.Cards[id]
+	if !ok {
+		card = &Card{ID: id, Title: title, Ease: reviewStartEase, Due: now}
+		s.Cards[id] = card
+	}
+	return card
+}
+
+// Review records how well a card was recalled, from 0 for a blackout to
+// MaxReviewQuality for a perfect answer, and schedules its next review.
+// Cards not in the schedule are added first.
+func (s *Schedule) Review(id, title string, quality int, now time.Time) *Card {
+	card := s.Add(id, title, now)
+	quality = max(0, min(quality, MaxReviewQuality))
+
+	if quality < reviewPass {
+		// Forgotten: learn it again from the first interval
+		card.Reps = 0
+		card.Interval = 1
+	} else {
+		switch card.Reps {
+		case 0:
+			card.Interval = 1
+		case 1:
+			card.Interval = 6
+		default:
+			card.Interval = int(math.Round(float64(card.Interval) * card.Ease))
+		}
+		card.Reps++
+	}
+
+	miss := float64(MaxReviewQuality - quality)
+	card.Ease = math.Max(reviewMinEase, card.Ease+0.1-miss*(0.08+miss*0.02))
+	card.Due = now.AddDate(0, 0, card.Interval)
+	return card
+}
+
+// Due returns the cards due at the given time, most overdue first
+func (s *Schedule) Due(now time.Time) []*Card {
+	var due []*Card
+	for _, card := range s.Cards {
+		if !card.Due.After(now) {
+			due = append(due, card)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool {
+		if !due[i].Due.Equal(due[j].Due) {
+			return due[i].Due.Before(due[j].Due)
+		}
+		return due[i].ID < due[j].ID
+	})
+	return due
+}
+
+// ReviewQuality converts the fraction of a review answered correctly into a
+// quality for Review
+func ReviewQuality(correct float64) int {
+	return int(math.Round(math.Max(0, math.Min(correct, 1)) * MaxReviewQuality))
+}
@@ -0,0 +1,226 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Limits on Rules.WinLength. Lines are scanned within WinLength-1 cells of a
+// stone, which must fit the bitboard's line windows.
+const (
+	MinWinLength = 4
+	MaxWinLength = 6
+
+	// MaxExactLength is the longest row Rules.Exact supports. Exact rows
+	// are scanned one cell further, and the pattern tables grow fourfold
+	// with every cell.
+	MaxExactLength = 5
+)
+
+// OpeningRule is a restriction on the first stones of a game
+type OpeningRule int
+
+const (
+	FreeOpening    OpeningRule = iota // No restriction
+	ProOpening                        // Black opens in the center; its second stone goes 3 or more rows or columns away
+	LongProOpening                    // As ProOpening, but 4 or more rows or columns away
+	SwapOpening                       // White may take over Black's first stone instead of replying, see Board.Swap
+)
+
+// String names the opening rule, e.g. "Pro opening"
+func (o OpeningRule) String() string {
+	switch o {
+	case ProOpening:
+		return "Pro opening"
+	case LongProOpening:
+		return "Long Pro opening"
+	case SwapOpening:
+		return "swap opening"
+	}
+	return "free opening"
+}
+
+// Rules are the rules a game is played under. The referee package repeats
+// them without the engines, so a change to how a game is played must be
+// made there too, with its TestAgreesWithGame covering it.
+type Rules struct {
+	WinLength int  // Stones in a row that win; longer rows win too unless Exact
+	Exact     bool // Only rows of exactly WinLength win, not longer ones (overlines)
+	Opening   OpeningRule
+	Gravity   bool // Stones fall to the lowest empty cell of their column, as in Connect Four
+
+	ThreePlayers bool // Red joins, moving after White; see Rules.NextPlayer
+}
+
+// StandardRules are the rules of freestyle gomoku, five or more in a row
+var StandardRules = Rules{WinLength: WinCondition}
+
+// Validate reports rules the board and engines cannot play
+func (r Rules) Validate() error {
+	if r.WinLength < MinWinLength || r.WinLength > MaxWinLength {
+		return fmt.Errorf("win length %d is not between %d and %d", r.WinLength, MinWinLength, MaxWinLength)
+	}
+	if r.Exact && r.WinLength > MaxExactLength {
+		return fmt.Errorf("exact rows are supported up to %d in a row", MaxExactLength)
+	}
+	if r.Opening < FreeOpening || r.Opening > SwapOpening {
+		return fmt.Errorf("unknown opening rule %d", r.Opening)
+	}
+	if r.Gravity && r.ThirdMoveDistance() > 0 {
+		return fmt.Errorf("the %s cannot be played with gravity, which keeps stones off the center", r.Opening)
+	}
+	if r.ThreePlayers && r.Opening != FreeOpening {
+		return fmt.Errorf("the %s is for two players; three-player games use a free opening", r.Opening)
+	}
+	return nil
+}
+
+// Standard reports whether the rules are StandardRules. Opening books,
+// ratings and external engines assume them.
+func (r Rules) Standard() bool {
+	return r == StandardRules
+}
+
+// String describes the rules, e.g. "five in a row" or "five in a row, Pro
+// opening, gravity"
+func (r Rules) String() string {
+	names := map[int]string{4: "four", 5: "five", 6: "six"}
+	name, ok := names[r.WinLength]
+	if !ok {
+		name = fmt.Sprint(r.WinLength)
+	}
+	name += " in a row"
+	if r.Exact {
+		name = "exactly " + name
+	}
+	if r.Opening != FreeOpening {
+		name += ", " + r.Opening.String()
+	}
+	if r.Gravity {
+		name += ", gravity"
+	}
+	if r.ThreePlayers {
+		name += ", three players"
+	}
+	return name
+}
+
+// wins reports whether a row of run stones wins
+func (r Rules) wins(run int) bool {
+	if r.Exact {
+		return run == r.WinLength
+	}
+	return run >= r.WinLength
+}
+
+// ThirdMoveDistance returns how many rows or columns from the center Black's
+// second stone, the third move of the game, must be at least, 0 when the
+// opening is free
+func (r Rules) ThirdMoveDistance() int {
+	switch r.Opening {
+	case ProOpening:
+		return 3
+	case LongProOpening:
+		return 4
+	}
+	return 0
+}
+
+// checkOpening reports an error if a stone at (row, col) breaks a Pro
+// opening rule, which covers the first and third moves of the game
+func (b *Board) checkOpening(row, col int) error {
+	if b.rules.ThirdMoveDistance() == 0 {
+		return nil
+	}
+	center := BoardSize / 2
+	distance := max(abs(row-center), abs(col-center))
+	switch len(b.MoveHistory) {
+	case 0:
+		if distance != 0 {
+			return fmt.Errorf("the first move must be in the center under the %s", b.rules.Opening)
+		}
+	case 2:
+		if limit := b.rules.ThirdMoveDistance(); distance < limit {
+			return fmt.Errorf("the third move must be at least %d rows or columns from the center under the %s",
+				limit, b.rules.Opening)
+		}
+	}
+	return nil
+}
+
+// openingMoves narrows moves to those a Pro opening rule allows. When none
+// of them is allowed on the third move, the nearest allowed points, a square
+// ring around the center, are returned instead.
+func (b *Board) openingMoves(moves [][2]int) [][2]int {
+	if b.rules.ThirdMoveDistance() == 0 || len(b.MoveHistory) != 2 {
+		return moves
+	}
+	var allowed [][2]int
+	for _, move := range moves {
+		if b.checkOpening(move[0], move[1]) == nil {
+			allowed = append(allowed, move)
+		}
+	}
+	if len(allowed) > 0 {
+		return allowed
+	}
+	center, distance := BoardSize/2, b.rules.ThirdMoveDistance()
+	for i := center - distance; i <= center+distance; i++ {
+		for j := center - distance; j <= center+distance; j++ {
+			onRing := max(abs(i-center), abs(j-center)) == distance
+			if onRing && b.Grid[i][j] == Empty {
+				allowed = append(allowed, [2]int{i, j})
+			}
+		}
+	}
+	return allowed
+}
+
+// CanSwap reports whether White, to move after Black's first stone, may
+// take it over under the swap opening
+func (b *Board) CanSwap() bool {
+	return b.rules.Opening == SwapOpening && len(b.MoveHistory) == 1 && !b.MoveHistory[0].IsPass() &&
+		b.CurrentTurn == White && !b.result.Finished()
+}
+
+// Swap takes over Black's first stone for White instead of replying to it,
+// under the swap opening. Rather than the players changing colors, the
+// stone turns White and Black moves next, which leaves the same game.
+// Black chooses the first stone knowing White may take it, so a stone
+// that is too strong is given away. A swapped opening cannot be redone
+// once undone.
+func (b *Board) Swap() error {
+	if !b.CanSwap() {
+		return errors.New("swapping is only allowed in reply to the first stone under the swap opening")
+	}
+	first := &b.MoveHistory[0]
+	b.eval.remove(first.Row, first.Col, Black)
+	b.setCell(first.Row, first.Col, White)
+	b.eval.place(first.Row, first.Col, White)
+	first.Player = White
+	b.CurrentTurn = Black
+	b.redo = b.redo[:0]
+	return nil
+}
+
+// Swapped reports whether White took over the first stone
+func (b *Board) Swapped() bool {
+	return b.rules.Opening == SwapOpening && len(b.MoveHistory) > 0 && b.MoveHistory[0].Player == White
+}
+
+// NewBoardWithRules returns an empty board for a game under rules
+func NewBoardWithRules(rules Rules) (*Board, error) {
+	if err := rules.Validate(); err != nil {
+		return nil, err
+	}
+	board := NewBoard()
+	board.rules = rules
+	board.eval.length = rules.WinLength
+	board.eval.exact = rules.Exact
+	return board, nil
+}
+
+// Rules returns the rules the game on the board is played under
+func (b *Board) Rules() Rules {
+	return b.rules
+}
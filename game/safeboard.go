@@ -0,0 +1,83 @@
+package game
+
+import "sync"
+
+// SafeBoard guards a Board with a lock so several goroutines, such as a
+// server handling requests and a background analysis, can share one game.
+// Reads see the board between moves, never halfway through one.
+type SafeBoard struct {
+	mu    sync.RWMutex
+	board *Board
+}
+
+// NewSafeBoard wraps board, which must not be used directly afterwards
+func NewSafeBoard(board *Board) *SafeBoard {
+	return &SafeBoard{board: board}
+}
+
+func (s *SafeBoard) PlaceStone(row, col int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.board.PlaceStone(row, col)
+}
+
+func (s *SafeBoard) Pass() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.board.Pass()
+}
+
+func (s *SafeBoard) Undo() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.board.Undo()
+}
+
+func (s *SafeBoard) Redo() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.board.Redo()
+}
+
+// Update runs f with the board locked for writing, for changes of several
+// steps that others must not see half done
+func (s *SafeBoard) Update(f func(board *Board) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return f(s.board)
+}
+
+// View runs f with the board locked for reading. f must not change the
+// board, and so must not hand it to the AI, which writes stones while it
+// thinks; analyze a Snapshot instead.
+func (s *SafeBoard) View(f func(board *Board)) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	f(s.board)
+}
+
+// Snapshot returns a Clone of the board that the caller owns, for analysis
+// or drawing without holding the lock
+func (s *SafeBoard) Snapshot() *Board {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.board.Clone()
+}
+
+func (s *SafeBoard) CurrentTurn() Player {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.board.CurrentTurn
+}
+
+func (s *SafeBoard) Result() Result {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.board.Result()
+}
+
+func (s *SafeBoard) LastMove() (Move, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.board.LastMove()
+}
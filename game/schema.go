@@ -0,0 +1,42 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrNewerSchema is returned when loading data saved by a newer release,
+// which this one cannot read without losing what it does not know about
+var ErrNewerSchema = errors.New("saved by a newer version of the game")
+
+// Migration upgrades saved JSON data, decoded generically, by one version
+type Migration func(data map[string]any) error
+
+// Migrate brings saved JSON data up to the latest version, running the
+// migrations from the data's version on. migrations[i] upgrades version i to
+// i+1; data without a "version" field is version 0.
+func Migrate(data []byte, migrations []Migration) ([]byte, error) {
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	version := 0
+	if v, ok := fields["version"].(float64); ok {
+		version = int(v)
+	}
+	switch {
+	case version > len(migrations):
+		return nil, fmt.Errorf("version %d: %w", version, ErrNewerSchema)
+	case version == len(migrations):
+		return data, nil
+	}
+
+	for ; version < len(migrations); version++ {
+		if err := migrations[version](fields); err != nil {
+			return nil, fmt.Errorf("migrating from version %d: %w", version, err)
+		}
+	}
+	fields["version"] = version
+	return json.Marshal(fields)
+}
@@ -0,0 +1,364 @@
+package game
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	ExpertDepth = 4
+	MasterDepth = 8
+
+	ExpertTimeLimit = 2 * time.Second
+	MasterTimeLimit = 5 * time.Second
+
+	quiescenceDepth = 8 // Forcing plies searched past the depth limit
+)
+
+// SetDepth sets the number of plies the Expert and Master engines search
+func (ai *AI) SetDepth(depth int) {
+	if depth < 1 {
+		depth = 1
+	}
+	ai.depth = depth
+}
+
+// SetTimeLimit sets how long the Expert and Master engines may think per
+// move, 0 for no limit. Easier positions use less of it.
+func (ai *AI) SetTimeLimit(limit time.Duration) {
+	if limit < 0 {
+		limit = 0
+	}
+	ai.timeLimit = limit
+}
+
+// Expert and Master modes: alpha-beta search over the best candidate moves,
+// deepening one ply at a time until the depth or time limit is reached
+func (ai *AI) makeSearchMove(ctx context.Context, board *Board) (int, int) {
+	// 1. Check if AI can win
+	if move := ai.findWinningMove(board, ai.player); move[0] >= 0 {
+		return move[0], move[1]
+	}
+
+	// 2. Check if need to block opponent's winning move
+	if move := ai.findWinningMove(board, ai.getOpponent()); move[0] >= 0 {
+		return move[0], move[1]
+	}
+
+	// If no stones on board, play center
+	if board.stones == 0 {
+		move := board.centerMove()
+		return move[0], move[1]
+	}
+
+	// 3. Strength-limited engines sometimes play a plausible but worse move
+	if ai.makesError() {
+		move := ai.inaccurateMove(board)
+		return move[0], move[1]
+	}
+
+	// 4. Search deeper until time runs out, keeping the last completed result.
+	// Easy positions get half the time limit and the hardest the full limit.
+	searchCtx := ctx
+	if ai.timeLimit > 0 {
+		rating := ai.RateDifficulty(board).Rating
+		limit := time.Duration(float64(ai.timeLimit) * (0.5 + rating/2))
+		var cancel context.CancelFunc
+		searchCtx, cancel = context.WithTimeout(ctx, limit)
+		defer cancel()
+	}
+	search := newSearcher(searchCtx, ai, board.Clone())
+	best := [2]int{-1, -1}
+	start := time.Now()
+	for depth := 1; depth <= ai.depth; depth++ {
+		move, score := search.root(depth)
+		if searchCtx.Err() != nil {
+			break
+		}
+		best = move
+		if ai.searchReport != nil {
+			ai.searchReport(SearchInfo{
+				Depth:   depth,
+				Move:    move,
+				Score:   score,
+				PV:      search.principalVariation(depth),
+				Nodes:   search.nodes,
+				Elapsed: time.Since(start),
+			})
+		}
+		if score >= WinScore {
+			break // A forced win was found, no need to look further
+		}
+	}
+	if best[0] >= 0 {
+		return best[0], best[1]
+	}
+
+	// 5. Not even one ply finished, fall back to hard mode
+	return ai.makeHardMove(ctx, board)
+}
+
+// SearchInfo describes one completed iteration of the Expert and Master search
+type SearchInfo struct {
+	Depth   int
+	Move    [2]int        // Best move found at this depth
+	Score   int           // Score of the move for the side to move
+	PV      [][2]int      // Line of play the search expects, starting with Move
+	Nodes   int           // Positions searched since the search started
+	Elapsed time.Duration // Time since the search started
+}
+
+// Analysis is the engine's view of a position for the side to move
+type Analysis struct {
+	Move  [2]int
+	Score int      // Search score for the side to move, or the move's heuristic score when Depth is 0
+	Depth int      // Plies searched, 0 when the move was found without a search
+	PV    [][2]int // Line of play expected after the position, starting with Move
+	Nodes int      // Positions searched
+}
+
+// Analyze returns the move the AI's engine would play for the side to move,
+// which need not be the AI's own color, with its score and expected line.
+// Only Expert and Master search; other engines report the move alone.
+func (ai *AI) Analyze(ctx context.Context, board *Board) (Analysis, error) {
+	helper := *ai
+	helper.player = board.CurrentTurn
+	var last *SearchInfo
+	helper.searchReport = func(info SearchInfo) {
+		last = &info
+		if ai.searchReport != nil {
+			ai.searchReport(info)
+		}
+	}
+
+	row, col, err := helper.MakeMoveCtx(ctx, board)
+	if err != nil {
+		return Analysis{}, err
+	}
+	move := [2]int{row, col}
+	if last != nil && last.Move == move {
+		return Analysis{Move: move, Score: last.Score, Depth: last.Depth, PV: last.PV, Nodes: last.Nodes}, nil
+	}
+	analysis := Analysis{Move: move, PV: [][2]int{move}}
+	if move != PassMove {
+		analysis.Score = helper.evaluatePositionHard(board, row, col)
+	}
+	return analysis, nil
+}
+
+// SetSearchReport sets a function called after every completed depth of the
+// Expert and Master search, nil for none. Moves played without a search, such
+// as book moves and immediate wins, are not reported.
+func (ai *AI) SetSearchReport(report func(SearchInfo)) {
+	ai.searchReport = report
+}
+
+// searcher holds the state of one alpha-beta search
+type searcher struct {
+	ctx      context.Context
+	ai       *AI
+	board    *Board
+	ordering *moveOrdering
+	nodes    int // Positions searched, for reports
+}
+
+func newSearcher(ctx context.Context, ai *AI, board *Board) *searcher {
+	return &searcher{
+		ctx:      ctx,
+		ai:       ai,
+		board:    board,
+		ordering: newMoveOrdering(),
+	}
+}
+
+// play places a stone for the side to move. A move the rules refuse
+// leaves the board as it was, and must not be undone.
+func (s *searcher) play(move [2]int) error {
+	return s.board.PlaceStone(move[0], move[1])
+}
+
+// undo takes back the last move played
+func (s *searcher) undo() {
+	s.board.Undo()
+}
+
+// root searches every root move to the given depth and returns the best one
+// with its score for the side to move
+func (s *searcher) root(depth int) ([2]int, int) {
+	best, alpha := [2]int{-1, -1}, -WinScore*2
+	for _, move := range s.ordering.order(s.ai, s.board, s.board.Hash(), 0) {
+		if s.play(move) != nil {
+			continue
+		}
+		score := -s.negamax(depth-1, -WinScore*2, -alpha, 1)
+		s.undo()
+		if s.ctx.Err() != nil {
+			return [2]int{-1, -1}, 0
+		}
+		if score > alpha || best[0] < 0 {
+			best, alpha = move, score
+		}
+	}
+	s.ordering.storeBest(s.board.Hash(), best)
+	return best, alpha
+}
+
+// principalVariation follows the best moves stored for each position from
+// the root, up to depth moves, to give the line the search expects
+func (s *searcher) principalVariation(depth int) [][2]int {
+	var pv [][2]int
+	for len(pv) < depth && !s.board.IsGameFinished() {
+		move, ok := s.ordering.hashMoves[s.board.Hash()]
+		if !ok || s.play(move) != nil {
+			break
+		}
+		pv = append(pv, move)
+	}
+	for range pv {
+		s.undo()
+	}
+	return pv
+}
+
+// negamax returns the score of the position for the side to move, searching
+// depth more plies with alpha-beta pruning. ply counts the moves from the root.
+func (s *searcher) negamax(depth, alpha, beta, ply int) int {
+	if s.board.IsDraw() {
+		return 0
+	}
+	if s.board.IsGameFinished() {
+		// The previous move won; losing later is better than losing now
+		return -WinScore - depth
+	}
+	if depth == 0 || s.ctx.Err() != nil {
+		return s.quiesce(alpha, beta, 0)
+	}
+	s.nodes++
+
+	best := [2]int{-1, -1}
+	for _, move := range s.ordering.order(s.ai, s.board, s.board.Hash(), ply) {
+		if s.play(move) != nil {
+			continue
+		}
+		score := -s.negamax(depth-1, -beta, -alpha, ply+1)
+		s.undo()
+		if score > alpha {
+			alpha, best = score, move
+		}
+		if alpha >= beta {
+			s.ordering.storeCutoff(move, depth, ply)
+			break
+		}
+	}
+	if best[0] >= 0 {
+		s.ordering.storeBest(s.board.Hash(), best)
+	}
+	return alpha
+}
+
+// quiesce evaluates a position at the end of the search for the side to
+// move. The static evaluation cannot tell whose turn it is, so the search is
+// extended along forcing moves only: a four must be blocked at once, and the
+// side to move may try its own fours and open threes or stand on the static
+// score. Without this the search stops one ply short of forced losses.
+func (s *searcher) quiesce(alpha, beta, ply int) int {
+	s.nodes++
+	board := s.board
+	if board.IsDraw() {
+		return 0
+	}
+	if board.IsGameFinished() {
+		return -WinScore - quiescenceDepth + ply
+	}
+	mover := board.CurrentTurn
+	opponent := Black
+	if mover == Black {
+		opponent = White
+	}
+
+	if move := s.ai.findWinningMove(board, mover); move[0] >= 0 {
+		return WinScore
+	}
+	blocks := fiveMoves(board, opponent)
+	if len(blocks) >= 2 {
+		return -WinScore
+	}
+	if ply >= quiescenceDepth || s.ctx.Err() != nil {
+		return s.ai.staticScore(board)
+	}
+	if len(blocks) == 1 && s.play(blocks[0]) == nil {
+		// The only move is to block the four
+		score := -s.quiesce(-beta, -alpha, ply+1)
+		s.undo()
+		return score
+	}
+
+	stand := s.ai.staticScore(board)
+	if stand >= beta {
+		return stand
+	}
+	alpha = max(alpha, stand)
+	for _, move := range s.ai.forcingMoves(board, ply < quiescenceDepth/2) {
+		if s.play(move) != nil {
+			continue
+		}
+		score := -s.quiesce(-beta, -alpha, ply+1)
+		s.undo()
+		if score > alpha {
+			alpha = score
+		}
+		if alpha >= beta {
+			break
+		}
+	}
+	return alpha
+}
+
+// staticScore returns the static evaluation for the side to move
+func (ai *AI) staticScore(board *Board) int {
+	var score int
+	if ai.evaluator != nil && !board.IsGameFinished() {
+		score = ai.evaluator.Evaluate(board)
+	} else {
+		score = ai.Evaluate(board)
+	}
+	if board.CurrentTurn == White {
+		score = -score
+	}
+	return score
+}
+
+// fiveMoves returns the empty positions where player would complete five
+func fiveMoves(board *Board, player Player) [][2]int {
+	var moves [][2]int
+	for _, candidate := range board.CandidateMoves(1) {
+		i, j := candidate[0], candidate[1]
+		board.setCell(i, j, player)
+		if board.CheckWin(i, j) {
+			moves = append(moves, candidate)
+		}
+		board.setCell(i, j, Empty)
+	}
+	return moves
+}
+
+// forcingMoves returns the moves making a four for the side to move, fours
+// first, followed by the moves making an open three when threes is set
+func (ai *AI) forcingMoves(board *Board, threes bool) [][2]int {
+	var fours, openThrees [][2]int
+	player := board.CurrentTurn
+	for _, candidate := range board.CandidateMoves(1) {
+		i, j := candidate[0], candidate[1]
+		board.setCell(i, j, player)
+		counts := patternCounts(board, i, j)
+		board.setCell(i, j, Empty)
+
+		switch {
+		case counts[PatternFour]+counts[PatternOpenFour] > 0:
+			fours = append(fours, candidate)
+		case threes && counts[PatternThree] > 0:
+			openThrees = append(openThrees, candidate)
+		}
+	}
+	return append(fours, openThrees...)
+}
@@ -0,0 +1,77 @@
+package game
+
+import (
+	"context"
+	"testing"
+)
+
+// almostFullBoard returns a board with every point taken but the center,
+// Black to fill it, laid out so that no row longer than two ever forms
+func almostFullBoard(t *testing.T) *Board {
+	t.Helper()
+	var grid [BoardSize][BoardSize]Player
+	for i := range grid {
+		for j := range grid[i] {
+			grid[i][j] = Black + Player((i/2+j)%2)
+		}
+	}
+	grid[BoardSize/2][BoardSize/2] = Empty
+	board, err := NewBoardFromPosition(grid, StandardRules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return board
+}
+
+func TestSearchScoresFullBoardAsDraw(t *testing.T) {
+	board := almostFullBoard(t)
+	filled := board.Clone()
+	if err := filled.PlaceStone(BoardSize/2, BoardSize/2); err != nil || !filled.IsDraw() {
+		t.Fatalf("filling the board does not draw: %v", err)
+	}
+
+	ai := NewAI(Black, Expert)
+	ai.SetEvaluator(nil)
+	search := newSearcher(context.Background(), ai, board)
+	move, score := search.root(2)
+	if center := [2]int{BoardSize / 2, BoardSize / 2}; move != center {
+		t.Fatalf("move is %v, want the last point %v", move, center)
+	}
+	if score != 0 {
+		t.Errorf("filling the board scores %d, want 0 for a draw", score)
+	}
+}
+
+func TestSearchSkipsRefusedMoves(t *testing.T) {
+	// Under the Pro opening Black's second stone may not go next to the
+	// center, so a stale hash move there is refused
+	board, err := NewBoardWithRules(Rules{WinLength: WinCondition, Opening: ProOpening})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, move := range [][2]int{{7, 7}, {7, 8}} {
+		if err := board.PlaceStone(move[0], move[1]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	before := board.Clone()
+
+	ai := NewAI(board.CurrentTurn, Expert)
+	ai.SetEvaluator(nil)
+	search := newSearcher(context.Background(), ai, board)
+	refused := [2]int{8, 8}
+	if err := search.play(refused); err == nil {
+		t.Fatalf("%v is allowed", refused)
+	}
+	search.ordering.storeBest(board.Hash(), refused)
+	if pv := search.principalVariation(3); len(pv) != 0 {
+		t.Errorf("the line is %v, want none past the refused move", pv)
+	}
+	move, _ := search.root(2)
+	if err := board.Clone().PlaceStone(move[0], move[1]); err != nil {
+		t.Errorf("best move %v cannot be played: %v", move, err)
+	}
+	if board.Grid != before.Grid || len(board.MoveHistory) != len(before.MoveHistory) {
+		t.Error("the search left the board changed")
+	}
+}
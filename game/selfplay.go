@@ -0,0 +1,210 @@
+package game
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"math/rand"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// SelfPlayConfig configures a run of engine-versus-engine games
+type SelfPlayConfig struct {
+	Games       int
+	Black       Difficulty
+	White       Difficulty
+	Depth       int   // Search depth for Expert and Master; they get no time limit so games repeat
+	RandomPlies int   // Random moves near the center opening each game, for variety
+	Workers     int   // Games played at once, 0 for one per CPU
+	Seed        int64 // Same seed, same games
+}
+
+// SelfPlayGame is a finished self-play game
+type SelfPlayGame struct {
+	Index  int      // Position of the game in the run, from 0
+	Moves  [][2]int // Moves in order, Black first
+	Winner Player   // Empty for a draw
+}
+
+// SelfPlay plays cfg.Games games and passes each to emit in order of Index.
+// It stops early with the context's error when ctx is cancelled, or with the
+// first error emit returns.
+func SelfPlay(ctx context.Context, cfg SelfPlayConfig, emit func(SelfPlayGame) error) error {
+	workers := cfg.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Every game gets its own seed up front, so results do not depend on
+	// which worker plays it
+	seeds := rand.New(rand.NewSource(cfg.Seed))
+	gameSeeds := make([]int64, cfg.Games)
+	results := make([]chan SelfPlayGame, cfg.Games)
+	for i := range gameSeeds {
+		gameSeeds[i] = seeds.Int63()
+		results[i] = make(chan SelfPlayGame, 1)
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range jobs {
+				moves, winner := playSelfPlayGame(ctx, cfg, gameSeeds[index])
+				results[index] <- SelfPlayGame{Index: index, Moves: moves, Winner: winner}
+			}
+		}()
+	}
+	go func() {
+		defer close(jobs)
+		for i := 0; i < cfg.Games; i++ {
+			select {
+			case jobs <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var err error
+	for _, result := range results {
+		select {
+		case game := <-result:
+			if err = ctx.Err(); err == nil {
+				err = emit(game)
+			}
+		case <-ctx.Done():
+			err = ctx.Err()
+		}
+		if err != nil {
+			break
+		}
+	}
+	cancel()
+	wg.Wait()
+	return err
+}
+
+// playSelfPlayGame plays one game, returning its moves and winner
+func playSelfPlayGame(ctx context.Context, cfg SelfPlayConfig, seed int64) ([][2]int, Player) {
+	rng := rand.New(rand.NewSource(seed))
+	board := NewBoard()
+
+	// Random opening near the center
+	for len(board.MoveHistory) < cfg.RandomPlies {
+		moves := board.CandidateMoves(1)
+		if len(board.MoveHistory) == 0 {
+			moves = [][2]int{}
+			for i := BoardSize/2 - 2; i <= BoardSize/2+2; i++ {
+				for j := BoardSize/2 - 2; j <= BoardSize/2+2; j++ {
+					moves = append(moves, [2]int{i, j})
+				}
+			}
+		}
+		move := moves[rng.Intn(len(moves))]
+		board.PlaceStone(move[0], move[1])
+		if board.IsGameFinished() {
+			break
+		}
+	}
+
+	engines := []*AI{NewAI(Black, cfg.Black), NewAI(White, cfg.White)}
+	for _, ai := range engines {
+		ai.SetSeed(rng.Int63())
+		ai.SetTimeLimit(0)
+		if cfg.Depth > 0 {
+			ai.SetDepth(cfg.Depth)
+		}
+	}
+	return PlayGame(ctx, engines[0], engines[1], board.Positions())
+}
+
+// PlayGame plays black against white after the given opening moves, until
+// the game is won, drawn or the board is full, and returns the moves and the
+// winner, Empty for a draw. The engines search with one worker, as games are
+// usually played in parallel. Play stops early when ctx is cancelled.
+func PlayGame(ctx context.Context, black, white *AI, opening [][2]int) ([][2]int, Player) {
+	board := NewBoard()
+	for _, move := range opening {
+		if board.IsGameFinished() || board.PlaceStone(move[0], move[1]) != nil {
+			break
+		}
+	}
+
+	engines := map[Player]*AI{Black: black, White: white}
+	black.SetWorkers(1)
+	white.SetWorkers(1)
+	for !board.IsGameFinished() {
+		row, col, err := engines[board.CurrentTurn].MakeMoveCtx(ctx, board)
+		if err != nil || board.PlaceStone(row, col) != nil {
+			break
+		}
+	}
+
+	return board.Positions(), board.Result().Winner
+}
+
+// SelfPlayRecord is one position of a self-play game, written as a line of
+// JSON by WriteSelfPlayRecords:
+//
+//	{"game":0,"ply":4,"board":"....","to_move":"black","move":"h9","result":1}
+//
+// board lists the 225 cells row by row from a15 to o1 ("." empty, "x" Black,
+// "o" White). move is the move played from the position in coordinate
+// notation, and result the outcome for the side to move: 1 won, 0 drawn or
+// unfinished, -1 lost.
+type SelfPlayRecord struct {
+	Game   int    `json:"game"`
+	Ply    int    `json:"ply"`
+	Board  string `json:"board"`
+	ToMove string `json:"to_move"`
+	Move   string `json:"move"`
+	Result int    `json:"result"`
+}
+
+// WriteSelfPlayRecords writes a record for every position of the game
+func WriteSelfPlayRecords(w io.Writer, game SelfPlayGame) error {
+	encoder := json.NewEncoder(w)
+	board := NewBoard()
+	for ply, move := range game.Moves {
+		record := SelfPlayRecord{
+			Game:   game.Index,
+			Ply:    ply,
+			Board:  boardString(board),
+			ToMove: "black",
+			Move:   FormatMove(move[0], move[1]),
+		}
+		if board.CurrentTurn == White {
+			record.ToMove = "white"
+		}
+		switch game.Winner {
+		case Empty:
+		case board.CurrentTurn:
+			record.Result = 1
+		default:
+			record.Result = -1
+		}
+		if err := encoder.Encode(record); err != nil {
+			return err
+		}
+		board.PlaceStone(move[0], move[1])
+	}
+	return nil
+}
+
+// boardString encodes the grid as one character per cell, row by row
+func boardString(board *Board) string {
+	var s strings.Builder
+	for i := 0; i < BoardSize; i++ {
+		for j := 0; j < BoardSize; j++ {
+			s.WriteByte(".xo"[board.Grid[i][j]])
+		}
+	}
+	return s.String()
+}
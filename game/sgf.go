@@ -0,0 +1,221 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FormatSGF returns the game on board in Smart Game Format (GM[4] for
+// Gomoku), with the result if it is over, a game comment if the engine
+// adjudicated it and any handicap stones as setup stones. Points are written column then row, both lettered from
+// the top left; a pass is an empty move. Red's moves in three-player games
+// use an R property of their own, and blocked points a BK property.
+func FormatSGF(board *Board) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "(;FF[4]GM[4]SZ[%d]AP[simple-gomoku]", BoardSize)
+	if result := board.Result(); result.Draw {
+		sb.WriteString("RE[0]")
+	} else if result.Finished() {
+		fmt.Fprintf(&sb, "RE[%c+]", "?BWR"[result.Winner])
+	}
+	if result := board.Result(); result.Reason == ReasonAdjudicated {
+		fmt.Fprintf(&sb, "GC[Result adjudicated by the engine, %.0f%% confidence]", result.Confidence*100)
+	}
+	if handicap := board.Handicap(); len(handicap) > 0 {
+		fmt.Fprintf(&sb, "HA[%d]AB", len(handicap))
+		for _, point := range handicap {
+			fmt.Fprintf(&sb, "[%c%c]", 'a'+point[1], 'a'+point[0])
+		}
+	}
+	if obstacles := board.Obstacles(); len(obstacles) > 0 {
+		sb.WriteString("BK")
+		for _, point := range obstacles {
+			fmt.Fprintf(&sb, "[%c%c]", 'a'+point[1], 'a'+point[0])
+		}
+	}
+
+	for _, move := range board.MoveHistory {
+		point := ""
+		if !move.IsPass() {
+			point = string([]byte{byte('a' + move.Col), byte('a' + move.Row)})
+		}
+		fmt.Fprintf(&sb, ";%c[%s]", "?BWR"[move.Player], point)
+	}
+	sb.WriteString(")")
+	return sb.String()
+}
+
+// ParseSGF reads the main line of a Gomoku game in Smart Game Format, such
+// as FormatSGF writes, and replays it on a new board with the given rules.
+// Variations are skipped, and so are properties other than moves and the
+// board size. Setup stones are not supported, other than Black handicap
+// stones and blocked points before the first move.
+func ParseSGF(s string, rules Rules) (*Board, error) {
+	board, err := NewBoardWithRules(rules)
+	if err != nil {
+		return nil, err
+	}
+	p := &sgfParser{text: s}
+	if err := p.gameTree(board, true); err != nil {
+		return nil, fmt.Errorf("SGF: %w", err)
+	}
+	return board, nil
+}
+
+// sgfParser reads SGF text from its current position
+type sgfParser struct {
+	text string
+	pos  int
+}
+
+// next skips white space and returns the next character, 0 at the end
+func (p *sgfParser) next() byte {
+	for p.pos < len(p.text) && strings.IndexByte(" \t\r\n", p.text[p.pos]) >= 0 {
+		p.pos++
+	}
+	if p.pos == len(p.text) {
+		return 0
+	}
+	return p.text[p.pos]
+}
+
+func (p *sgfParser) expect(c byte) error {
+	if p.next() != c {
+		return fmt.Errorf("expected %q at offset %d", c, p.pos)
+	}
+	p.pos++
+	return nil
+}
+
+// gameTree reads a game tree in parentheses, playing its nodes on board if
+// it is on the main line
+func (p *sgfParser) gameTree(board *Board, main bool) error {
+	if err := p.expect('('); err != nil {
+		return err
+	}
+	for p.next() == ';' {
+		p.pos++
+		if err := p.node(board, main); err != nil {
+			return err
+		}
+	}
+	for first := true; p.next() == '('; first = false {
+		if err := p.gameTree(board, main && first); err != nil {
+			return err
+		}
+	}
+	return p.expect(')')
+}
+
+// node reads the properties of one node
+func (p *sgfParser) node(board *Board, main bool) error {
+	for {
+		c := p.next()
+		if c < 'A' || c > 'Z' {
+			return nil
+		}
+		start := p.pos
+		for p.pos < len(p.text) && p.text[p.pos] >= 'A' && p.text[p.pos] <= 'Z' {
+			p.pos++
+		}
+		name := p.text[start:p.pos]
+		var values []string
+		for p.next() == '[' {
+			value, err := p.value()
+			if err != nil {
+				return err
+			}
+			values = append(values, value)
+		}
+		if len(values) == 0 {
+			return fmt.Errorf("property %s has no value", name)
+		}
+		if main {
+			if err := playProperty(board, name, values); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// value reads a property value in brackets, in which \ escapes the next
+// character
+func (p *sgfParser) value() (string, error) {
+	p.pos++ // The opening bracket
+	var sb strings.Builder
+	for p.pos < len(p.text) {
+		c := p.text[p.pos]
+		p.pos++
+		switch {
+		case c == ']':
+			return sb.String(), nil
+		case c == '\\' && p.pos < len(p.text):
+			sb.WriteByte(p.text[p.pos])
+			p.pos++
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return "", errors.New("unterminated property value")
+}
+
+// playProperty applies a property of the main line to board
+func playProperty(board *Board, name string, values []string) error {
+	value := values[0]
+	switch name {
+	case "SZ":
+		if size, err := strconv.Atoi(value); err != nil || size != BoardSize {
+			return fmt.Errorf("board size %s is not supported, only %d", value, BoardSize)
+		}
+	case "AB":
+		var points [][2]int
+		for _, value := range values {
+			if len(value) != 2 {
+				return fmt.Errorf("invalid handicap point %q", value)
+			}
+			points = append(points, [2]int{int(value[1] - 'a'), int(value[0] - 'a')})
+		}
+		if err := board.SetupHandicap(points); err != nil {
+			return fmt.Errorf("handicap: %w", err)
+		}
+	case "BK":
+		var points [][2]int
+		for _, value := range values {
+			if len(value) != 2 {
+				return fmt.Errorf("invalid blocked point %q", value)
+			}
+			points = append(points, [2]int{int(value[1] - 'a'), int(value[0] - 'a')})
+		}
+		if err := board.SetupObstacles(points); err != nil {
+			return fmt.Errorf("blocked points: %w", err)
+		}
+	case "AW", "AE":
+		return errors.New("setup stones other than Black handicap stones are not supported")
+	case "B", "W", "R":
+		player := Player(strings.Index("?BWR", name))
+		move := len(board.MoveHistory) + 1
+		// A White first stone is one White took over under the swap opening
+		swapped := player == White && move == 1 && board.Rules().Opening == SwapOpening && len(value) == 2
+		if player != board.CurrentTurn && !swapped {
+			return fmt.Errorf("move %d is played out of turn", move)
+		}
+		if value == "" || value == "tt" {
+			if err := board.Pass(); err != nil {
+				return fmt.Errorf("move %d: %w", move, err)
+			}
+			return nil
+		}
+		if len(value) != 2 {
+			return fmt.Errorf("move %d: invalid point %q", move, value)
+		}
+		if err := board.PlaceStone(int(value[1]-'a'), int(value[0]-'a')); err != nil {
+			return fmt.Errorf("move %d: %w", move, err)
+		}
+		if swapped {
+			return board.Swap()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package game
+
+import "math"
+
+const (
+	// MinElo and MaxElo bound the ratings NewEloAI can aim for, on the
+	// same scale as the ladder
+	MinElo = 800
+	MaxElo = 2400
+
+	// EasyErrorRate is the default chance of a beginner's mistake in Easy mode
+	EasyErrorRate = 0.35
+
+	easyFocus = 2  // Rows and columns around the last moves a distracted player sees
+	easyNoise = 20 // Random score added to Easy's moves so ties vary
+
+	maxEloDepth  = 6   // Search depth at MaxElo
+	maxErrorRate = 0.5 // Chance of an inaccurate move at MinElo
+	errorBreadth = 6   // Inaccurate moves are drawn from this many best candidates
+)
+
+// NewEloAI creates a searching engine limited to play at roughly the given
+// rating. Weaker settings search fewer plies and more often play one of the
+// other good-looking moves instead of the best one, but never miss a five or
+// the block of one. At 1400 it scores about even against Hard, the ladder's
+// 1400 level.
+func NewEloAI(player Player, elo int) *AI {
+	elo = max(MinElo, min(elo, MaxElo))
+	strength := float64(elo-MinElo) / float64(MaxElo-MinElo)
+
+	ai := NewAI(player, Expert)
+	ai.SetDepth(1 + int(strength*float64(maxEloDepth-1)+0.5))
+	ai.SetErrorRate(maxErrorRate * (1 - strength) * (1 - strength))
+	ai.elo = elo
+	return ai
+}
+
+// SetErrorRate sets the chance, from 0 to 1, that the Easy engine makes a
+// beginner's mistake, or that the Expert and Master engines play an
+// inaccurate move instead of searching
+func (ai *AI) SetErrorRate(rate float64) {
+	ai.errorRate = math.Max(0, math.Min(rate, 1))
+}
+
+// makesError decides whether the next move should be an inaccurate one
+func (ai *AI) makesError() bool {
+	return ai.errorRate > 0 && ai.float64() < ai.errorRate
+}
+
+// inaccurateMove picks one of the most promising candidate moves at random,
+// the kind of plausible move a weaker player might choose
+func (ai *AI) inaccurateMove(board *Board) [2]int {
+	moves := newMoveOrdering().order(ai, board, 0, 0)
+	moves = moves[:min(errorBreadth, len(moves))]
+	return moves[ai.intn(len(moves))]
+}
@@ -0,0 +1,218 @@
+package game
+
+import (
+	"bufio"
+	"context"
+	_ "embed"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+//go:embed suites/tactics.txt
+var tacticsSuiteData string
+
+// TestPosition is one position of a tactical test suite.
+//
+// Suites are text files with one position per line, in the spirit of EPD for
+// chess. A line holds the moves leading to the position in coordinate notation,
+// Black first, followed by operations separated by semicolons:
+//
+//	# Black completes a double four
+//	f8 e8 g8 i4 h8 a1 i5 o1 i6 a15 i7 o15; bm i8; id double-four
+//
+// "bm" lists the best moves, any of which solves the position, "am" lists
+// moves to avoid and "id" names the position. A position with neither bm nor
+// am is only timed. Blank lines and lines starting with # are skipped.
+type TestPosition struct {
+	ID    string
+	Moves [][2]int
+	Best  [][2]int
+	Avoid [][2]int
+}
+
+// DefaultTestSuite returns the tactical suite embedded in the binary: forced
+// wins, double fours, four-threes and broken threes that must be blocked
+func DefaultTestSuite() []TestPosition {
+	positions, err := ParseTestSuite(strings.NewReader(tacticsSuiteData))
+	if err != nil {
+		panic(err)
+	}
+	return positions
+}
+
+// ParseTestSuite reads a test suite, checking that every position can be set
+// up and that its moves are empty points
+func ParseTestSuite(r io.Reader) ([]TestPosition, error) {
+	var positions []TestPosition
+	scanner := bufio.NewScanner(r)
+	for number := 1; scanner.Scan(); number++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		p, err := parseTestPosition(line)
+		if err != nil {
+			return nil, fmt.Errorf("test suite line %d: %w", number, err)
+		}
+		if p.ID == "" {
+			p.ID = fmt.Sprintf("line-%d", number)
+		}
+		positions = append(positions, p)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return positions, nil
+}
+
+// LoadTestSuiteFile reads a test suite from a file
+func LoadTestSuiteFile(path string) ([]TestPosition, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseTestSuite(f)
+}
+
+func parseTestPosition(line string) (TestPosition, error) {
+	var p TestPosition
+	fields := strings.Split(line, ";")
+	moves, err := parseMoveList(fields[0])
+	if err != nil {
+		return p, err
+	}
+	p.Moves = moves
+	for _, field := range fields[1:] {
+		op, args, _ := strings.Cut(strings.TrimSpace(field), " ")
+		args = strings.TrimSpace(args)
+		switch op {
+		case "":
+		case "bm":
+			p.Best, err = parseMoveList(args)
+		case "am":
+			p.Avoid, err = parseMoveList(args)
+		case "id":
+			p.ID = args
+		default:
+			err = fmt.Errorf("unknown operation %q", op)
+		}
+		if err != nil {
+			return p, err
+		}
+	}
+
+	board, err := p.Board()
+	if err != nil {
+		return p, err
+	}
+	for _, move := range append(slices.Clip(p.Best), p.Avoid...) {
+		if move == PassMove || board.Grid[move[0]][move[1]] != Empty {
+			return p, fmt.Errorf("%s is not an empty point", FormatMove(move[0], move[1]))
+		}
+	}
+	return p, nil
+}
+
+func parseMoveList(s string) ([][2]int, error) {
+	var moves [][2]int
+	for _, field := range strings.Fields(s) {
+		row, col, err := ParseMove(field)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", field, err)
+		}
+		moves = append(moves, [2]int{row, col})
+	}
+	return moves, nil
+}
+
+// Board plays the moves of the position on a new board
+func (p TestPosition) Board() (*Board, error) {
+	board := NewBoard()
+	for _, move := range p.Moves {
+		var err error
+		if move == PassMove {
+			err = board.Pass()
+		} else {
+			err = board.PlaceStone(move[0], move[1])
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", FormatMove(move[0], move[1]), err)
+		}
+	}
+	if board.IsGameFinished() {
+		return nil, fmt.Errorf("the game is already over")
+	}
+	return board, nil
+}
+
+// Tactical reports whether the position has a right answer, rather than
+// being timed only
+func (p TestPosition) Tactical() bool {
+	return len(p.Best) > 0 || len(p.Avoid) > 0
+}
+
+// Solves reports whether move solves the position: it is one of the best
+// moves, if any are given, and none of the moves to avoid
+func (p TestPosition) Solves(move [2]int) bool {
+	if len(p.Best) > 0 && !slices.Contains(p.Best, move) {
+		return false
+	}
+	return !slices.Contains(p.Avoid, move)
+}
+
+// TestResult is the engine's answer to one test position
+type TestResult struct {
+	Position TestPosition
+	Move     [2]int
+	Solved   bool
+	Elapsed  time.Duration
+}
+
+// RunTestSuite asks a new engine from newAI for a move in every position and
+// checks it. The opening book is turned off, since suites test the search.
+// It stops with the context's error when ctx is cancelled.
+func RunTestSuite(ctx context.Context, positions []TestPosition, newAI func(Player) *AI) ([]TestResult, error) {
+	results := make([]TestResult, 0, len(positions))
+	for _, p := range positions {
+		board, err := p.Board()
+		if err != nil {
+			return results, fmt.Errorf("%s: %w", p.ID, err)
+		}
+		ai := newAI(board.CurrentTurn)
+		ai.SetBook(nil)
+
+		start := time.Now()
+		row, col, err := ai.MakeMoveCtx(ctx, board)
+		if err != nil {
+			return results, err
+		}
+		move := [2]int{row, col}
+		results = append(results, TestResult{
+			Position: p,
+			Move:     move,
+			Solved:   p.Solves(move),
+			Elapsed:  time.Since(start),
+		})
+	}
+	return results, nil
+}
+
+// TestScore counts the tactical positions among results and how many of them
+// were solved
+func TestScore(results []TestResult) (solved, total int) {
+	for _, r := range results {
+		if !r.Position.Tactical() {
+			continue
+		}
+		total++
+		if r.Solved {
+			solved++
+		}
+	}
+	return solved, total
+}
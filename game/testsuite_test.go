@@ -0,0 +1,30 @@
+package game
+
+import (
+	"context"
+	"testing"
+)
+
+// TestTacticalSuite runs the embedded tactical suite at Expert depth 6,
+// as cmd/bench does by default, and fails on any position left unsolved
+func TestTacticalSuite(t *testing.T) {
+	if testing.Short() {
+		t.Skip("searches every position to depth 6")
+	}
+	suite := DefaultTestSuite()
+	results, err := RunTestSuite(context.Background(), suite, func(player Player) *AI {
+		ai := NewAI(player, Expert)
+		ai.SetDepth(6)
+		ai.SetTimeLimit(0)
+		ai.SetErrorRate(0)
+		return ai
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range results {
+		if r.Position.Tactical() && !r.Solved {
+			t.Errorf("%s: played %s", r.Position.ID, FormatMove(r.Move[0], r.Move[1]))
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package game
+
+import (
+	"context"
+	"math"
+)
+
+// NextPlayer returns the player who moves after player: Black and White take
+// turns, and in three-player games Red moves after White
+func (r Rules) NextPlayer(player Player) Player {
+	switch {
+	case player == Black:
+		return White
+	case player == White && r.ThreePlayers:
+		return Red
+	}
+	return Black
+}
+
+// Players returns the players of a game under the rules, in turn order
+func (r Rules) Players() []Player {
+	if r.ThreePlayers {
+		return []Player{Black, White, Red}
+	}
+	return []Player{Black, White}
+}
+
+// opponents returns the players other than player, in the order they move
+// after it
+func (r Rules) opponents(player Player) []Player {
+	var opponents []Player
+	for next := r.NextPlayer(player); next != player; next = r.NextPlayer(next) {
+		opponents = append(opponents, next)
+	}
+	return opponents
+}
+
+// makeThreePlayerMove plays for the side to move in a three-player game,
+// whatever the AI's own color, so one AI can play both computer players.
+// The search and Monte Carlo engines assume a single opponent, so every
+// difficulty plays by move scores here. Each opponent is taken to play for
+// itself: the next player's wins and winning shapes are stopped first,
+// since it moves first, and the last player's threats count for less, as
+// the next player may have to stop them too.
+func (ai *AI) makeThreePlayerMove(ctx context.Context, board *Board) (int, int) {
+	mover := *ai
+	mover.player = board.CurrentTurn
+	if board.stones == 0 {
+		move := board.centerMove()
+		return move[0], move[1]
+	}
+
+	// 1. Win, or stop an opponent's win, the next player's first
+	if move := mover.findWinningMove(board, mover.player); move[0] >= 0 {
+		return move[0], move[1]
+	}
+	opponents := board.rules.opponents(mover.player)
+	for _, opponent := range opponents {
+		if move := mover.findWinningMove(board, opponent); move[0] >= 0 {
+			return move[0], move[1]
+		}
+	}
+
+	// 2. Make an open four or double four, or stop the next player's
+	if move := mover.findComboMove(board, mover.player, comboWinning); move[0] >= 0 {
+		return move[0], move[1]
+	}
+	if ai.difficulty != Easy {
+		if move := mover.findComboMove(board, opponents[0], comboWinning); move[0] >= 0 {
+			return move[0], move[1]
+		}
+	}
+
+	// 3. Play the best scored move; Easy adds noise as it does against one
+	// opponent
+	best, bestScore := [2]int{-1, -1}, math.MinInt
+	for _, move := range board.CandidateMoves(2) {
+		if ctx.Err() != nil {
+			break
+		}
+		score := mover.evaluateThreePlayer(board, move[0], move[1])
+		if ai.difficulty == Easy {
+			score += ai.intn(easyNoise)
+		}
+		if score > bestScore {
+			best, bestScore = move, score
+		}
+	}
+	return best[0], best[1]
+}
+
+// evaluateThreePlayer scores a move for the AI in a three-player game, by
+// the shapes it makes and the threats it takes from each opponent, those of
+// the player moving last counting half
+func (ai *AI) evaluateThreePlayer(board *Board, row, col int) int {
+	weights := ai.weights.Hard
+	score := ai.evaluateAttack(board, row, col)
+
+	board.setCell(row, col, ai.player)
+	score += ai.threatScore(board, row, col, weights.Attack)
+	board.setCell(row, col, Empty)
+
+	for k, opponent := range board.rules.opponents(ai.player) {
+		board.setCell(row, col, opponent)
+		defense := ai.threatScore(board, row, col, weights.Defense)
+		board.setCell(row, col, Empty)
+		if k > 0 {
+			defense /= 2
+		}
+		score += defense
+	}
+	return score
+}
@@ -0,0 +1,298 @@
+// Package tournament runs engine-versus-engine matches, round robin or
+// gauntlet, and estimates the engines' relative Elo ratings from the results.
+package tournament
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"runtime"
+	"sort"
+	"sync"
+	"text/tabwriter"
+
+	"simple-gomoku/game"
+)
+
+// Format decides which engines play each other
+type Format int
+
+const (
+	RoundRobin Format = iota // Every engine plays every other
+	Gauntlet                 // The first engine plays every other
+)
+
+const (
+	eloIterations = 1000
+	eloZ          = 1.96 // 95% confidence for the error margins
+)
+
+// Engine is a tournament entrant
+type Engine struct {
+	Name string
+	New  func(player game.Player) *game.AI // Creates the engine's AI for one game
+}
+
+// DifficultyEngine returns an entrant playing a built-in difficulty. Expert
+// and Master search to a fixed depth with no time limit when depth > 0, so
+// their games repeat.
+func DifficultyEngine(name string, difficulty game.Difficulty, depth int) Engine {
+	return Engine{Name: name, New: func(player game.Player) *game.AI {
+		ai := game.NewAI(player, difficulty)
+		if depth > 0 {
+			ai.SetDepth(depth)
+			ai.SetTimeLimit(0)
+		}
+		return ai
+	}}
+}
+
+// LadderEngine returns an entrant playing a ladder level
+func LadderEngine(level game.LadderLevel) Engine {
+	return Engine{Name: level.Name, New: level.NewAI}
+}
+
+// Config configures a tournament
+type Config struct {
+	Engines  []Engine
+	Format   Format
+	Openings [][][2]int // Each pairing plays every opening with each color; nil for the book's openings
+	Rounds   int        // Times each pairing plays the openings, at least 1
+	Workers  int        // Games played at once, 0 for one per CPU
+	Seed     int64      // Same seed, same games, for engines without time limits
+}
+
+// Game is one finished tournament game
+type Game struct {
+	Black, White int // Indexes into Config.Engines
+	Opening      int // Index into the openings played
+	Moves        [][2]int
+	Winner       game.Player // Empty for a draw
+}
+
+// score returns the points engine scored in the game, or -1 if it did not play
+func (g Game) score(engine int) float64 {
+	switch {
+	case engine != g.Black && engine != g.White:
+		return -1
+	case g.Winner == game.Empty:
+		return 0.5
+	case (g.Winner == game.Black) == (engine == g.Black):
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Result holds the games of a tournament
+type Result struct {
+	Engines []string
+	Games   []Game
+}
+
+// Standing is one engine's line in the results table
+type Standing struct {
+	Name                string
+	Wins, Draws, Losses int
+	Score               float64 // Points, a draw counting half
+	Elo, EloMargin      float64 // Rating relative to the field's average, and its 95% margin
+}
+
+// Games returns the number of games the engine played
+func (s Standing) Games() int {
+	return s.Wins + s.Draws + s.Losses
+}
+
+// Run plays the tournament, passing each game to progress, if not nil, as it
+// finishes. It stops early with the context's error when ctx is cancelled.
+func Run(ctx context.Context, cfg Config, progress func(Game)) (*Result, error) {
+	if len(cfg.Engines) < 2 {
+		return nil, fmt.Errorf("a tournament needs at least 2 engines, got %d", len(cfg.Engines))
+	}
+	openings := cfg.Openings
+	if openings == nil {
+		for _, opening := range game.DefaultBook().Openings() {
+			openings = append(openings, opening.Moves)
+		}
+	}
+	if len(openings) == 0 {
+		openings = [][][2]int{nil}
+	}
+	workers := cfg.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	// Schedule every game up front, each with its own seed, so results do
+	// not depend on which worker plays it
+	seeds := rand.New(rand.NewSource(cfg.Seed))
+	var games []Game
+	var gameSeeds []int64
+	for _, pair := range pairings(len(cfg.Engines), cfg.Format) {
+		for round := 0; round < max(cfg.Rounds, 1); round++ {
+			for i := range openings {
+				games = append(games,
+					Game{Black: pair[0], White: pair[1], Opening: i},
+					Game{Black: pair[1], White: pair[0], Opening: i})
+				gameSeeds = append(gameSeeds, seeds.Int63(), seeds.Int63())
+			}
+		}
+	}
+
+	jobs := make(chan int)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range jobs {
+				g := &games[index]
+				rng := rand.New(rand.NewSource(gameSeeds[index]))
+				black := cfg.Engines[g.Black].New(game.Black)
+				white := cfg.Engines[g.White].New(game.White)
+				black.SetSeed(rng.Int63())
+				white.SetSeed(rng.Int63())
+				g.Moves, g.Winner = game.PlayGame(ctx, black, white, openings[g.Opening])
+				if progress != nil && ctx.Err() == nil {
+					mu.Lock()
+					progress(*g)
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	for i := range games {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	result := &Result{Games: games}
+	for _, engine := range cfg.Engines {
+		result.Engines = append(result.Engines, engine.Name)
+	}
+	return result, nil
+}
+
+// pairings returns the pairs of engines that play each other
+func pairings(engines int, format Format) [][2]int {
+	var pairs [][2]int
+	for i := 0; i < engines; i++ {
+		for j := i + 1; j < engines; j++ {
+			if format == Gauntlet && i > 0 {
+				break
+			}
+			pairs = append(pairs, [2]int{i, j})
+		}
+	}
+	return pairs
+}
+
+// Standings returns the engines' results, best score first
+func (r *Result) Standings() []Standing {
+	standings := make([]Standing, len(r.Engines))
+	for i, name := range r.Engines {
+		standings[i].Name = name
+		for _, g := range r.Games {
+			switch g.score(i) {
+			case 1:
+				standings[i].Wins++
+			case 0.5:
+				standings[i].Draws++
+			case 0:
+				standings[i].Losses++
+			}
+		}
+		standings[i].Score = float64(standings[i].Wins) + float64(standings[i].Draws)/2
+	}
+
+	ratings := r.ratings()
+	for i := range standings {
+		standings[i].Elo = ratings[i]
+		standings[i].EloMargin = eloMargin(standings[i])
+	}
+	sort.SliceStable(standings, func(a, b int) bool {
+		return standings[a].Score > standings[b].Score
+	})
+	return standings
+}
+
+// ratings returns the Elo ratings under which the results are most likely,
+// centred on 0, found with Hunter's MM algorithm for the Bradley-Terry model.
+// A draw counts as half a win and half a loss, and every engine gets one
+// extra draw against a 0-rated opponent so perfect scores give finite
+// ratings.
+func (r *Result) ratings() []float64 {
+	strengths := make([]float64, len(r.Engines)) // 10^(rating/400)
+	for i := range strengths {
+		strengths[i] = 1
+	}
+	for iter := 0; iter < eloIterations; iter++ {
+		next := make([]float64, len(strengths))
+		for i := range strengths {
+			score := 0.5
+			denominator := 1 / (strengths[i] + 1)
+			for _, g := range r.Games {
+				points := g.score(i)
+				if points < 0 {
+					continue
+				}
+				opponent := g.Black
+				if opponent == i {
+					opponent = g.White
+				}
+				score += points
+				denominator += 1 / (strengths[i] + strengths[opponent])
+			}
+			next[i] = score / denominator
+		}
+		strengths = next
+	}
+
+	ratings := make([]float64, len(strengths))
+	mean := 0.0
+	for i, strength := range strengths {
+		ratings[i] = 400 * math.Log10(strength)
+		mean += ratings[i]
+	}
+	mean /= float64(len(ratings))
+	for i := range ratings {
+		ratings[i] -= mean
+	}
+	return ratings
+}
+
+// eloMargin returns the 95% margin of the engine's rating from the spread of
+// its score
+func eloMargin(s Standing) float64 {
+	games := float64(s.Games())
+	if games == 0 {
+		return 0
+	}
+	p := (s.Score + 0.5) / (games + 1)
+	stderr := math.Sqrt(p * (1 - p) / games)
+	return eloZ * stderr * 400 / (math.Ln10 * p * (1 - p))
+}
+
+// WriteTable writes the standings as a text table
+func (r *Result) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "Rank\tEngine\tGames\tWins\tDraws\tLosses\tScore\tElo\t")
+	for i, s := range r.Standings() {
+		fmt.Fprintf(tw, "%d\t%s\t%d\t%d\t%d\t%d\t%.1f\t%+.0f ± %.0f\t\n",
+			i+1, s.Name, s.Games(), s.Wins, s.Draws, s.Losses, s.Score, s.Elo, s.EloMargin)
+	}
+	return tw.Flush()
+}
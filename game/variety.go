@@ -0,0 +1,60 @@
+package game
+
+import "math"
+
+const (
+	// VarietyPlies is how many plies from the start of a game the variety
+	// setting applies to. It fades over them, so the engines play their
+	// best moves from the middle game on.
+	VarietyPlies = 12
+
+	varietyMoves = 5     // Top moves a varied move is drawn from
+	varietyScale = 300.0 // Softmax temperature at full variety, in move score points
+)
+
+// SetVariety sets how far the AI strays from its best move in the opening,
+// from 0, always the best move, to 1. Moves are drawn from the top few with
+// softmax weights, at a temperature that falls to nothing over the first
+// VarietyPlies plies, so repeated games against the same engine open
+// differently while the middle game is played at full strength.
+func (ai *AI) SetVariety(variety float64) {
+	ai.variety = math.Max(0, math.Min(1, variety))
+}
+
+// varietyMove draws the move to play from the top moves for the side to
+// move, counting best, the move the AI chose, among them. It returns false
+// when the variety setting does not apply to the position.
+func (ai *AI) varietyMove(board *Board, best [2]int) ([2]int, bool) {
+	ply := len(board.MoveHistory)
+	if ai.variety == 0 || ply >= VarietyPlies || best[0] < 0 {
+		return best, false
+	}
+	moves := ai.TopMoves(board, varietyMoves)
+	if len(moves) == 0 {
+		return best, false
+	}
+	top := moves[0].Score
+	found := false
+	for _, move := range moves {
+		found = found || [2]int{move.Row, move.Col} == best
+	}
+	if !found {
+		moves = append(moves, Suggestion{Row: best[0], Col: best[1], Score: top})
+	}
+
+	temperature := ai.variety * varietyScale * float64(VarietyPlies-ply) / VarietyPlies
+	weights := make([]float64, len(moves))
+	total := 0.0
+	for i, move := range moves {
+		weights[i] = math.Exp(float64(move.Score-top) / temperature)
+		total += weights[i]
+	}
+	r := ai.float64() * total
+	for i, weight := range weights {
+		if r < weight {
+			return [2]int{moves[i].Row, moves[i].Col}, true
+		}
+		r -= weight
+	}
+	return best, true
+}
@@ -0,0 +1,145 @@
+package game
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+//go:embed weights/default.json
+var defaultWeightsData []byte
+
+// builtinWeights are the embedded weights, defaultWeights those new engines use
+var (
+	builtinWeights = mustLoadWeights(bytes.NewReader(defaultWeightsData))
+	defaultWeights = builtinWeights
+)
+
+// Weights are the scores the Easy, Medium and Hard engines give candidate
+// moves. They are stored as JSON, see weights/default.json for the built-in
+// values.
+type Weights struct {
+	Win              int `json:"win"`                // Completing five
+	BlockWin         int `json:"block_win"`          // Stopping the opponent's five
+	CenterDistance   int `json:"center_distance"`    // Taken off per step from the center
+	LastMoveDistance int `json:"last_move_distance"` // Taken off per step from the last move
+
+	Line   LineWeights   `json:"line"`
+	Medium MediumWeights `json:"medium"`
+	Hard   HardWeights   `json:"hard"`
+}
+
+// LineWeights score the stones within four points of a move along one line
+type LineWeights struct {
+	Four       int `json:"four"`        // Four own stones in a row
+	Three      int `json:"three"`       // Three in a row with room to grow
+	Two        int `json:"two"`         // Two in a row with room to grow
+	BlockThree int `json:"block_three"` // Three opponent stones in a row
+	BlockTwo   int `json:"block_two"`   // Two opponent stones in a row with room to grow
+	Stone      int `json:"stone"`       // Per own stone
+	Empty      int `json:"empty"`       // Per empty point
+}
+
+// ThreatWeights score the threats a move makes, or stops when played by the
+// opponent. Zero weights are not looked for.
+type ThreatWeights struct {
+	OpenFour    int `json:"open_four"`
+	FourCombo   int `json:"four_combo"` // Double four or four-three
+	DoubleThree int `json:"double_three"`
+	OpenThree   int `json:"open_three"`
+}
+
+// MediumWeights are added to the move score in Medium mode
+type MediumWeights struct {
+	Attack  ThreatWeights `json:"attack"`
+	Defense ThreatWeights `json:"defense"`
+}
+
+// HardWeights are added to the move score in Hard mode
+type HardWeights struct {
+	Attack         ThreatWeights `json:"attack"`
+	Defense        ThreatWeights `json:"defense"`
+	CenterDistance int           `json:"center_distance"` // Taken off per step from the center
+	AdjacentStone  int           `json:"adjacent_stone"`  // Per stone next to the move
+	NearbyStone    int           `json:"nearby_stone"`    // Per other stone within two points
+	EdgeDivisor    int           `json:"edge_divisor"`    // Divides the score next to the edge, 1 for no effect
+}
+
+// DefaultWeights returns the weights new engines use
+func DefaultWeights() Weights {
+	return defaultWeights
+}
+
+// SetDefaultWeights sets the weights new engines use in place of the
+// built-in ones. Engines already created keep theirs, so it is meant to be
+// called once at startup.
+func SetDefaultWeights(weights Weights) {
+	defaultWeights = weights
+}
+
+// LoadWeights reads weights in JSON format. Values missing from the file
+// keep their built-in defaults, so a file may hold only the weights being
+// tuned; unknown names are an error to catch typos.
+func LoadWeights(r io.Reader) (Weights, error) {
+	weights := builtinWeights
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&weights); err != nil {
+		return Weights{}, fmt.Errorf("invalid weights: %w", err)
+	}
+	if weights.Hard.EdgeDivisor < 1 {
+		return Weights{}, fmt.Errorf("invalid weights: edge_divisor must be at least 1")
+	}
+	return weights, nil
+}
+
+// LoadWeightsFile reads weights from a JSON file
+func LoadWeightsFile(path string) (Weights, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Weights{}, err
+	}
+	defer f.Close()
+	return LoadWeights(f)
+}
+
+func mustLoadWeights(r io.Reader) Weights {
+	var weights Weights
+	if err := json.NewDecoder(r).Decode(&weights); err != nil {
+		panic(err)
+	}
+	return weights
+}
+
+// SetWeights sets the weights the Easy, Medium and Hard engines score
+// moves with
+func (ai *AI) SetWeights(weights Weights) {
+	ai.weights = weights
+}
+
+// Weights returns the weights the engine scores moves with
+func (ai *AI) Weights() Weights {
+	return ai.weights
+}
+
+// threatScore scores the threats player makes by playing at (row, col),
+// which must already hold the stone
+func (ai *AI) threatScore(board *Board, row, col int, weights ThreatWeights) int {
+	score := 0
+	if weights.OpenFour != 0 && ai.hasOpenFour(board, row, col) {
+		score += weights.OpenFour
+	}
+	if weights.FourCombo != 0 && ai.hasFourCombo(board, row, col) {
+		score += weights.FourCombo
+	}
+	if weights.DoubleThree != 0 && ai.hasDoubleThree(board, row, col) {
+		score += weights.DoubleThree
+	}
+	if weights.OpenThree != 0 && ai.hasOpenThree(board, row, col) {
+		score += weights.OpenThree
+	}
+	return score
+}
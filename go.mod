@@ -1,7 +1,12 @@
 module simple-gomoku
 
 go 1.24.1
-require fyne.io/fyne/v2 v2.5.5
+
+require (
+	fyne.io/fyne/v2 v2.5.5
+	github.com/yalue/onnxruntime_go v1.27.0
+	golang.org/x/image v0.18.0
+)
 
 require (
 	fyne.io/systray v1.11.0 // indirect
@@ -28,7 +33,6 @@ require (
 	github.com/srwiley/rasterx v0.0.0-20220730225603-2ab79fcdd4ef // indirect
 	github.com/stretchr/testify v1.8.4 // indirect
 	github.com/yuin/goldmark v1.7.1 // indirect
-	golang.org/x/image v0.18.0 // indirect
 	golang.org/x/mobile v0.0.0-20231127183840-76ac6878050a // indirect
 	golang.org/x/net v0.36.0 // indirect
 	golang.org/x/sys v0.30.0 // indirect
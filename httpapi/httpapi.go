@@ -0,0 +1,186 @@
+// Package httpapi serves the engine over HTTP with a small JSON API, so
+// other programs can analyze positions without linking Go code.
+//
+// POST /analyze takes a position as the moves leading to it and returns the
+// engine's move for the side to move:
+//
+//	{"moves": "h8 h9 i9", "engine": "expert", "depth": 6, "time_ms": 2000}
+//
+//	{"to_move": "white", "move": "g7", "score": 120, "depth": 6,
+//	 "pv": ["g7", "i10", "j10"], "nodes": 51234, "time_ms": 412}
+//
+// Moves use the game's coordinate notation, columns a to o from the left and
+// rows 1 to 15 from the bottom, with "pass" for a pass. Instead of moves, a
+// request may give "game", a game code from game.FormatGameCode, which also
+// carries the rules and any setup stones. Errors are returned as
+// {"error": "..."} with a 4xx status.
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"simple-gomoku/game"
+)
+
+// maxRequestSize limits the size of request bodies
+const maxRequestSize = 64 << 10
+
+// AnalyzeRequest is the body of a POST /analyze request
+type AnalyzeRequest struct {
+	Moves  string `json:"moves"`   // Moves leading to the position, Black first
+	Game   string `json:"game"`    // Game code of the position, instead of Moves
+	Engine string `json:"engine"`  // Name game.ParseDifficulty reads, the server's default if empty
+	Depth  int    `json:"depth"`   // Search depth for expert and master, 0 for the engine's default
+	TimeMS int    `json:"time_ms"` // Thinking time for expert and master, 0 for the engine's default
+}
+
+// AnalyzeResponse is the reply to a POST /analyze request
+type AnalyzeResponse struct {
+	ToMove string   `json:"to_move"`
+	Move   string   `json:"move"`
+	Score  int      `json:"score"`
+	Depth  int      `json:"depth"` // 0 when the move was found without a search
+	PV     []string `json:"pv"`
+	Nodes  int      `json:"nodes"`
+	TimeMS int64    `json:"time_ms"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// Server answers analysis requests
+type Server struct {
+	Engine  game.Difficulty // Engine used when a request names none
+	MaxTime time.Duration   // Longest a request may think, 0 for no limit
+}
+
+// NewServer returns a server analyzing with the given engine by default,
+// thinking at most maxTime per request
+func NewServer(engine game.Difficulty, maxTime time.Duration) *Server {
+	return &Server{Engine: engine, MaxTime: maxTime}
+}
+
+// Handler returns the HTTP handler serving the API
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /analyze", s.analyze)
+	return mux
+}
+
+func (s *Server) analyze(w http.ResponseWriter, r *http.Request) {
+	var req AnalyzeRequest
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestSize))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request: %w", err))
+		return
+	}
+
+	board, err := setUp(req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if board.IsGameFinished() {
+		writeError(w, http.StatusUnprocessableEntity, errors.New("the game is already over"))
+		return
+	}
+	ai, err := s.newAI(req, board.CurrentTurn)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	start := time.Now()
+	analysis, err := ai.Analyze(r.Context(), board)
+	if err != nil {
+		return // The client went away
+	}
+	resp := AnalyzeResponse{
+		ToMove: strings.ToLower(board.CurrentTurn.String()),
+		Move:   game.FormatMove(analysis.Move[0], analysis.Move[1]),
+		Score:  analysis.Score,
+		Depth:  analysis.Depth,
+		PV:     make([]string, len(analysis.PV)),
+		Nodes:  analysis.Nodes,
+		TimeMS: time.Since(start).Milliseconds(),
+	}
+	for i, move := range analysis.PV {
+		resp.PV[i] = game.FormatMove(move[0], move[1])
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// newAI returns the engine a request asks for, within the server's limits
+func (s *Server) newAI(req AnalyzeRequest, player game.Player) (*game.AI, error) {
+	difficulty := s.Engine
+	if req.Engine != "" {
+		d, err := game.ParseDifficulty(req.Engine)
+		if err != nil {
+			return nil, err
+		}
+		difficulty = d
+	}
+	if req.Depth < 0 || req.TimeMS < 0 {
+		return nil, errors.New("depth and time_ms must not be negative")
+	}
+
+	ai := game.NewAI(player, difficulty)
+	ai.SetBook(nil) // Book moves come without a score or line
+	if req.Depth > 0 {
+		ai.SetDepth(req.Depth)
+	}
+	limit := game.ExpertTimeLimit
+	if difficulty == game.Master {
+		limit = game.MasterTimeLimit
+	}
+	if req.TimeMS > 0 {
+		limit = time.Duration(req.TimeMS) * time.Millisecond
+	}
+	if s.MaxTime > 0 {
+		limit = min(limit, s.MaxTime)
+	}
+	ai.SetTimeLimit(limit)
+	return ai, nil
+}
+
+// setUp replays the position of a request on a new board
+func setUp(req AnalyzeRequest) (*game.Board, error) {
+	if req.Game != "" {
+		if req.Moves != "" {
+			return nil, errors.New("give either moves or a game code, not both")
+		}
+		return game.ParseGameCode(req.Game)
+	}
+	board := game.NewBoard()
+	for _, move := range strings.Fields(req.Moves) {
+		row, col, err := game.ParseMove(move)
+		if err == nil {
+			if [2]int{row, col} == game.PassMove {
+				err = board.Pass()
+			} else {
+				err = board.PlaceStone(row, col)
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", move, err)
+		}
+	}
+	return board, nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
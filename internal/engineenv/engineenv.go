@@ -0,0 +1,39 @@
+// Package engineenv applies the engine settings the gomoku commands take
+// from the environment: tuned move weights and an ONNX evaluation network.
+// The game package reads no environment of its own, so each command calls
+// Load once at startup, before creating engines.
+package engineenv
+
+import (
+	"fmt"
+	"os"
+
+	"simple-gomoku/game"
+)
+
+// Environment variables read by Load
+const (
+	WeightsEnv     = "GOMOKU_WEIGHTS"      // JSON weights file for new engines, see game.LoadWeights
+	ONNXModelEnv   = "GOMOKU_ONNX_MODEL"   // ONNX model new Expert and Master engines evaluate with
+	ONNXLibraryEnv = "GOMOKU_ONNX_LIBRARY" // Path of the onnxruntime shared library
+)
+
+// Load makes new engines use the weights and model the environment names.
+// One that cannot be loaded is reported on standard error, prefixed with
+// program, and the built-in default is kept.
+func Load(program string) {
+	if path := os.Getenv(WeightsEnv); path != "" {
+		if weights, err := game.LoadWeightsFile(path); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: not using %s: %v\n", program, path, err)
+		} else {
+			game.SetDefaultWeights(weights)
+		}
+	}
+	if path := os.Getenv(ONNXModelEnv); path != "" {
+		if evaluator, err := game.LoadONNXEvaluator(path, os.Getenv(ONNXLibraryEnv)); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: not using %s: %v\n", program, path, err)
+		} else {
+			game.SetDefaultEvaluator(evaluator)
+		}
+	}
+}
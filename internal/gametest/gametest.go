@@ -0,0 +1,99 @@
+// Package gametest plays random games of every rule variant for tests, so
+// the packages checking games against each other play the same kinds.
+package gametest
+
+import (
+	"math/rand"
+	"testing"
+
+	"simple-gomoku/game"
+)
+
+// passOdds is how rarely a random turn passes: one time in passOdds
+const passOdds = 20
+
+// Variant is one kind of game: a rule variant, and the setup stones it is
+// played with
+type Variant struct {
+	Name      string
+	Rules     game.Rules
+	Handicap  bool // Black starts with a random number of handicap stones
+	Obstacles bool // A random layout of points is blocked
+}
+
+// Variants are the kinds of game tests play: each rule variant, and the
+// setup stones they allow
+var Variants = []Variant{
+	{Name: "standard", Rules: game.StandardRules},
+	{Name: "handicap", Rules: game.StandardRules, Handicap: true},
+	{Name: "blocked points", Rules: game.StandardRules, Obstacles: true},
+	{Name: "exact four", Rules: game.Rules{WinLength: 4, Exact: true}},
+	{Name: "exact five", Rules: game.Rules{WinLength: game.WinCondition, Exact: true}},
+	{Name: "six", Rules: game.Rules{WinLength: 6}},
+	{Name: "Pro opening", Rules: game.Rules{WinLength: game.WinCondition, Opening: game.ProOpening}},
+	{Name: "Long Pro opening with blocked points", Rules: game.Rules{WinLength: game.WinCondition, Opening: game.LongProOpening}, Obstacles: true},
+	{Name: "swap", Rules: game.Rules{WinLength: game.WinCondition, Opening: game.SwapOpening}},
+	{Name: "gravity", Rules: game.Rules{WinLength: 4, Gravity: true}},
+	{Name: "gravity with blocked points", Rules: game.Rules{WinLength: 4, Gravity: true}, Obstacles: true},
+	{Name: "three players", Rules: game.Rules{WinLength: 4, ThreePlayers: true}},
+	{Name: "three players with blocked points", Rules: game.Rules{WinLength: game.WinCondition, ThreePlayers: true}, Obstacles: true},
+}
+
+// NewBoard returns an empty board of the variant, with its setup stones
+// placed at random
+func (v Variant) NewBoard(t testing.TB, rng *rand.Rand) *game.Board {
+	t.Helper()
+	board, err := game.NewBoardWithRules(v.Rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Obstacles {
+		if err := board.SetupObstacles(game.RandomObstacles(1+rng.Intn(game.MaxObstacles), rng.Int63())); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if v.Handicap {
+		if err := board.SetupHandicap(game.HandicapPoints(1 + rng.Intn(game.MaxHandicap))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return board
+}
+
+// RandomGame plays random turns on a new board of the variant until the
+// game ends or length moves are played
+func (v Variant) RandomGame(t testing.TB, rng *rand.Rand, length int) *game.Board {
+	t.Helper()
+	board := v.NewBoard(t, rng)
+	for len(board.MoveHistory) < length && !board.IsGameFinished() {
+		PlayRandom(t, rng, board, nil)
+	}
+	return board
+}
+
+// PlayRandom plays one random turn on board: a swap half the time one is
+// allowed, else a pass now and then or a random point of moves. With no
+// moves it tries every point in random order, and passes if none is legal.
+func PlayRandom(t testing.TB, rng *rand.Rand, board *game.Board, moves [][2]int) {
+	t.Helper()
+	var err error
+	switch {
+	case board.CanSwap() && rng.Intn(2) == 0:
+		err = board.Swap()
+	case rng.Intn(passOdds) == 0:
+		err = board.Pass()
+	case len(moves) > 0:
+		move := moves[rng.Intn(len(moves))]
+		err = board.PlaceStone(move[0], move[1])
+	default:
+		for _, cell := range rng.Perm(game.BoardSize * game.BoardSize) {
+			if board.PlaceStone(cell/game.BoardSize, cell%game.BoardSize) == nil {
+				return
+			}
+		}
+		err = board.Pass()
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -1,6 +1,11 @@
 package main
 
 import (
+	"flag"
+	"log"
+	"os"
+
+	"simple-gomoku/internal/engineenv"
 	"simple-gomoku/ui"
 
 	"fyne.io/fyne/v2"
@@ -8,11 +13,24 @@ import (
 )
 
 func main() {
-	myApp := app.New()
+	kiosk := flag.Bool("kiosk", os.Getenv("GOMOKU_KIOSK") == "1", "run in tournament mode, locked until the PIN in GOMOKU_KIOSK_PIN is entered")
+	flag.Parse()
+	engineenv.Load("simple-gomoku")
+
+	myApp := app.NewWithID("io.github.aidenwang9867.simple-gomoku")
 	window := myApp.NewWindow("Gomoku Game")
 	window.Resize(fyne.NewSize(600, 600))
 
-	game := ui.NewGameWindow(window)
+	var kioskPIN string
+	if *kiosk {
+		if kioskPIN = os.Getenv("GOMOKU_KIOSK_PIN"); kioskPIN == "" {
+			log.Fatal("tournament mode needs a PIN in GOMOKU_KIOSK_PIN")
+		}
+	}
+	game, err := ui.NewGameWindow(window, kioskPIN)
+	if err != nil {
+		log.Fatal(err)
+	}
 	game.Show()
 
 	window.ShowAndRun()
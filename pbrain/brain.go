@@ -0,0 +1,265 @@
+// Package pbrain implements the Gomocup "pbrain" protocol, in which a
+// tournament manager such as Piskvork drives a brain through text commands
+// on its standard input and output.
+package pbrain
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"simple-gomoku/game"
+)
+
+const (
+	// timeMargin is kept back from the turn time for reading the command
+	// and replying
+	timeMargin = 150 * time.Millisecond
+
+	fastTurn       = 100 * time.Millisecond // Thinking time when the manager asks for no delay
+	timeLeftShares = 10                     // At most this share of the remaining match time per move
+)
+
+// Brain plays a game of Gomoku under the pbrain protocol
+type Brain struct {
+	Name    string
+	Version string
+	Author  string
+
+	difficulty game.Difficulty
+	board      *game.Board
+	own        game.Player // Color the brain plays, Empty until known
+
+	timeoutTurn time.Duration // 0 means as fast as possible
+	timeLeft    time.Duration // 0 means unlimited
+	hasTurnTime bool
+}
+
+// NewBrain creates a brain playing with the given engine
+func NewBrain(difficulty game.Difficulty) *Brain {
+	return &Brain{
+		Name:       "simple-gomoku",
+		Version:    "1.0",
+		Author:     "simple-gomoku authors",
+		difficulty: difficulty,
+		board:      game.NewBoard(),
+	}
+}
+
+// Run answers the commands read from r on w until END or the end of input
+func (b *Brain) Run(r io.Reader, w io.Writer) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		command, args, _ := strings.Cut(line, " ")
+		command = strings.ToUpper(command)
+
+		var reply string
+		switch command {
+		case "END":
+			return nil
+		case "BOARD":
+			var rows []string
+			for scanner.Scan() {
+				row := strings.TrimSpace(scanner.Text())
+				if strings.EqualFold(row, "DONE") {
+					break
+				}
+				rows = append(rows, row)
+			}
+			reply = b.setBoard(rows)
+		default:
+			reply = b.Handle(command, strings.TrimSpace(args))
+		}
+		if reply != "" {
+			if _, err := fmt.Fprintln(w, reply); err != nil {
+				return err
+			}
+		}
+	}
+	return scanner.Err()
+}
+
+// Handle answers one command other than BOARD and END. Commands without a
+// reply, such as INFO, return an empty string.
+func (b *Brain) Handle(command, args string) string {
+	switch command {
+	case "START":
+		size, err := strconv.Atoi(args)
+		if err != nil || size != game.BoardSize {
+			return fmt.Sprintf("ERROR only %dx%d boards are supported", game.BoardSize, game.BoardSize)
+		}
+		b.reset()
+		return "OK"
+	case "RECTSTART":
+		return fmt.Sprintf("ERROR only %dx%d boards are supported", game.BoardSize, game.BoardSize)
+	case "RESTART":
+		b.reset()
+		return "OK"
+	case "BEGIN":
+		if len(b.board.MoveHistory) > 0 {
+			return "ERROR the game has already begun"
+		}
+		b.own = game.Black
+		return b.think()
+	case "TURN":
+		row, col, err := parseCoords(args)
+		if err != nil {
+			return "ERROR " + err.Error()
+		}
+		if b.own == game.Empty {
+			b.own = opponent(b.board.CurrentTurn)
+		}
+		if err := b.board.PlaceStone(row, col); err != nil {
+			return "ERROR " + err.Error()
+		}
+		return b.think()
+	case "TAKEBACK":
+		row, col, err := parseCoords(args)
+		if err != nil {
+			return "ERROR " + err.Error()
+		}
+		if last, ok := b.board.LastMove(); !ok || last.Pos() != [2]int{row, col} {
+			return "ERROR can only take back the last move"
+		}
+		b.board.Undo()
+		return "OK"
+	case "INFO":
+		b.info(args)
+		return ""
+	case "ABOUT":
+		return fmt.Sprintf("name=%q, version=%q, author=%q", b.Name, b.Version, b.Author)
+	default:
+		return "UNKNOWN command " + command
+	}
+}
+
+func (b *Brain) reset() {
+	b.board = game.NewBoard()
+	b.own = game.Empty
+}
+
+// info stores the time controls; other keys are not needed
+func (b *Brain) info(args string) {
+	key, value, _ := strings.Cut(args, " ")
+	ms, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return
+	}
+	switch strings.ToLower(key) {
+	case "timeout_turn":
+		b.timeoutTurn = time.Duration(ms) * time.Millisecond
+		b.hasTurnTime = true
+	case "time_left":
+		b.timeLeft = time.Duration(ms) * time.Millisecond
+	}
+}
+
+// setBoard sets up the position of a BOARD command, where each row is
+// "x,y,who" with who 1 for the brain's stones and 2 for the opponent's, and
+// replies with the brain's move
+func (b *Brain) setBoard(rows []string) string {
+	var own, other [][2]int
+	for _, row := range rows {
+		fields := strings.Split(row, ",")
+		if len(fields) != 3 {
+			return "ERROR invalid board line " + row
+		}
+		r, c, err := parseCoords(fields[0] + "," + fields[1])
+		if err != nil {
+			return "ERROR " + err.Error()
+		}
+		switch strings.TrimSpace(fields[2]) {
+		case "1":
+			own = append(own, [2]int{r, c})
+		case "2":
+			other = append(other, [2]int{r, c})
+		default:
+			return "ERROR invalid board line " + row
+		}
+	}
+
+	// The brain is to move, so it moved first unless the opponent has more
+	// stones. The stones are replayed alternately in the order given.
+	first, second := own, other
+	b.own = game.Black
+	if len(other) > len(own) {
+		first, second = other, own
+		b.own = game.White
+	}
+	if len(first)-len(second) > 1 || len(first) < len(second) {
+		return "ERROR stone counts do not fit the brain being to move"
+	}
+	b.board = game.NewBoard()
+	for i := range first {
+		for _, stones := range [][][2]int{first, second} {
+			if i >= len(stones) {
+				continue
+			}
+			if err := b.board.PlaceStone(stones[i][0], stones[i][1]); err != nil {
+				return "ERROR " + err.Error()
+			}
+		}
+	}
+	if b.board.CurrentTurn != b.own {
+		return "ERROR stone counts do not fit the brain being to move"
+	}
+	return b.think()
+}
+
+// think plays the brain's move and returns it as "x,y"
+func (b *Brain) think() string {
+	if b.board.IsGameFinished() {
+		return "ERROR the game is over"
+	}
+	ai := game.NewAI(b.board.CurrentTurn, b.difficulty)
+	ai.SetTimeLimit(b.turnTime())
+	row, col := ai.MakeMove(b.board)
+	if err := b.board.PlaceStone(row, col); err != nil {
+		return "ERROR " + err.Error()
+	}
+	return fmt.Sprintf("%d,%d", col, row)
+}
+
+// turnTime returns the thinking time for the next move under the manager's
+// time controls
+func (b *Brain) turnTime() time.Duration {
+	limit := game.ExpertTimeLimit
+	if b.hasTurnTime {
+		limit = fastTurn
+		if b.timeoutTurn > 0 {
+			limit = max(fastTurn, b.timeoutTurn-timeMargin)
+		}
+	}
+	if b.timeLeft > 0 {
+		limit = min(limit, b.timeLeft/timeLeftShares)
+	}
+	return limit
+}
+
+// parseCoords parses "x,y" into a row and column on the board
+func parseCoords(s string) (int, int, error) {
+	xs, ys, ok := strings.Cut(s, ",")
+	x, errX := strconv.Atoi(strings.TrimSpace(xs))
+	y, errY := strconv.Atoi(strings.TrimSpace(ys))
+	if !ok || errX != nil || errY != nil {
+		return -1, -1, fmt.Errorf("invalid coordinates %q", s)
+	}
+	if x < 0 || x >= game.BoardSize || y < 0 || y >= game.BoardSize {
+		return -1, -1, fmt.Errorf("coordinates %q off the board", s)
+	}
+	return y, x, nil
+}
+
+func opponent(player game.Player) game.Player {
+	if player == game.Black {
+		return game.White
+	}
+	return game.Black
+}
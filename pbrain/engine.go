@@ -0,0 +1,231 @@
+package pbrain
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"simple-gomoku/game"
+)
+
+const (
+	// DefaultTurnTime is how long an engine is told it may think per move
+	DefaultTurnTime = 2 * time.Second
+
+	startTimeout = 5 * time.Second // Wait for the reply to START
+	aboutTimeout = time.Second     // Wait for the reply to ABOUT, which brains may ignore
+	replyGrace   = 5 * time.Second // Extra wait for a move beyond the turn time
+	endTimeout   = time.Second     // Wait for the engine to exit after END
+)
+
+// ErrEngineExited is returned when the engine's output ends
+var ErrEngineExited = errors.New("engine exited")
+
+// Engine is an external brain speaking the pbrain protocol, such as Yixin or
+// another Gomocup entry, run as a child process. Positions are sent whole
+// with BOARD, so the caller's board may be undone or replaced between moves.
+type Engine struct {
+	Name string // From the engine's ABOUT reply, or the file name
+
+	cmd      *exec.Cmd
+	stdin    io.WriteCloser
+	lines    chan string // Lines read from the engine's output
+	turnTime time.Duration
+
+	mu    sync.Mutex // Serialises commands
+	stale int        // Replies still due to moves that were cancelled
+}
+
+// StartEngine runs the engine at path and starts a 15x15 freestyle game with
+// the given time per move
+func StartEngine(path string, turnTime time.Duration) (*Engine, error) {
+	cmd := exec.Command(path)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	e := &Engine{
+		Name:     path[strings.LastIndexAny(path, `/\`)+1:],
+		cmd:      cmd,
+		stdin:    stdin,
+		lines:    make(chan string, 64),
+		turnTime: turnTime,
+	}
+	go e.read(stdout)
+
+	if err := e.start(); err != nil {
+		e.Close()
+		return nil, err
+	}
+	return e, nil
+}
+
+// read passes the engine's output to the lines channel until it ends
+func (e *Engine) read(stdout io.Reader) {
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			e.lines <- line
+		}
+	}
+	close(e.lines)
+}
+
+func (e *Engine) start() error {
+	if err := e.send(fmt.Sprintf("START %d", game.BoardSize)); err != nil {
+		return err
+	}
+	reply, err := e.reply(context.Background(), startTimeout)
+	if err != nil {
+		return err
+	}
+	if reply != "OK" {
+		return fmt.Errorf("engine refused to start: %s", reply)
+	}
+
+	ms := e.turnTime.Milliseconds()
+	if err := e.send(fmt.Sprintf("INFO timeout_turn %d\nINFO timeout_match 0\nINFO rule 0\nABOUT", ms)); err != nil {
+		return err
+	}
+	about, err := e.reply(context.Background(), aboutTimeout)
+	switch {
+	case errors.Is(err, ErrEngineExited):
+		return err
+	case err != nil:
+		e.stale++ // A late reply must not be taken for a move
+	default:
+		if name := aboutField(about, "name"); name != "" {
+			e.Name = name
+		}
+	}
+	return nil
+}
+
+// Move asks the engine for the move of the side to move on board. The board
+// is not changed.
+func (e *Engine) Move(ctx context.Context, board *game.Board) (int, int, error) {
+	if board.HasPasses() {
+		return -1, -1, errors.New("the pbrain protocol has no passes")
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	// Handicap stones are not moves, so they are sent first as Black's
+	stones := make([]game.Move, 0, len(board.Handicap())+len(board.MoveHistory))
+	for _, point := range board.Handicap() {
+		stones = append(stones, game.Move{Row: point[0], Col: point[1], Player: game.Black})
+	}
+	stones = append(stones, board.MoveHistory...)
+
+	command := "BEGIN"
+	if len(stones) > 0 {
+		var lines []string
+		lines = append(lines, "BOARD")
+		for _, move := range stones {
+			who := 2
+			if move.Player == board.CurrentTurn {
+				who = 1
+			}
+			lines = append(lines, fmt.Sprintf("%d,%d,%d", move.Col, move.Row, who))
+		}
+		command = strings.Join(append(lines, "DONE"), "\n")
+	}
+	if err := e.send(command); err != nil {
+		return -1, -1, err
+	}
+
+	reply, err := e.reply(ctx, e.turnTime+replyGrace)
+	if err != nil {
+		if !errors.Is(err, ErrEngineExited) {
+			e.stale++ // The move may still arrive and is skipped
+		}
+		return -1, -1, err
+	}
+	if strings.HasPrefix(reply, "ERROR") || strings.HasPrefix(reply, "UNKNOWN") {
+		return -1, -1, fmt.Errorf("engine: %s", reply)
+	}
+	row, col, err := parseCoords(reply)
+	if err != nil {
+		return -1, -1, fmt.Errorf("engine: %w", err)
+	}
+	if board.Grid[row][col] != game.Empty {
+		return -1, -1, fmt.Errorf("engine played on an occupied point %s", game.FormatMove(row, col))
+	}
+	return row, col, nil
+}
+
+// Close ends the game and stops the engine, killing it if it does not exit
+func (e *Engine) Close() error {
+	e.send("END")
+	e.stdin.Close()
+
+	done := make(chan error, 1)
+	go func() { done <- e.cmd.Wait() }()
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(endTimeout):
+		e.cmd.Process.Kill()
+		return <-done
+	}
+}
+
+func (e *Engine) send(command string) error {
+	_, err := io.WriteString(e.stdin, command+"\n")
+	return err
+}
+
+// reply waits for the engine's next reply, skipping its MESSAGE and DEBUG
+// output and any replies to cancelled moves
+func (e *Engine) reply(ctx context.Context, timeout time.Duration) (string, error) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	for {
+		select {
+		case line, ok := <-e.lines:
+			if !ok {
+				return "", ErrEngineExited
+			}
+			command, _, _ := strings.Cut(line, " ")
+			switch strings.ToUpper(command) {
+			case "MESSAGE", "DEBUG", "SUGGEST":
+				continue
+			}
+			if e.stale > 0 {
+				e.stale--
+				continue
+			}
+			return line, nil
+		case <-timer.C:
+			return "", errors.New("engine did not reply in time")
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+}
+
+// aboutField returns a field of an ABOUT reply such as
+// name="Yixin", version="2018"
+func aboutField(about, key string) string {
+	for _, field := range strings.Split(about, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(field), "=")
+		if ok && strings.EqualFold(name, key) {
+			return strings.Trim(value, `"`)
+		}
+	}
+	return ""
+}
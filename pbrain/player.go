@@ -0,0 +1,53 @@
+package pbrain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"simple-gomoku/game"
+)
+
+// EnginePlayer plays one side of a game with an external engine. Its moves
+// are asked for like game.AI's, so it can take the AI's place as an opponent.
+type EnginePlayer struct {
+	path     string
+	turnTime time.Duration
+	engine   *Engine
+}
+
+// NewEnginePlayer starts the engine at path, which is told it may think for
+// turnTime per move
+func NewEnginePlayer(path string, turnTime time.Duration) (*EnginePlayer, error) {
+	engine, err := StartEngine(path, turnTime)
+	if err != nil {
+		return nil, err
+	}
+	return &EnginePlayer{path: path, turnTime: turnTime, engine: engine}, nil
+}
+
+// Name returns the engine's name
+func (p *EnginePlayer) Name() string {
+	return p.engine.Name
+}
+
+// MakeMoveCtx returns the engine's move for the side to move on board. An
+// engine that has exited is started again once before giving up.
+func (p *EnginePlayer) MakeMoveCtx(ctx context.Context, board *game.Board) (int, int, error) {
+	row, col, err := p.engine.Move(ctx, board)
+	if !errors.Is(err, ErrEngineExited) {
+		return row, col, err
+	}
+	p.engine.Close()
+	engine, err := StartEngine(p.path, p.turnTime)
+	if err != nil {
+		return -1, -1, err
+	}
+	p.engine = engine
+	return p.engine.Move(ctx, board)
+}
+
+// Close stops the engine
+func (p *EnginePlayer) Close() error {
+	return p.engine.Close()
+}
@@ -0,0 +1,226 @@
+// Package referee judges Gomoku games without the engine: which moves are
+// legal and how a game stands. It follows the game package's rules, every
+// variant included, but depends on the standard library alone, so thin
+// clients such as web and mobile frontends, and servers checking their
+// input, can validate moves without shipping the engines, opening books
+// and networks the game package carries.
+//
+// The rules are written out a second time here, apart from the game
+// package's incremental board the engines search with, and nothing but
+// TestAgreesWithGame keeps the two in step: it plays random games of every
+// variant and compares the verdicts after each move. Any change to the
+// rules, in win detection, exact rows, the opening rules and swaps,
+// gravity, three players, handicap stones or blocked points, must be made
+// in both packages, with TestAgreesWithGame covering it.
+//
+// A position is given as the moves leading to it, since the opening rules
+// and passes depend on the order stones were played in. Points are [row,
+// col] pairs from the top left, as in the game package, with PassMove for a
+// pass. The API is kept stable. Player, OpeningRule and Reason values match
+// their game package namesakes, so those convert with a cast; Rules and the
+// other structs have to be converted field by field.
+package referee
+
+import (
+	"errors"
+	"fmt"
+)
+
+// BoardSize is the number of rows and columns of the board
+const BoardSize = 15
+
+// Limits on the setup of a position, as in the game package
+const (
+	MinWinLength   = 4
+	MaxWinLength   = 6
+	MaxExactLength = 5
+	MaxHandicap    = 5
+	MaxObstacles   = 40
+)
+
+// PassMove is the move of a player who passes
+var PassMove = [2]int{-1, -1}
+
+// Player is the occupant of a point, or the player to move
+type Player int
+
+const (
+	Empty Player = iota
+	Black
+	White
+	Red     // Third player, only in three-player games
+	Blocked // A point no player may take
+)
+
+// String names the player, e.g. "Black"
+func (p Player) String() string {
+	switch p {
+	case Black:
+		return "Black"
+	case White:
+		return "White"
+	case Red:
+		return "Red"
+	case Blocked:
+		return "Blocked"
+	}
+	return "none"
+}
+
+// OpeningRule is a restriction on the first stones of a game
+type OpeningRule int
+
+const (
+	FreeOpening    OpeningRule = iota // No restriction
+	ProOpening                        // Black opens in the center; its second stone goes 3 or more rows or columns away
+	LongProOpening                    // As ProOpening, but 4 or more rows or columns away
+	SwapOpening                       // White may take over Black's first stone, see Position.Swapped
+)
+
+// Rules are the rules a game is played under
+type Rules struct {
+	WinLength    int  // Stones in a row that win; longer rows win too unless Exact
+	Exact        bool // Only rows of exactly WinLength win
+	Opening      OpeningRule
+	Gravity      bool // Stones drop to the lowest empty point of their column
+	ThreePlayers bool // Red joins, moving after White
+}
+
+// StandardRules are the rules of freestyle gomoku, five or more in a row
+var StandardRules = Rules{WinLength: 5}
+
+// Position is a game as the moves leading to it
+type Position struct {
+	Rules    Rules
+	Handicap [][2]int // Black stones set before the first move, after which White moves first
+	Blocked  [][2]int // Points no player may take, set before the first move
+	Moves    [][2]int // Moves in the order played, PassMove for a pass
+	Swapped  bool     // White took over the first stone under the swap opening, and Black moved next
+}
+
+// Reason is why a game ended
+type Reason int
+
+const (
+	InProgress      Reason = iota
+	ReasonRow              // A player made a winning row
+	ReasonPasses           // Every player passed in a row
+	ReasonFullBoard        // No empty points remain
+)
+
+// Outcome is how a game stands
+type Outcome struct {
+	Winner Player // Empty unless a player won
+	Draw   bool
+	Reason Reason
+	ToMove Player // Player to move next, Empty once the game is over
+}
+
+// Finished reports whether the game is over
+func (o Outcome) Finished() bool {
+	return o.Reason != InProgress
+}
+
+// LegalMoves returns the points the side to move may play, in row order,
+// none once the game is over. Under gravity they are the points each column
+// with room drops a stone to. Passing is legal while the game is on and is
+// not listed.
+func LegalMoves(pos Position) ([][2]int, error) {
+	g, err := replay(pos)
+	if err != nil || g.result.Finished() {
+		return nil, err
+	}
+	var moves [][2]int
+	for row := 0; row < BoardSize; row++ {
+		for col := 0; col < BoardSize; col++ {
+			if g.check([2]int{row, col}) == nil {
+				moves = append(moves, [2]int{row, col})
+			}
+		}
+	}
+	return moves, nil
+}
+
+// IsLegal reports whether the side to move may play move, which may be
+// PassMove, in a valid position
+func IsLegal(pos Position, move [2]int) bool {
+	return Check(pos, move) == nil
+}
+
+// Check returns why the side to move may not play move, nil if it may. It
+// returns an error too when the position itself is not valid.
+func Check(pos Position, move [2]int) error {
+	g, err := replay(pos)
+	if err != nil {
+		return err
+	}
+	if g.result.Finished() {
+		return errors.New("game is already finished")
+	}
+	if move == PassMove {
+		return nil
+	}
+	return g.check(move)
+}
+
+// Result returns how the game stands after the moves of pos
+func Result(pos Position) (Outcome, error) {
+	g, err := replay(pos)
+	if err != nil {
+		return Outcome{}, err
+	}
+	result := g.result
+	if !result.Finished() {
+		result.ToMove = g.toMove
+	}
+	return result, nil
+}
+
+// FormatMove returns a point in the game's coordinate notation, columns a
+// to o from the left and rows 1 to 15 from the bottom, e.g. "h8"
+func FormatMove(move [2]int) string {
+	if move == PassMove {
+		return "pass"
+	}
+	return fmt.Sprintf("%c%d", 'a'+move[1], BoardSize-move[0])
+}
+
+// Validate reports rules the game package cannot play
+func (r Rules) Validate() error {
+	if r.WinLength < MinWinLength || r.WinLength > MaxWinLength {
+		return fmt.Errorf("win length %d is not between %d and %d", r.WinLength, MinWinLength, MaxWinLength)
+	}
+	if r.Exact && r.WinLength > MaxExactLength {
+		return fmt.Errorf("exact rows are supported up to %d in a row", MaxExactLength)
+	}
+	if r.Opening < FreeOpening || r.Opening > SwapOpening {
+		return fmt.Errorf("unknown opening rule %d", r.Opening)
+	}
+	if r.Gravity && r.thirdMoveDistance() > 0 {
+		return errors.New("a Pro opening cannot be played with gravity, which keeps stones off the center")
+	}
+	if r.ThreePlayers && r.Opening != FreeOpening {
+		return errors.New("three-player games use a free opening")
+	}
+	return nil
+}
+
+// thirdMoveDistance returns how many rows or columns from the center the
+// third move must be at least, 0 when the opening is free
+func (r Rules) thirdMoveDistance() int {
+	switch r.Opening {
+	case ProOpening:
+		return 3
+	case LongProOpening:
+		return 4
+	}
+	return 0
+}
+
+// players returns the players of a game under the rules, in turn order
+func (r Rules) players() []Player {
+	if r.ThreePlayers {
+		return []Player{Black, White, Red}
+	}
+	return []Player{Black, White}
+}
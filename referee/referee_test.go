@@ -0,0 +1,129 @@
+package referee_test
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"simple-gomoku/game"
+	"simple-gomoku/internal/gametest"
+	"simple-gomoku/referee"
+)
+
+// position converts the game on board to a referee position. The rules
+// convert field by field, since their opening rules are different types.
+func position(board *game.Board) referee.Position {
+	rules := board.Rules()
+	return referee.Position{
+		Rules: referee.Rules{
+			WinLength:    rules.WinLength,
+			Exact:        rules.Exact,
+			Opening:      referee.OpeningRule(rules.Opening),
+			Gravity:      rules.Gravity,
+			ThreePlayers: rules.ThreePlayers,
+		},
+		Handicap: board.Handicap(),
+		Blocked:  board.Obstacles(),
+		Moves:    board.Positions(),
+		Swapped:  board.Swapped(),
+	}
+}
+
+// legalMoves returns the points the game package lets the side to move
+// play, in row order, as the referee lists them
+func legalMoves(board *game.Board) [][2]int {
+	var moves [][2]int
+	if board.IsGameFinished() {
+		return nil
+	}
+	trial := board.Clone()
+	for row := 0; row < game.BoardSize; row++ {
+		for col := 0; col < game.BoardSize; col++ {
+			if board.Rules().Gravity && row != board.DropRow(col) {
+				continue // Any row drops to the same point
+			}
+			if board.Grid[row][col] == game.Empty && trial.PlaceStone(row, col) == nil {
+				moves = append(moves, [2]int{row, col})
+				trial.Undo()
+			}
+		}
+	}
+	return moves
+}
+
+// checkAgrees fails the test unless the referee judges the position on
+// board as the game package does, and returns the legal moves
+func checkAgrees(t *testing.T, board *game.Board) [][2]int {
+	t.Helper()
+	pos := position(board)
+	outcome, err := referee.Result(pos)
+	if err != nil {
+		t.Fatalf("after %v: %v", pos.Moves, err)
+	}
+	result := board.Result()
+	toMove := referee.Empty
+	if !result.Finished() {
+		toMove = referee.Player(board.CurrentTurn)
+	}
+	want := referee.Outcome{
+		Winner: referee.Player(result.Winner),
+		Draw:   result.Draw,
+		Reason: referee.Reason(result.Reason),
+		ToMove: toMove,
+	}
+	if outcome != want {
+		t.Fatalf("after %v the referee gives %+v, the game %+v", pos.Moves, outcome, want)
+	}
+
+	moves, err := referee.LegalMoves(pos)
+	if err != nil {
+		t.Fatalf("after %v: %v", pos.Moves, err)
+	}
+	if want := legalMoves(board); !reflect.DeepEqual(moves, want) {
+		t.Fatalf("after %v the referee allows %v, the game %v", pos.Moves, moves, want)
+	}
+	if legal := referee.IsLegal(pos, referee.PassMove); legal != !result.Finished() {
+		t.Fatalf("after %v passing is legal is %v, want %v", pos.Moves, legal, !result.Finished())
+	}
+	if last, ok := board.LastMove(); ok && !last.IsPass() && referee.IsLegal(pos, last.Pos()) {
+		t.Fatalf("after %v the referee allows the last move again", pos.Moves)
+	}
+	return moves
+}
+
+// TestAgreesWithGame plays random games of every variant, swapping and
+// passing now and then, and checks after each move that the referee and
+// the game package agree on the legal moves, the side to move and the
+// result
+func TestAgreesWithGame(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, variant := range gametest.Variants {
+		t.Run(variant.Name, func(t *testing.T) {
+			for i := 0; i < 20; i++ {
+				board := variant.NewBoard(t, rng)
+				moves := checkAgrees(t, board)
+				for !board.IsGameFinished() {
+					gametest.PlayRandom(t, rng, board, moves)
+					moves = checkAgrees(t, board)
+				}
+			}
+		})
+	}
+}
+
+func TestRejectsInvalidPositions(t *testing.T) {
+	for name, pos := range map[string]referee.Position{
+		"bad win length":         {Rules: referee.Rules{WinLength: 7}},
+		"occupied point":         {Rules: referee.StandardRules, Moves: [][2]int{{7, 7}, {7, 7}}},
+		"off the board":          {Rules: referee.StandardRules, Moves: [][2]int{{15, 0}}},
+		"move after the end":     {Rules: referee.StandardRules, Moves: [][2]int{referee.PassMove, referee.PassMove, {7, 7}}},
+		"swap without rule":      {Rules: referee.StandardRules, Moves: [][2]int{{7, 7}}, Swapped: true},
+		"Pro opening off center": {Rules: referee.Rules{WinLength: 5, Opening: referee.ProOpening}, Moves: [][2]int{{0, 0}}},
+		"handicap and blocked point": {Rules: referee.StandardRules, Handicap: [][2]int{{7, 7}},
+			Blocked: [][2]int{{0, 0}}},
+	} {
+		if _, err := referee.Result(pos); err == nil {
+			t.Errorf("%s: no error", name)
+		}
+	}
+}
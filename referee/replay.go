@@ -0,0 +1,238 @@
+package referee
+
+import (
+	"errors"
+	"fmt"
+)
+
+// directions are the four line directions a row can run in
+var directions = [4][2]int{{0, 1}, {1, 0}, {1, 1}, {1, -1}}
+
+// game is a position replayed move by move. Its rules repeat game.Board's
+// and must change with them, see the package comment.
+type game struct {
+	rules  Rules
+	grid   [BoardSize][BoardSize]Player
+	free   int // Empty points left
+	moves  int // Moves played, passes included
+	passes int // Passes since the last stone
+	toMove Player
+	result Outcome
+}
+
+// replay plays the moves of pos on a new board, checking the setup and
+// every move
+func replay(pos Position) (*game, error) {
+	if err := pos.Rules.Validate(); err != nil {
+		return nil, err
+	}
+	g := &game{rules: pos.Rules, free: BoardSize * BoardSize, toMove: Black}
+	if err := g.block(pos.Blocked); err != nil {
+		return nil, err
+	}
+	if err := g.setHandicap(pos.Handicap); err != nil {
+		return nil, err
+	}
+	if pos.Swapped && (pos.Rules.Opening != SwapOpening || len(pos.Handicap) > 0 || len(pos.Moves) == 0 ||
+		pos.Moves[0] == PassMove) {
+		return nil, errors.New("only a first stone under the swap opening can be swapped")
+	}
+
+	for i, move := range pos.Moves {
+		if g.result.Finished() {
+			return nil, fmt.Errorf("move %d: game is already finished", i+1)
+		}
+		if move == PassMove {
+			g.pass()
+			continue
+		}
+		if err := g.check(move); err != nil {
+			return nil, fmt.Errorf("move %d, %s: %w", i+1, FormatMove(move), err)
+		}
+		g.place(move)
+		if i == 0 && pos.Swapped && !g.result.Finished() {
+			// The stone turns White and Black moves next
+			g.grid[move[0]][move[1]] = White
+			g.toMove = Black
+		}
+	}
+	return g, nil
+}
+
+// block sets the blocked points of an empty board
+func (g *game) block(points [][2]int) error {
+	if len(points) > MaxObstacles {
+		return fmt.Errorf("a board has at most %d blocked points, not %d", MaxObstacles, len(points))
+	}
+	for _, point := range points {
+		if !onBoard(point) {
+			return fmt.Errorf("blocked point %s is off the board", FormatMove(point))
+		}
+		if g.grid[point[0]][point[1]] != Empty {
+			return fmt.Errorf("blocked point %s is given twice", FormatMove(point))
+		}
+		g.grid[point[0]][point[1]] = Blocked
+		g.free--
+	}
+	if center := BoardSize / 2; g.grid[center][center] == Blocked && g.rules.thirdMoveDistance() > 0 {
+		return errors.New("the center cannot be blocked under a Pro opening")
+	}
+	return nil
+}
+
+// setHandicap places Black's handicap stones, after which White moves first
+func (g *game) setHandicap(points [][2]int) error {
+	if len(points) == 0 {
+		return nil
+	}
+	switch {
+	case g.rules.Opening != FreeOpening || g.rules.Gravity || g.rules.ThreePlayers:
+		return errors.New("handicap stones need two players, a free opening and no gravity")
+	case g.free < BoardSize*BoardSize:
+		return errors.New("handicap stones cannot be used with blocked points")
+	case len(points) > MaxHandicap:
+		return fmt.Errorf("a handicap is 1 to %d stones, not %d", MaxHandicap, len(points))
+	}
+	for _, point := range points {
+		if !onBoard(point) {
+			return fmt.Errorf("handicap point %s is off the board", FormatMove(point))
+		}
+		if g.grid[point[0]][point[1]] != Empty {
+			return fmt.Errorf("handicap point %s is given twice", FormatMove(point))
+		}
+		g.grid[point[0]][point[1]] = Black
+		g.free--
+	}
+	for _, point := range points {
+		if g.wins(point) {
+			return errors.New("the handicap stones must not make a winning row")
+		}
+	}
+	g.toMove = White
+	return nil
+}
+
+// check returns why the side to move may not place a stone on move
+func (g *game) check(move [2]int) error {
+	row, col := move[0], move[1]
+	switch {
+	case !onBoard(move):
+		return errors.New("position out of bounds")
+	case g.grid[row][col] == Blocked:
+		return errors.New("position is blocked")
+	case g.grid[row][col] != Empty:
+		return errors.New("position already occupied")
+	}
+	if g.rules.Gravity {
+		if drop := g.dropRow(col); row != drop {
+			return fmt.Errorf("a stone in this column drops to %s", FormatMove([2]int{drop, col}))
+		}
+	}
+
+	center := BoardSize / 2
+	distance := max(abs(row-center), abs(col-center))
+	switch limit := g.rules.thirdMoveDistance(); {
+	case limit == 0:
+	case g.moves == 0 && distance != 0:
+		return errors.New("the first move must be in the center under a Pro opening")
+	case g.moves == 2 && distance < limit:
+		return fmt.Errorf("the third move must be at least %d rows or columns from the center", limit)
+	}
+	return nil
+}
+
+// place puts a stone of the side to move on move, which check allows
+func (g *game) place(move [2]int) {
+	g.grid[move[0]][move[1]] = g.toMove
+	g.free--
+	g.moves++
+	g.passes = 0
+	switch {
+	case g.wins(move):
+		g.result = Outcome{Winner: g.toMove, Reason: ReasonRow}
+	case g.full():
+		g.result = Outcome{Draw: true, Reason: ReasonFullBoard}
+	default:
+		g.toMove = g.next()
+	}
+}
+
+// pass gives up the turn. Once every player has passed in a row the game
+// ends as a draw.
+func (g *game) pass() {
+	g.moves++
+	g.passes++
+	if g.passes == len(g.rules.players()) {
+		g.result = Outcome{Draw: true, Reason: ReasonPasses}
+		return
+	}
+	g.toMove = g.next()
+}
+
+// next returns the player who moves after the side to move
+func (g *game) next() Player {
+	players := g.rules.players()
+	for i, player := range players {
+		if player == g.toMove {
+			return players[(i+1)%len(players)]
+		}
+	}
+	return Black
+}
+
+// full reports whether no point is left to play: every point is taken, or
+// under gravity every column is filled up to its top or a blocked point
+func (g *game) full() bool {
+	if g.free == 0 || !g.rules.Gravity {
+		return g.free == 0
+	}
+	for col := 0; col < BoardSize; col++ {
+		if g.dropRow(col) >= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// dropRow returns the row a stone dropped in col comes to rest on, -1 when
+// the column is full
+func (g *game) dropRow(col int) int {
+	for row := 0; row < BoardSize; row++ {
+		if g.grid[row][col] != Empty {
+			return row - 1
+		}
+	}
+	return BoardSize - 1
+}
+
+// wins reports whether the stone on move is part of a winning row
+func (g *game) wins(move [2]int) bool {
+	player := g.grid[move[0]][move[1]]
+	for _, dir := range directions {
+		run := 1
+		for _, sign := range []int{1, -1} {
+			for k := 1; ; k++ {
+				point := [2]int{move[0] + sign*k*dir[0], move[1] + sign*k*dir[1]}
+				if !onBoard(point) || g.grid[point[0]][point[1]] != player {
+					break
+				}
+				run++
+			}
+		}
+		if run == g.rules.WinLength || run > g.rules.WinLength && !g.rules.Exact {
+			return true
+		}
+	}
+	return false
+}
+
+func onBoard(point [2]int) bool {
+	return point[0] >= 0 && point[0] < BoardSize && point[1] >= 0 && point[1] < BoardSize
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,58 @@
+// Package fynecanvas is the Fyne backend of the render package: it draws
+// boards as Fyne canvas objects, for windows and for Fyne's test canvas.
+package fynecanvas
+
+import (
+	"image"
+	"image/color"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+)
+
+// Canvas is a render.Canvas adding canvas objects to a container without a
+// layout
+type Canvas struct {
+	*fyne.Container
+}
+
+// New returns a canvas with an empty container
+func New() Canvas {
+	return Canvas{container.NewWithoutLayout()}
+}
+
+func (c Canvas) place(obj fyne.CanvasObject, x, y, width, height float32) {
+	obj.Resize(fyne.NewSize(width, height))
+	obj.Move(fyne.NewPos(x, y))
+	c.Add(obj)
+}
+
+func (c Canvas) Rect(x, y, width, height float32, fill color.Color) {
+	c.place(canvas.NewRectangle(fill), x, y, width, height)
+}
+
+func (c Canvas) Gradient(x, y, width, height float32, start, end color.Color) {
+	c.place(canvas.NewLinearGradient(start, end, 315), x, y, width, height) // Top left to bottom right
+}
+
+func (c Canvas) Picture(x, y, width, height float32, img image.Image) {
+	picture := canvas.NewImageFromImage(img)
+	picture.FillMode = canvas.ImageFillStretch
+	c.place(picture, x, y, width, height)
+}
+
+func (c Canvas) Line(x1, y1, x2, y2, width float32, stroke color.Color) {
+	line := canvas.NewLine(stroke)
+	line.StrokeWidth = width
+	line.Position1 = fyne.NewPos(x1, y1)
+	line.Position2 = fyne.NewPos(x2, y2)
+	c.Add(line)
+}
+
+func (c Canvas) Circle(cx, cy, radius float32, fill, stroke color.Color, strokeWidth float32) {
+	circle := canvas.NewCircle(fill)
+	circle.StrokeColor = stroke
+	circle.StrokeWidth = strokeWidth
+	c.place(circle, cx-radius, cy-radius, 2*radius, 2*radius)
+}
@@ -0,0 +1,115 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+
+	xdraw "golang.org/x/image/draw"
+	"golang.org/x/image/vector"
+)
+
+// circleControl places the control points of the cubic curves that make up
+// a quarter circle
+const circleControl = 0.5522847
+
+// Image is a Canvas drawing into an image in memory, anti-aliased, with no
+// need for a display
+type Image struct {
+	*image.RGBA
+	scale float32 // Pixels per point
+}
+
+// NewImage returns a transparent image for a drawing width by height points
+// in size, at scale pixels per point
+func NewImage(width, height, scale float32) *Image {
+	bounds := image.Rect(0, 0, int(math.Ceil(float64(width*scale))), int(math.Ceil(float64(height*scale))))
+	return &Image{RGBA: image.NewRGBA(bounds), scale: scale}
+}
+
+// rect returns the pixels covered by a rectangle in points
+func (m *Image) rect(x, y, width, height float32) image.Rectangle {
+	round := func(v float32) int { return int(math.Round(float64(v * m.scale))) }
+	return image.Rect(round(x), round(y), round(x+width), round(y+height)).Intersect(m.Bounds())
+}
+
+func (m *Image) Rect(x, y, width, height float32, fill color.Color) {
+	draw.Draw(m.RGBA, m.rect(x, y, width, height), image.NewUniform(fill), image.Point{}, draw.Over)
+}
+
+func (m *Image) Gradient(x, y, width, height float32, start, end color.Color) {
+	r := m.rect(x, y, width, height)
+	from, to := color.NRGBAModel.Convert(start).(color.NRGBA), color.NRGBAModel.Convert(end).(color.NRGBA)
+	mix := func(a, b uint8, t float64) uint8 { return uint8(math.Round(float64(a) + (float64(b)-float64(a))*t)) }
+	span := float64(max(1, r.Dx()+r.Dy()-2))
+	for py := r.Min.Y; py < r.Max.Y; py++ {
+		for px := r.Min.X; px < r.Max.X; px++ {
+			t := float64(px-r.Min.X+py-r.Min.Y) / span
+			c := color.NRGBA{R: mix(from.R, to.R, t), G: mix(from.G, to.G, t), B: mix(from.B, to.B, t), A: mix(from.A, to.A, t)}
+			m.RGBA.Set(px, py, c)
+		}
+	}
+}
+
+func (m *Image) Picture(x, y, width, height float32, img image.Image) {
+	xdraw.BiLinear.Scale(m.RGBA, m.rect(x, y, width, height), img, img.Bounds(), xdraw.Over, nil)
+}
+
+func (m *Image) Line(x1, y1, x2, y2, width float32, stroke color.Color) {
+	length := float32(math.Hypot(float64(x2-x1), float64(y2-y1)))
+	if length == 0 {
+		return
+	}
+	// The line is a rectangle around the segment, width wide
+	nx, ny := (y1-y2)/length*width/2, (x2-x1)/length*width/2
+	z := m.rasterizer()
+	z.MoveTo(m.scale*(x1+nx), m.scale*(y1+ny))
+	z.LineTo(m.scale*(x2+nx), m.scale*(y2+ny))
+	z.LineTo(m.scale*(x2-nx), m.scale*(y2-ny))
+	z.LineTo(m.scale*(x1-nx), m.scale*(y1-ny))
+	z.ClosePath()
+	m.fill(z, stroke)
+}
+
+func (m *Image) Circle(cx, cy, radius float32, fill, stroke color.Color, strokeWidth float32) {
+	if fill != nil {
+		z := m.rasterizer()
+		m.circlePath(z, cx, cy, radius, false)
+		m.fill(z, fill)
+	}
+	if stroke != nil && strokeWidth > 0 {
+		// A ring: the inner circle runs the other way and cuts out the middle
+		z := m.rasterizer()
+		m.circlePath(z, cx, cy, radius+strokeWidth/2, false)
+		m.circlePath(z, cx, cy, max(0, radius-strokeWidth/2), true)
+		m.fill(z, stroke)
+	}
+}
+
+// rasterizer returns a rasterizer the size of the image
+func (m *Image) rasterizer() *vector.Rasterizer {
+	return vector.NewRasterizer(m.Bounds().Dx(), m.Bounds().Dy())
+}
+
+// circlePath adds a circle to z as four cubic curves, counterclockwise when
+// reversed is set
+func (m *Image) circlePath(z *vector.Rasterizer, cx, cy, radius float32, reversed bool) {
+	cx, cy, r := cx*m.scale, cy*m.scale, radius*m.scale
+	k := r * circleControl
+	dir := float32(1)
+	if reversed {
+		dir = -1
+	}
+	z.MoveTo(cx+r, cy)
+	z.CubeTo(cx+r, cy+dir*k, cx+k, cy+dir*r, cx, cy+dir*r)
+	z.CubeTo(cx-k, cy+dir*r, cx-r, cy+dir*k, cx-r, cy)
+	z.CubeTo(cx-r, cy-dir*k, cx-k, cy-dir*r, cx, cy-dir*r)
+	z.CubeTo(cx+k, cy-dir*r, cx+r, cy-dir*k, cx+r, cy)
+	z.ClosePath()
+}
+
+// fill paints the shape rasterized by z in c
+func (m *Image) fill(z *vector.Rasterizer, c color.Color) {
+	z.Draw(m.RGBA, m.Bounds(), image.NewUniform(c), image.Point{})
+}
@@ -0,0 +1,160 @@
+// Package render draws Gomoku boards through a small set of drawing
+// operations, so the window and the exporters show the same picture. The
+// fynecanvas package is the Fyne backend; Image draws into an image.RGBA
+// and needs no display server, so exports and snapshots also work
+// headlessly. The rendertest package checks renderings against snapshots.
+package render
+
+import (
+	"image"
+	"image/color"
+
+	"simple-gomoku/game"
+)
+
+// Canvas is a drawing surface. Coordinates and sizes are in board points,
+// which backends map to pixels.
+type Canvas interface {
+	// Rect fills the rectangle with its top left corner at (x, y)
+	Rect(x, y, width, height float32, fill color.Color)
+	// Gradient fills the rectangle with colors running from start at the top
+	// left corner to end at the bottom right
+	Gradient(x, y, width, height float32, start, end color.Color)
+	// Picture stretches img over the rectangle
+	Picture(x, y, width, height float32, img image.Image)
+	// Line draws a line from (x1, y1) to (x2, y2)
+	Line(x1, y1, x2, y2, width float32, stroke color.Color)
+	// Circle fills a circle and draws its outline, centered on the circle's
+	// edge, when strokeWidth is above zero
+	Circle(cx, cy, radius float32, fill, stroke color.Color, strokeWidth float32)
+}
+
+// Geometry holds the sizes a board is drawn with, in points
+type Geometry struct {
+	Cell    float32 // Distance between grid lines
+	Padding float32 // Margin around the outer lines
+	Stone   float32 // Stone diameter
+	Marker  float32 // Length of the last move marker's arms
+}
+
+// DefaultGeometry is the size of the board in the main window
+var DefaultGeometry = Geometry{Cell: 40, Padding: 30, Stone: 32, Marker: 10}
+
+// Span returns the distance between the outer grid lines
+func (g Geometry) Span() float32 {
+	return float32(game.BoardSize-1) * g.Cell
+}
+
+// Coord returns the offset of the grid line with the given index
+func (g Geometry) Coord(index int) float32 {
+	return g.Padding + float32(index)*g.Cell
+}
+
+// Total returns the board size including the margins
+func (g Geometry) Total() float32 {
+	return g.Span() + 2*g.Padding
+}
+
+// Background is what is drawn under the grid lines: an image if set, or
+// else a gradient, solid when End is nil or the same as Start
+type Background struct {
+	Start, End color.Color
+	Image      image.Image
+}
+
+// Style holds the colors a board is drawn with
+type Style struct {
+	Background Background
+	Grid       color.Color // Grid lines
+	Marker     color.Color // Last move marker
+	Black      color.Color // Black stones
+	White      color.Color // White stones
+	Red        color.Color // Red stones, in three-player games
+	Blocked    color.Color // Blocked points, crossed with the grid color
+}
+
+var woodColor = color.RGBA{R: 255, G: 223, B: 176, A: 255}
+
+// DefaultStyle is the plain wooden board
+var DefaultStyle = Style{
+	Background: Background{Start: woodColor},
+	Grid:       color.Black,
+	Marker:     color.RGBA{R: 255, A: 255},
+	Black:      color.Black,
+	White:      color.White,
+	Red:        color.RGBA{R: 200, G: 30, B: 30, A: 255},
+	Blocked:    color.RGBA{R: 120, G: 110, B: 100, A: 255},
+}
+
+// Board draws the background, the grid, the stones of grid and a marker on
+// last, unless last is game.PassMove
+func Board(c Canvas, g Geometry, s Style, grid *[game.BoardSize][game.BoardSize]game.Player, last [2]int) {
+	Grid(c, g, s)
+	Stones(c, g, s, grid)
+	if last != game.PassMove {
+		Marker(c, g, s, last[0], last[1])
+	}
+}
+
+// Grid draws the background and the grid lines
+func Grid(c Canvas, g Geometry, s Style) {
+	size := g.Total()
+	switch bg := s.Background; {
+	case bg.Image != nil:
+		c.Picture(0, 0, size, size, bg.Image)
+	case bg.End == nil || bg.End == bg.Start:
+		c.Rect(0, 0, size, size, bg.Start)
+	default:
+		c.Gradient(0, 0, size, size, bg.Start, bg.End)
+	}
+	for i := 0; i < game.BoardSize; i++ {
+		c.Line(g.Padding, g.Coord(i), g.Padding+g.Span(), g.Coord(i), 1, s.Grid)
+		c.Line(g.Coord(i), g.Padding, g.Coord(i), g.Padding+g.Span(), 1, s.Grid)
+	}
+}
+
+// Stones draws the stones and blocked points of grid
+func Stones(c Canvas, g Geometry, s Style, grid *[game.BoardSize][game.BoardSize]game.Player) {
+	for i := range grid {
+		for j, cell := range grid[i] {
+			switch cell {
+			case game.Black:
+				c.Circle(g.Coord(j), g.Coord(i), g.Stone/2, s.Black, nil, 0)
+			case game.White:
+				c.Circle(g.Coord(j), g.Coord(i), g.Stone/2, s.White, nil, 0)
+			case game.Red:
+				c.Circle(g.Coord(j), g.Coord(i), g.Stone/2, s.Red, nil, 0)
+			case game.Blocked:
+				Obstacle(c, g, s, i, j)
+			}
+		}
+	}
+}
+
+// Marker draws the last move marker, a small cross, on (row, col)
+func Marker(c Canvas, g Geometry, s Style, row, col int) {
+	x, y, arm := g.Coord(col), g.Coord(row), g.Marker/2
+	c.Line(x-arm, y, x+arm, y, 2, s.Marker)
+	c.Line(x, y-arm, x, y+arm, 2, s.Marker)
+}
+
+// Obstacle draws a blocked point on (row, col), a crossed square the size
+// of a stone, so it cannot be mistaken for one
+func Obstacle(c Canvas, g Geometry, s Style, row, col int) {
+	x, y, half := g.Coord(col), g.Coord(row), g.Stone/2
+	c.Rect(x-half, y-half, g.Stone, g.Stone, s.Blocked)
+	c.Line(x-half, y-half, x+half, y+half, 2, s.Grid)
+	c.Line(x-half, y+half, x+half, y-half, 2, s.Grid)
+}
+
+// BoardImage draws the position on board with the default look, at scale
+// pixels per point
+func BoardImage(board *game.Board, scale float32) *image.RGBA {
+	img := NewImage(DefaultGeometry.Total(), DefaultGeometry.Total(), scale)
+	last := game.PassMove
+	if move, ok := board.LastMove(); ok {
+		last = move.Pos()
+	}
+	Board(img, DefaultGeometry, DefaultStyle, &board.Grid, last)
+	return img.RGBA
+}
@@ -0,0 +1,187 @@
+// Package rendertest checks board renderings against snapshot images. It
+// draws a set of canonical positions, covering stones, the last move marker,
+// finished games and the edges of the board, with a given geometry and
+// style, and compares them pixel for pixel with PNG files. Authors of
+// themes and content packs can run it against their own snapshots from a
+// Go test:
+//
+//	func TestStyle(t *testing.T) {
+//		rendertest.AssertImages(t, "testdata", render.DefaultGeometry, myStyle)
+//	}
+//
+// A missing or different snapshot fails the test and writes the rendering to
+// a failed folder next to the snapshots, from where it can be copied over
+// them once checked by eye.
+package rendertest
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"simple-gomoku/game"
+	"simple-gomoku/render"
+	"simple-gomoku/render/fynecanvas"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/test"
+)
+
+// Fixture is a canonical position, given as the moves leading to it
+type Fixture struct {
+	Name  string     // Snapshot file name, without the extension
+	Rules game.Rules // Rules the moves are played under
+	Moves string     // Moves in coordinate notation, separated by spaces
+}
+
+var gravityRules = game.Rules{WinLength: game.WinCondition, Gravity: true}
+
+// Fixtures are the positions every style is checked with
+var Fixtures = []Fixture{
+	{Name: "empty", Rules: game.StandardRules},
+	{Name: "opening", Rules: game.StandardRules, Moves: "h8 i9 g9 i7"},
+	{Name: "five", Rules: game.StandardRules, Moves: "h8 h9 i8 i9 j8 j9 k8 k9 l8"},
+	{Name: "edges", Rules: game.StandardRules, Moves: "a1 o1 o15 a15 a8 o8 h1 h15"},
+	{Name: "crowded", Rules: game.StandardRules,
+		Moves: "h8 i9 g9 i7 i8 g8 j9 f10 g7 h6 f8 e9 j7 k6 f6 e5 g6 h7 e7 d8 k8 l8"},
+	{Name: "pass", Rules: game.StandardRules, Moves: "h8 i9 pass"},
+	{Name: "gravity", Rules: gravityRules, Moves: "h1 h2 i1 g1 i2 j1 h3 i3"},
+}
+
+// Board plays the fixture's moves on a new board
+func (f Fixture) Board() (*game.Board, error) {
+	board, err := game.NewBoardWithRules(f.Rules)
+	if err != nil {
+		return nil, err
+	}
+	for _, move := range strings.Fields(f.Moves) {
+		row, col, err := game.ParseMove(move)
+		if err == nil && [2]int{row, col} == game.PassMove {
+			err = board.Pass()
+		} else if err == nil {
+			err = board.PlaceStone(row, col)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("fixture %s, move %s: %w", f.Name, move, err)
+		}
+	}
+	return board, nil
+}
+
+// Draw draws the fixture with g and s on c
+func (f Fixture) Draw(c render.Canvas, g render.Geometry, s render.Style) error {
+	board, err := f.Board()
+	if err != nil {
+		return err
+	}
+	last := game.PassMove
+	if move, ok := board.LastMove(); ok {
+		last = move.Pos()
+	}
+	render.Board(c, g, s, &board.Grid, last)
+	return nil
+}
+
+// Render draws the fixture with the image backend, one pixel per point
+func (f Fixture) Render(g render.Geometry, s render.Style) (*image.RGBA, error) {
+	img := render.NewImage(g.Total(), g.Total(), 1)
+	if err := f.Draw(img, g, s); err != nil {
+		return nil, err
+	}
+	return img.RGBA, nil
+}
+
+// AssertImages renders every fixture with the image backend and compares it
+// with the snapshot of the same name in dir, such as dir/opening.png
+func AssertImages(t *testing.T, dir string, g render.Geometry, s render.Style) {
+	t.Helper()
+	for _, f := range Fixtures {
+		img, err := f.Render(g, s)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if err := compare(dir, f.Name+".png", img); err != nil {
+			t.Errorf("fixture %s: %v", f.Name, err)
+		}
+	}
+}
+
+// AssertFyne renders every fixture with the Fyne backend on Fyne's test
+// canvas and compares it with the snapshot testdata/name/fixture.png, as
+// Fyne's own test.AssertImageMatches does
+func AssertFyne(t *testing.T, name string, g render.Geometry, s render.Style) {
+	t.Helper()
+	app := test.NewTempApp(t)
+	for _, f := range Fixtures {
+		c := fynecanvas.New()
+		if err := f.Draw(c, g, s); err != nil {
+			t.Error(err)
+			continue
+		}
+		window := app.NewWindow(f.Name)
+		window.SetPadded(false)
+		window.SetContent(c.Container)
+		window.Resize(fyne.NewSize(g.Total(), g.Total()))
+		test.AssertImageMatches(t, filepath.Join(name, f.Name+".png"), window.Canvas().Capture())
+		window.Close()
+	}
+}
+
+// compare checks img against the snapshot file in dir, writing img to the
+// failed folder in dir when they differ or there is no snapshot yet
+func compare(dir, file string, img *image.RGBA) error {
+	want, err := readPNG(filepath.Join(dir, file))
+	switch {
+	case os.IsNotExist(err):
+		err = fmt.Errorf("no snapshot %s", filepath.Join(dir, file))
+	case err == nil && !equal(want, img):
+		err = fmt.Errorf("rendering differs from %s", filepath.Join(dir, file))
+	case err == nil:
+		return nil
+	}
+	failed := filepath.Join(dir, "failed", file)
+	if writeErr := writePNG(failed, img); writeErr != nil {
+		return fmt.Errorf("%w; the rendering could not be saved: %v", err, writeErr)
+	}
+	return fmt.Errorf("%w; the rendering is saved to %s", err, failed)
+}
+
+// equal reports whether two images have the same size and pixels
+func equal(a image.Image, b *image.RGBA) bool {
+	if a.Bounds().Size() != b.Bounds().Size() {
+		return false
+	}
+	rgba := image.NewRGBA(image.Rectangle{Max: a.Bounds().Size()})
+	draw.Draw(rgba, rgba.Bounds(), a, a.Bounds().Min, draw.Src)
+	return string(rgba.Pix) == string(b.Pix)
+}
+
+func readPNG(path string) (image.Image, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return png.Decode(file)
+}
+
+func writePNG(path string, img image.Image) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(file, img); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
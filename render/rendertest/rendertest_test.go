@@ -0,0 +1,15 @@
+package rendertest
+
+import (
+	"testing"
+
+	"simple-gomoku/render"
+)
+
+func TestDefaultStyleImages(t *testing.T) {
+	AssertImages(t, "testdata", render.DefaultGeometry, render.DefaultStyle)
+}
+
+func TestDefaultStyleFyne(t *testing.T) {
+	AssertFyne(t, "fyne", render.DefaultGeometry, render.DefaultStyle)
+}
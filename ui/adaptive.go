@@ -0,0 +1,88 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"simple-gomoku/game"
+
+	"fyne.io/fyne/v2"
+)
+
+const (
+	adaptiveRecordsKey = "adaptive.records"
+	adaptivePlayer     = "Player" // Name the records are kept under, and of the first profile
+)
+
+func loadRecordStore() *game.RecordStore {
+	return readRecordStore(profileKey(adaptiveRecordsKey))
+}
+
+// readRecordStore reads the records saved under key
+func readRecordStore(key string) *game.RecordStore {
+	saved := fyne.CurrentApp().Preferences().String(key)
+	if saved == "" {
+		return game.NewRecordStore()
+	}
+	store, err := game.LoadRecordStore(strings.NewReader(saved))
+	if err != nil {
+		backupUnreadable(key, saved, err)
+		return game.NewRecordStore()
+	}
+	return store
+}
+
+func saveRecordStore(store *game.RecordStore) {
+	var saved strings.Builder
+	if err := store.Save(&saved); err == nil {
+		fyne.CurrentApp().Preferences().SetString(profileKey(adaptiveRecordsKey), saved.String())
+	}
+}
+
+// adaptiveAI creates an AI at the strength the player's record calls for
+func adaptiveAI() *game.AI {
+	return loadRecordStore().Record(adaptivePlayer).NewAI(game.White)
+}
+
+// recordAdaptiveResult updates the player's record after an adaptive game and
+// returns a message describing the engine strength for the next game. A draw
+// counts as a game not won.
+func (gw *GameWindow) recordAdaptiveResult(won bool) string {
+	if !gw.adaptive || gw.setupPlies() > 0 {
+		return ""
+	}
+	store := loadRecordStore()
+	record := store.Record(adaptivePlayer)
+	change := record.AddResult(won)
+	saveRecordStore(store)
+	gw.refreshProfileSelect()
+
+	level := game.Ladder[record.CurrentLevel()]
+	switch {
+	case change > 0:
+		return fmt.Sprintf("The engine moves up to %s (%d)", level.Name, level.Elo)
+	case change < 0:
+		return fmt.Sprintf("The engine moves down to %s (%d)", level.Name, level.Elo)
+	default:
+		return fmt.Sprintf("The engine stays at %s (%d)", level.Name, level.Elo)
+	}
+}
+
+// recordRating updates the player's rating after a game against a rated
+// engine and returns a message describing it. Games against unrated engines,
+// another person or an external engine, and drills, are not rated. score is
+// 1 for a win, 0.5 for a draw and 0 for a loss.
+func (gw *GameWindow) recordRating(score float64) string {
+	if !gw.rated() {
+		return ""
+	}
+	elo := gw.ai.Elo()
+	store := loadRecordStore()
+	record := store.Record(adaptivePlayer)
+	before := record.PlayerRating(time.Now())
+	after := record.AddRatedResult(elo, score, time.Now())
+	saveRecordStore(store)
+	gw.refreshProfileSelect()
+	return fmt.Sprintf("Your rating: %s (%+.0f)", after, after.Rating-before.Rating)
+}
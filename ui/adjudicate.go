@@ -0,0 +1,84 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+
+	"simple-gomoku/game"
+
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// adjudicate asks the engine for the probable result of the unfinished game,
+// one the players are abandoning, and ends the game with it once confirmed.
+// Adjudicated games are not rated and leave ladder, adaptive and gauntlet
+// progress alone, since nobody played them out.
+func (gw *GameWindow) adjudicate() {
+	switch {
+	case gw.isProcessing:
+		gw.showToast("Wait for the engine to finish")
+		return
+	case gw.setup != nil:
+		gw.showToast("Finish setting up the position first")
+		return
+	case gw.board.IsGameFinished():
+		gw.showToast("The game is already over")
+		return
+	case len(gw.board.MoveHistory) == 0:
+		gw.showToast("There are no moves to adjudicate")
+		return
+	}
+	gw.isProcessing = true
+	ctx, cancel := context.WithCancel(context.Background())
+	gw.cancelAI = cancel
+	board, position := gw.board, gw.board.Clone()
+
+	progress := dialog.NewCustom("Adjudicating", "Cancel", container.NewVBox(
+		widget.NewLabel(fmt.Sprintf("The engine is searching the position %d moves deep...", game.AdjudicationDepth)),
+		widget.NewProgressBarInfinite(),
+	), gw.window)
+	progress.SetOnClosed(func() {
+		cancel()
+		if gw.board == board {
+			gw.cancelAI = nil
+			gw.isProcessing = false
+		}
+	})
+	progress.Show()
+
+	go func() {
+		adjudication, err := game.Adjudicate(ctx, position)
+		if ctx.Err() != nil {
+			return // Cancelled, or the game was replaced
+		}
+		progress.Hide()
+		if err != nil {
+			dialog.ShowError(err, gw.window)
+			return
+		}
+		gw.confirmAdjudication(adjudication)
+	}()
+}
+
+// confirmAdjudication shows the engine's verdict and ends the game with it if
+// the players accept it
+func (gw *GameWindow) confirmAdjudication(a game.Adjudication) {
+	verdict := fmt.Sprintf("The engine expects %s to win, with %.0f%% confidence.",
+		gw.getPlayerText(a.Winner), a.Confidence*100)
+	switch {
+	case a.Winner == game.Empty:
+		verdict = "The engine favours neither side and calls the game a draw."
+	case a.Confidence == 1:
+		verdict = fmt.Sprintf("%s has a forced win.", gw.getPlayerText(a.Winner))
+	}
+	message := verdict + "\nEnd the game with this result? Adjudicated games are not rated."
+	dialog.ShowConfirm("Adjudicate Game", message, func(ok bool) {
+		if !ok || gw.board.EndByAdjudication(a) != nil {
+			return
+		}
+		gw.updateStatus()
+		gw.logEvent("Game over, %s (%.0f%% confidence)", gw.board.Result(), a.Confidence*100)
+	}, gw.window)
+}
@@ -0,0 +1,133 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+
+	"simple-gomoku/game"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+const assistMoves = 3 // Moves engine assist marks
+
+var assistColor = color.RGBA{R: 0, G: 90, B: 200, A: 255}
+
+// rated reports whether the current game counts towards the player's rating:
+// a game from the empty board, without handicap stones or blocked points,
+// under standard rules against a rated engine
+func (gw *GameWindow) rated() bool {
+	return !gw.hotSeat && gw.engine == nil && gw.drill == nil && gw.ai.Elo() != 0 &&
+		gw.board.Rules().Standard() && gw.setupPlies() == 0 && len(gw.board.Handicap()) == 0 &&
+		len(gw.board.Obstacles()) == 0
+}
+
+// assisted reports whether engine assist shows moves to player. Assist only
+// helps people, so White and Red are assisted in hot-seat games alone, and
+// never in rated games, tournament mode, low power mode or when club
+// settings forbid hints.
+func (gw *GameWindow) assisted(player game.Player) bool {
+	if !gw.assist[player] || gw.rated() || gw.kiosk != nil || lowPower() || clubForbids(clubNoHintsKey) {
+		return false
+	}
+	return player == game.Black || gw.hotSeat
+}
+
+// refreshAssist marks the engine's top moves when the side to move has
+// engine assist
+func (gw *GameWindow) refreshAssist() {
+	gw.clearAssist()
+	if gw.board.IsGameFinished() || !gw.assisted(gw.board.GetCurrentPlayer()) {
+		return
+	}
+	for rank, move := range gw.ai.TopMoves(gw.board.Clone(), assistMoves) {
+		gw.markAssist(move.Row, move.Col, rank+1)
+	}
+}
+
+// markAssist rings the cell at (row, col) and numbers it with its rank
+func (gw *GameWindow) markAssist(row, col, rank int) {
+	ring := canvas.NewCircle(color.Transparent)
+	ring.StrokeColor = assistColor
+	ring.StrokeWidth = 2
+	ring.Resize(fyne.NewSize(gw.geom.stone, gw.geom.stone))
+
+	label := canvas.NewText(fmt.Sprint(rank), assistColor)
+	label.TextStyle = fyne.TextStyle{Bold: true}
+	label.TextSize = gw.geom.stone * 0.6
+	label.Alignment = fyne.TextAlignCenter
+	label.Resize(fyne.NewSize(gw.geom.stone, gw.geom.stone))
+
+	mark := container.NewStack(ring, container.NewCenter(label))
+	mark.Resize(fyne.NewSize(gw.geom.stone, gw.geom.stone))
+	mark.Move(fyne.NewPos(
+		gw.geom.coord(col)-gw.geom.stone/2,
+		gw.geom.coord(row)-gw.geom.stone/2,
+	))
+	gw.assistMarks = append(gw.assistMarks, mark)
+	gw.boardContainer.Add(mark)
+}
+
+func (gw *GameWindow) clearAssist() {
+	for _, mark := range gw.assistMarks {
+		gw.boardContainer.Remove(mark)
+	}
+	gw.assistMarks = nil
+}
+
+// newAssistChecks creates the engine assist choices of the game settings
+// dialog, and a function that enables them to suit the chosen game
+func (gw *GameWindow) newAssistChecks() (fyne.CanvasObject, func()) {
+	check := func(player game.Player) *widget.Check {
+		return widget.NewCheck(gw.getPlayerText(player), func(checked bool) {
+			gw.assist[player] = checked
+			gw.refreshAssist()
+		})
+	}
+	blackCheck, whiteCheck, redCheck := check(game.Black), check(game.White), check(game.Red)
+	blackCheck.SetChecked(gw.assist[game.Black])
+	whiteCheck.SetChecked(gw.assist[game.White])
+	redCheck.SetChecked(gw.assist[game.Red])
+	note := widget.NewLabel("")
+
+	refresh := func() {
+		if gw.rated() || gw.kiosk != nil || lowPower() || clubForbids(clubNoHintsKey) {
+			blackCheck.Disable()
+			whiteCheck.Disable()
+			redCheck.Disable()
+			switch {
+			case gw.rated():
+				note.SetText("Not available in rated games")
+			case gw.kiosk != nil:
+				note.SetText("Off in tournament mode")
+			case lowPower():
+				note.SetText("Off in low power mode")
+			default:
+				note.SetText("Off under the " + clubName() + " club settings")
+			}
+			return
+		}
+		blackCheck.Enable()
+		whiteCheck.Disable()
+		redCheck.Disable()
+		if gw.hotSeat {
+			whiteCheck.Enable()
+			redCheck.Enable()
+		}
+		if gw.board.Rules().ThreePlayers {
+			redCheck.Show()
+		} else {
+			redCheck.Hide()
+		}
+		note.SetText(fmt.Sprintf("Shows the top %d moves before that side plays", assistMoves))
+	}
+	refresh()
+	return container.NewVBox(
+		widget.NewLabel("Engine Assist:"),
+		container.NewHBox(blackCheck, whiteCheck, redCheck),
+		note,
+	), refresh
+}
@@ -0,0 +1,156 @@
+package ui
+
+import (
+	"image"
+	"image/color"
+	_ "image/jpeg"
+	_ "image/png"
+	"os"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/theme"
+)
+
+const (
+	backgroundKeyPrefix = "board.background."
+	backgroundImageTag  = "image:"
+)
+
+// boardBackground describes what is drawn under the grid lines
+type boardBackground struct {
+	name  string
+	start color.RGBA
+	end   color.RGBA // Same as start for a solid color
+	image string     // Image file path, replaces the colors when set
+}
+
+var woodColor = color.RGBA{R: 255, G: 223, B: 176, A: 255}
+
+var backgroundPresets = []boardBackground{
+	{name: "Wood", start: woodColor, end: woodColor},
+	{name: "Maple", start: color.RGBA{R: 240, G: 200, B: 140, A: 255}, end: color.RGBA{R: 215, G: 165, B: 105, A: 255}},
+	{name: "Jade", start: color.RGBA{R: 170, G: 215, B: 180, A: 255}, end: color.RGBA{R: 120, G: 180, B: 140, A: 255}},
+	{name: "Slate", start: color.RGBA{R: 96, G: 110, B: 124, A: 255}, end: color.RGBA{R: 96, G: 110, B: 124, A: 255}},
+	{name: "Dusk", start: color.RGBA{R: 50, G: 54, B: 96, A: 255}, end: color.RGBA{R: 104, G: 70, B: 120, A: 255}},
+}
+
+// imageBackground returns a background showing an image file
+func imageBackground(path string) boardBackground {
+	return boardBackground{name: "Custom Image", image: path}
+}
+
+// backgroundKey returns the preference key for the current theme variant,
+// so light and dark themes each keep their own background
+func backgroundKey() string {
+	app := fyne.CurrentApp()
+	if app.Settings().ThemeVariant() == theme.VariantDark {
+		return profileKey(backgroundKeyPrefix + "dark")
+	}
+	return profileKey(backgroundKeyPrefix + "light")
+}
+
+func loadBackground() boardBackground {
+	saved := fyne.CurrentApp().Preferences().String(backgroundKey())
+	if path, ok := strings.CutPrefix(saved, backgroundImageTag); ok {
+		return imageBackground(path)
+	}
+	for _, preset := range backgroundPresets {
+		if preset.name == saved {
+			return preset
+		}
+	}
+	return backgroundPresets[0]
+}
+
+func saveBackground(bg boardBackground) {
+	value := bg.name
+	if bg.image != "" {
+		value = backgroundImageTag + bg.image
+	}
+	fyne.CurrentApp().Preferences().SetString(backgroundKey(), value)
+}
+
+// canvasObject creates the object drawn as the board background
+func (bg boardBackground) canvasObject() fyne.CanvasObject {
+	if bg.image != "" {
+		img := canvas.NewImageFromFile(bg.image)
+		img.FillMode = canvas.ImageFillStretch
+		return img
+	}
+	if bg.start == bg.end {
+		return canvas.NewRectangle(bg.start)
+	}
+	return canvas.NewLinearGradient(bg.start, bg.end, 135)
+}
+
+// isDark reports whether the background is dark enough to need light lines
+func (bg boardBackground) isDark() bool {
+	if bg.image != "" {
+		return imageLuminance(bg.image) < 0.5
+	}
+	return (luminance(bg.start)+luminance(bg.end))/2 < 0.5
+}
+
+// contrastColors returns grid line and last move marker colors that stay
+// readable on a light or dark background
+func contrastColors(dark bool) (grid, marker color.Color) {
+	if dark {
+		return color.RGBA{R: 220, G: 220, B: 220, A: 255}, color.RGBA{R: 255, G: 200, B: 0, A: 255}
+	}
+	return color.Black, color.RGBA{R: 255, G: 0, B: 0, A: 255}
+}
+
+// luminance returns the perceived brightness of a color between 0 and 1
+func luminance(c color.Color) float64 {
+	r, g, b, _ := c.RGBA()
+	return (0.299*float64(r) + 0.587*float64(g) + 0.114*float64(b)) / 0xffff
+}
+
+// imageLuminance returns the average brightness of an image file, sampling
+// a grid of points. Unreadable images count as light.
+func imageLuminance(path string) float64 {
+	f, err := os.Open(path)
+	if err != nil {
+		return 1
+	}
+	defer f.Close()
+
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return 1
+	}
+
+	const samples = 16
+	bounds := img.Bounds()
+	total := 0.0
+	for i := 0; i < samples; i++ {
+		for j := 0; j < samples; j++ {
+			x := bounds.Min.X + (2*i+1)*bounds.Dx()/(2*samples)
+			y := bounds.Min.Y + (2*j+1)*bounds.Dy()/(2*samples)
+			total += luminance(img.At(x, y))
+		}
+	}
+	return total / (samples * samples)
+}
+
+// applyBackground replaces the board background and adjusts the grid lines
+// and last move marker to stay readable on it
+func (gw *GameWindow) applyBackground(bg boardBackground) {
+	background := bg.canvasObject()
+	background.Resize(fyne.NewSize(gw.geom.total(), gw.geom.total()))
+	background.Move(fyne.NewPos(0, 0))
+	gw.boardContainer.Objects[0] = background
+
+	gridColor, markerColor := contrastColors(bg.isDark())
+	for _, line := range gw.gridLines {
+		line.StrokeColor = gridColor
+		line.Refresh()
+	}
+	gw.markerColor = markerColor
+	if lastMove, ok := gw.board.LastMove(); ok {
+		gw.updateLastMoveMarker(lastMove.Row, lastMove.Col)
+	}
+	gw.boardContainer.Refresh()
+}
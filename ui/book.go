@@ -0,0 +1,105 @@
+package ui
+
+import (
+	"slices"
+
+	"simple-gomoku/game"
+
+	"fyne.io/fyne/v2"
+)
+
+const (
+	bookLearningKey = "book.learn"
+	bookLearnPlies  = game.BookPlies // Opening moves of each game added to the book
+)
+
+// learnedBookFile is the file in the app's storage holding the openings the
+// active profile's games have added to the book
+func learnedBookFile() string {
+	return profileKey("learned-book") + ".json"
+}
+
+func bookLearning() bool {
+	return fyne.CurrentApp().Preferences().Bool(profileKey(bookLearningKey))
+}
+
+// loadLearnedBook reads the active profile's learned openings, or returns an
+// empty book if there are none yet
+func loadLearnedBook() *game.Book {
+	reader, err := fyne.CurrentApp().Storage().Open(learnedBookFile())
+	if err != nil {
+		return game.NewBook("Learned")
+	}
+	defer reader.Close()
+	book, err := game.LoadBook(reader)
+	if err != nil {
+		fyne.LogError("Could not read the learned openings", err)
+		return game.NewBook("Learned")
+	}
+	return book
+}
+
+func saveLearnedBook(book *game.Book) error {
+	store := fyne.CurrentApp().Storage()
+	save := store.Create
+	if slices.Contains(store.List(), learnedBookFile()) {
+		save = store.Save // Save only overwrites existing files
+	}
+	writer, err := save(learnedBookFile())
+	if err != nil {
+		return err
+	}
+	if err := book.Save(writer); err != nil {
+		writer.Close()
+		return err
+	}
+	return writer.Close()
+}
+
+// applyOpeningBook makes the engines open from the built-in book, merged
+// with the active profile's learned openings when learning is on
+func (gw *GameWindow) applyOpeningBook() {
+	game.SetDefaultBook(nil)
+	gw.learnedBook = nil
+	if bookLearning() {
+		gw.learnedBook = loadLearnedBook()
+		book := game.DefaultBook().Clone()
+		book.Merge(gw.learnedBook)
+		game.SetDefaultBook(book)
+	}
+	if gw.ai != nil && gw.drill == nil {
+		gw.ai.SetBook(game.DefaultBook())
+	}
+}
+
+// learnOpening adds the opening of the finished game and its result to the
+// book, if learning is on. Drills follow set lines and set-up positions,
+// handicap games and boards with blocked points are not openings, so none
+// is learned from, and the book only holds five-in-a-row openings.
+func (gw *GameWindow) learnOpening(winner game.Player) {
+	if gw.learnedBook == nil || gw.drill != nil || !gw.board.Rules().Standard() || gw.setupPlies() > 0 ||
+		len(gw.board.Handicap()) > 0 || len(gw.board.Obstacles()) > 0 {
+		return
+	}
+	moves := gw.board.Positions()
+	if err := gw.learnedBook.Learn(moves, winner, bookLearnPlies); err != nil {
+		fyne.LogError("Could not learn the opening", err)
+		return
+	}
+	game.DefaultBook().Learn(moves, winner, bookLearnPlies)
+	if err := saveLearnedBook(gw.learnedBook); err != nil {
+		fyne.LogError("Could not save the learned openings", err)
+	}
+}
+
+// forgetLearnedOpenings deletes the active profile's learned openings
+func (gw *GameWindow) forgetLearnedOpenings() {
+	store := fyne.CurrentApp().Storage()
+	if slices.Contains(store.List(), learnedBookFile()) {
+		if err := store.Remove(learnedBookFile()); err != nil {
+			fyne.LogError("Could not delete the learned openings", err)
+		}
+	}
+	gw.applyOpeningBook()
+	gw.logEvent("Learned openings forgotten")
+}
@@ -0,0 +1,182 @@
+package ui
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"simple-gomoku/game"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	issuesURL = "https://github.com/aidenwang9867/simple-gomoku/issues/new"
+
+	// issueLogLines is how much of the session log goes into an issue URL,
+	// which browsers and GitHub limit in length; the zip has all of it
+	issueLogLines = 30
+	issueMaxBody  = 6000
+)
+
+// bugReport is the diagnostic bundle attached to a bug report
+type bugReport struct {
+	description string
+	system      string
+	sessionLog  string
+	game        string         // Current game in SGF
+	preferences map[string]any // nil when the user leaves them out
+}
+
+// newBugReport gathers the diagnostics for a report
+func (gw *GameWindow) newBugReport(description string, withPreferences bool) bugReport {
+	opponent := gw.difficultyName
+	if gw.engine != nil {
+		opponent = "external engine " + gw.engine.Name()
+	}
+	report := bugReport{
+		description: strings.TrimSpace(description),
+		system:      systemInfo() + "\nOpponent: " + opponent,
+		sessionLog:  gw.sessionLog.text(),
+		game:        game.FormatSGF(gw.board),
+	}
+	if withPreferences {
+		report.preferences = preferenceValues()
+	}
+	return report
+}
+
+// systemInfo describes the build and the machine it runs on
+func systemInfo() string {
+	version := fyne.CurrentApp().Metadata().Version
+	if info, ok := debug.ReadBuildInfo(); ok {
+		if version == "" {
+			version = info.Main.Version
+		}
+		for _, setting := range info.Settings {
+			if setting.Key == "vcs.revision" {
+				version += " (" + setting.Value + ")"
+			}
+		}
+	}
+	return fmt.Sprintf("Version: %s\nOS: %s/%s\nGo: %s", version, runtime.GOOS, runtime.GOARCH, runtime.Version())
+}
+
+// issueURL returns the address of a new GitHub issue prefilled with the
+// report, shortened to fit in a URL
+func (r bugReport) issueURL() string {
+	lines := strings.Split(strings.TrimSpace(r.sessionLog), "\n")
+	if len(lines) > issueLogLines {
+		lines = lines[len(lines)-issueLogLines:]
+	}
+	body := fmt.Sprintf("%s\n\n### System\n```\n%s\n```\n\n### Game\n```\n%s\n```\n\n### Session log (last %d lines)\n```\n%s\n```\n",
+		r.descriptionOrPlaceholder(), r.system, r.game, len(lines), strings.Join(lines, "\n"))
+	if len(body) > issueMaxBody {
+		body = strings.ToValidUTF8(body[:issueMaxBody], "") + "\n...\n```\n(truncated, please attach the saved report)"
+	}
+
+	title := r.description
+	if i := strings.IndexByte(title, '\n'); i >= 0 {
+		title = title[:i]
+	}
+	values := url.Values{"title": {title}, "body": {body}}
+	return issuesURL + "?" + values.Encode()
+}
+
+func (r bugReport) descriptionOrPlaceholder() string {
+	if r.description == "" {
+		return "(no description)"
+	}
+	return r.description
+}
+
+// writeZip writes the report and its attachments as a zip archive
+func (r bugReport) writeZip(w io.Writer) error {
+	archive := zip.NewWriter(w)
+	files := []struct{ name, content string }{
+		{"report.txt", r.descriptionOrPlaceholder() + "\n\n" + r.system + "\n"},
+		{"session-log.txt", r.sessionLog},
+		{"game.sgf", r.game + "\n"},
+	}
+	if r.preferences != nil {
+		data, err := json.MarshalIndent(r.preferences, "", "  ")
+		if err != nil {
+			return err
+		}
+		files = append(files, struct{ name, content string }{"preferences.json", string(data)})
+	}
+	for _, file := range files {
+		f, err := archive.Create(file.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f, file.content); err != nil {
+			return err
+		}
+	}
+	return archive.Close()
+}
+
+// showBugReportDialog lets the user describe a problem and send it as a
+// prefilled GitHub issue or save the diagnostics to attach by hand
+func (gw *GameWindow) showBugReportDialog() {
+	descriptionEntry := widget.NewMultiLineEntry()
+	descriptionEntry.SetPlaceHolder("What happened, and what did you expect?")
+	descriptionEntry.SetMinRowsVisible(5)
+	preferencesCheck := widget.NewCheck("Include settings in the zip (may contain file paths)", nil)
+	preferencesCheck.SetChecked(true)
+
+	var reportDialog dialog.Dialog
+	issueButton := widget.NewButton("Open GitHub Issue", func() {
+		report := gw.newBugReport(descriptionEntry.Text, preferencesCheck.Checked)
+		issue, err := url.Parse(report.issueURL())
+		if err == nil {
+			err = fyne.CurrentApp().OpenURL(issue)
+		}
+		if err != nil {
+			dialog.ShowError(err, gw.window)
+			return
+		}
+		gw.logEvent("Bug report opened on GitHub")
+		reportDialog.Hide()
+	})
+	saveButton := widget.NewButton("Save Zip...", func() {
+		report := gw.newBugReport(descriptionEntry.Text, preferencesCheck.Checked)
+		save := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+			if err != nil || writer == nil {
+				return
+			}
+			defer writer.Close()
+			if err := report.writeZip(writer); err != nil {
+				dialog.ShowError(err, gw.window)
+				return
+			}
+			gw.logEvent("Bug report saved")
+			gw.showToast("Bug report saved, attach it to an issue")
+			reportDialog.Hide()
+		}, gw.window)
+		save.SetFileName("gomoku-bug-report-" + time.Now().Format("20060102-150405") + ".zip")
+		save.SetFilter(storage.NewExtensionFileFilter([]string{".zip"}))
+		save.Show()
+	})
+
+	content := container.NewVBox(
+		widget.NewLabel("Describe the problem. The report includes the session log, the current game,\nthe app version and your operating system."),
+		descriptionEntry,
+		preferencesCheck,
+		container.NewHBox(issueButton, saveButton),
+	)
+	reportDialog = dialog.NewCustom("Report a Bug", "Cancel", content, gw.window)
+	reportDialog.Resize(fyne.NewSize(480, 360))
+	reportDialog.Show()
+}
@@ -0,0 +1,303 @@
+package ui
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strings"
+
+	"simple-gomoku/game"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	clubNameKey    = "club.name"    // Club whose settings file was imported, "" for none
+	clubNoUndoKey  = "club.noUndo"  // The club's league play allows no undo
+	clubNoHintsKey = "club.noHints" // The club's league play allows no hints or engine assist
+
+	clubFileVersion = 1
+)
+
+// clubKeys are the settings a club file carries. Personal settings such as
+// engine paths and shortcuts stay with each member, and so do image
+// backgrounds, whose files exist on one machine only.
+var clubKeys = []string{
+	winLengthKey, exactKey, openingKey, gravityKey, threePlayersKey, obstaclesKey, backgroundKeyPrefix + "light", backgroundKeyPrefix + "dark",
+	winEffectKey, loseEffectKey, reducedMotionKey, commentaryOffKey,
+	clubNameKey, clubNoUndoKey, clubNoHintsKey,
+}
+
+// clubFile is a settings file a club hands out so every member's app is set
+// up the same way for league play. Settings are stored by preference key.
+type clubFile struct {
+	Version  int            `json:"version"`
+	Settings map[string]any `json:"settings"`
+}
+
+// clubName returns the club whose settings are in use, "" for none
+func clubName() string {
+	return fyne.CurrentApp().Preferences().String(profileKey(clubNameKey))
+}
+
+// clubForbids reports whether the imported club settings forbid what the
+// bool setting key restricts
+func clubForbids(key string) bool {
+	return fyne.CurrentApp().Preferences().Bool(profileKey(key))
+}
+
+// clubRestrictions describes the restrictions in force, "" for none
+func clubRestrictions() string {
+	var rules []string
+	if clubForbids(clubNoUndoKey) {
+		rules = append(rules, "no undo")
+	}
+	if clubForbids(clubNoHintsKey) {
+		rules = append(rules, "no hints")
+	}
+	return strings.Join(rules, ", ")
+}
+
+// newClubFile returns the active profile's club settings as a club file
+// for the club name, with the restrictions chosen
+func newClubFile(name string, noUndo, noHints bool) clubFile {
+	prefs := fyne.CurrentApp().Preferences()
+	settings := map[string]any{clubNameKey: name}
+	if noUndo {
+		settings[clubNoUndoKey] = true
+	}
+	if noHints {
+		settings[clubNoHintsKey] = true
+	}
+	for _, key := range clubKeys {
+		if _, ok := settings[key]; ok {
+			continue
+		}
+		switch {
+		case slices.Contains(intPrefKeys, key):
+			if value := prefs.Int(profileKey(key)); value != 0 {
+				settings[key] = value
+			}
+		case slices.Contains(boolPrefKeys, key):
+			if prefs.Bool(profileKey(key)) {
+				settings[key] = true
+			}
+		default:
+			if value := prefs.String(profileKey(key)); value != "" && !strings.HasPrefix(value, backgroundImageTag) {
+				settings[key] = value
+			}
+		}
+	}
+	return clubFile{Version: clubFileVersion, Settings: settings}
+}
+
+// readClubFile parses and checks a club file, so a damaged or foreign file
+// changes nothing
+func readClubFile(r io.Reader) (clubFile, error) {
+	var club clubFile
+	if err := json.NewDecoder(r).Decode(&club); err != nil {
+		return club, fmt.Errorf("not a club settings file: %w", err)
+	}
+	if club.Version < 1 || club.Version > clubFileVersion {
+		return club, fmt.Errorf("club settings version %d is not supported by this version of the game", club.Version)
+	}
+	if name, _ := club.Settings[clubNameKey].(string); strings.TrimSpace(name) == "" {
+		return club, errors.New("the club settings have no club name")
+	}
+	for key, value := range club.Settings {
+		if err := checkClubSetting(key, value); err != nil {
+			return club, fmt.Errorf("club setting %s: %w", key, err)
+		}
+	}
+	return club, nil
+}
+
+func checkClubSetting(key string, value any) error {
+	switch {
+	case !slices.Contains(clubKeys, key):
+		return errors.New("not a setting clubs can share")
+	case slices.Contains(intPrefKeys, key):
+		number, ok := value.(float64)
+		if !ok || number != math.Trunc(number) {
+			return errors.New("not a whole number")
+		}
+		if key == winLengthKey && number != 0 {
+			return game.Rules{WinLength: int(number)}.Validate()
+		}
+		if key == openingKey {
+			return game.Rules{WinLength: game.WinCondition, Opening: game.OpeningRule(number)}.Validate()
+		}
+	case slices.Contains(boolPrefKeys, key):
+		if _, ok := value.(bool); !ok {
+			return errors.New("not true or false")
+		}
+	default:
+		text, ok := value.(string)
+		if !ok {
+			return errors.New("not text")
+		}
+		var options []string
+		switch key {
+		case winEffectKey:
+			options = winEffects
+		case loseEffectKey:
+			options = loseEffects
+		case backgroundKeyPrefix + "light", backgroundKeyPrefix + "dark":
+			for _, preset := range backgroundPresets {
+				options = append(options, preset.name)
+			}
+		}
+		if options != nil && !slices.Contains(options, text) {
+			return fmt.Errorf("unknown choice %q", text)
+		}
+	}
+	return nil
+}
+
+// apply replaces the active profile's club settings with the file's. Settings
+// the file leaves out go back to their defaults, so every member ends up
+// with the same configuration.
+func (club clubFile) apply() {
+	prefs := fyne.CurrentApp().Preferences()
+	for _, key := range clubKeys {
+		value := club.Settings[key]
+		switch {
+		case slices.Contains(intPrefKeys, key):
+			number, _ := value.(float64)
+			prefs.SetInt(profileKey(key), int(number))
+		case slices.Contains(boolPrefKeys, key):
+			flag, _ := value.(bool)
+			prefs.SetBool(profileKey(key), flag)
+		default:
+			text, _ := value.(string)
+			prefs.SetString(profileKey(key), text)
+		}
+	}
+}
+
+// applyClubSettings brings the window in line with newly imported settings
+func (gw *GameWindow) applyClubSettings() {
+	gw.applyBackground(loadBackground())
+	if rules := savedRules(); rules != gw.rules {
+		gw.rules = rules
+		gw.stopAI()
+		gw.board = gw.newBoard()
+		gw.updateBoard()
+		gw.logEvent("Playing %s", gw.board.Rules())
+	}
+	gw.clearComment()
+	gw.updateStatus()
+}
+
+// showExportClubDialog asks for the club name and restrictions, then saves
+// the current settings as a club file
+func (gw *GameWindow) showExportClubDialog() {
+	nameEntry := widget.NewEntry()
+	nameEntry.SetText(clubName())
+	nameEntry.SetPlaceHolder("Club name")
+	noUndoCheck := widget.NewCheck("No undo", nil)
+	noUndoCheck.SetChecked(clubForbids(clubNoUndoKey))
+	noHintsCheck := widget.NewCheck("No hints or engine assist", nil)
+	noHintsCheck.SetChecked(clubForbids(clubNoHintsKey))
+
+	content := container.NewVBox(
+		widget.NewLabel("Saves the game rules, board backgrounds, effects and\ncommentary settings of this profile for club members to import."),
+		nameEntry,
+		widget.NewLabel("League restrictions:"),
+		noUndoCheck,
+		noHintsCheck,
+	)
+	dialog.ShowCustomConfirm("Export Club Settings", "Save...", "Cancel", content, func(ok bool) {
+		name := strings.TrimSpace(nameEntry.Text)
+		if !ok {
+			return
+		}
+		if name == "" {
+			dialog.ShowError(errors.New("the club settings need a club name"), gw.window)
+			return
+		}
+		club := newClubFile(name, noUndoCheck.Checked, noHintsCheck.Checked)
+		save := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+			if err != nil || writer == nil {
+				return
+			}
+			defer writer.Close()
+			encoder := json.NewEncoder(writer)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(club); err != nil {
+				dialog.ShowError(err, gw.window)
+				return
+			}
+			gw.logEvent("Club settings for %s exported", name)
+			gw.showToast("Club settings saved")
+		}, gw.window)
+		save.SetFileName(strings.ReplaceAll(strings.ToLower(name), " ", "-") + ".gomoku-club.json")
+		save.SetFilter(storage.NewExtensionFileFilter([]string{".json"}))
+		save.Show()
+	}, gw.window)
+}
+
+// importClubSettings loads a club file chosen by the player into the active
+// profile
+func (gw *GameWindow) importClubSettings(done func()) {
+	open := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil || reader == nil {
+			return
+		}
+		defer reader.Close()
+		club, err := readClubFile(reader)
+		if err != nil {
+			dialog.ShowError(err, gw.window)
+			return
+		}
+		club.apply()
+		gw.applyClubSettings()
+		gw.logEvent("Club settings for %s imported", clubName())
+		gw.showToast("Now using the %s club settings", clubName())
+		done()
+	}, gw.window)
+	open.SetFilter(storage.NewExtensionFileFilter([]string{".json"}))
+	open.Show()
+}
+
+// leaveClub lifts the club's restrictions, keeping the other settings
+func (gw *GameWindow) leaveClub() {
+	prefs := fyne.CurrentApp().Preferences()
+	gw.logEvent("Left the %s club settings", clubName())
+	prefs.SetString(profileKey(clubNameKey), "")
+	prefs.SetBool(profileKey(clubNoUndoKey), false)
+	prefs.SetBool(profileKey(clubNoHintsKey), false)
+	gw.updateStatus()
+}
+
+// newClubSettings creates the club section of the settings dialog
+func (gw *GameWindow) newClubSettings() fyne.CanvasObject {
+	clubLabel := widget.NewLabel("")
+	var leaveButton *widget.Button
+	refresh := func() {
+		clubLabel.SetText("None")
+		leaveButton.Disable()
+		if name := clubName(); name != "" {
+			if rules := clubRestrictions(); rules != "" {
+				name += " (" + rules + ")"
+			}
+			clubLabel.SetText(name)
+			leaveButton.Enable()
+		}
+	}
+	leaveButton = widget.NewButton("Leave", func() {
+		gw.leaveClub()
+		refresh()
+	})
+	importButton := widget.NewButton("Import...", func() { gw.importClubSettings(refresh) })
+	exportButton := widget.NewButton("Export...", gw.showExportClubDialog)
+	refresh()
+	return container.NewHBox(clubLabel, importButton, exportButton, leaveButton)
+}
@@ -0,0 +1,98 @@
+package ui
+
+import (
+	"image/color"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+)
+
+const (
+	commentaryOffKey = "commentary.off"
+
+	commentDuration = 3500 * time.Millisecond
+	commentPadding  = 8
+	commentMargin   = 8
+	commentGap      = 3 // Fewest moves between two comments, so the engine is not chatty
+)
+
+var (
+	commentBackground = color.NRGBA{R: 250, G: 250, B: 245, A: 235}
+	commentText       = color.NRGBA{R: 30, G: 30, B: 30, A: 255}
+)
+
+// commentary holds the speech bubble shown over the board
+type commentary struct {
+	bubble *fyne.Container
+	ply    int // Moves on the board at the last comment
+}
+
+func commentaryEnabled() bool {
+	return !fyne.CurrentApp().Preferences().Bool(profileKey(commentaryOffKey))
+}
+
+// commentOnMove lets the engine remark on the move just played, in a speech
+// bubble over the board. People playing each other, external engines,
+// drills and three-player games get no comments.
+func (gw *GameWindow) commentOnMove() {
+	if !commentaryEnabled() || gw.hotSeat || gw.engine != nil || gw.drill != nil || gw.kiosk != nil ||
+		gw.board.Rules().ThreePlayers {
+		return
+	}
+	ply := len(gw.board.MoveHistory)
+	if since := ply - gw.comments.ply; since > 0 && since < commentGap {
+		return
+	}
+	if remark := gw.ai.Comment(gw.board); remark != "" {
+		gw.comments.ply = ply
+		gw.showComment(gw.difficultyName, remark)
+	}
+}
+
+// showComment shows a chat-style bubble with the speaker's name at the top
+// of the board, replacing any earlier one
+func (gw *GameWindow) showComment(speaker, remark string) {
+	gw.clearComment()
+
+	name := canvas.NewText(speaker, commentText)
+	name.TextSize = theme.CaptionTextSize()
+	name.TextStyle = fyne.TextStyle{Bold: true}
+	text := canvas.NewText(remark, commentText)
+	text.TextSize = theme.TextSize()
+	background := canvas.NewRectangle(commentBackground)
+	background.CornerRadius = 10
+
+	width := max(name.MinSize().Width, text.MinSize().Width) + 2*commentPadding
+	size := fyne.NewSize(width, name.MinSize().Height+text.MinSize().Height+commentPadding)
+	background.Resize(size)
+	name.Move(fyne.NewPos(commentPadding, commentPadding/2))
+	text.Move(fyne.NewPos(commentPadding, commentPadding/2+name.MinSize().Height))
+
+	// A small tail at the bottom left makes it read as speech
+	tail := canvas.NewRectangle(commentBackground)
+	tail.Resize(fyne.NewSize(10, 10))
+	tail.Move(fyne.NewPos(2*commentPadding, size.Height-5))
+
+	bubble := container.NewWithoutLayout(tail, background, name, text)
+	bubble.Resize(size)
+	bubble.Move(fyne.NewPos(commentMargin, commentMargin))
+	gw.comments.bubble = bubble
+	gw.boardContainer.Add(bubble)
+
+	gw.logEvent("%s: %s", speaker, remark)
+	time.AfterFunc(commentDuration, func() {
+		if gw.comments.bubble == bubble {
+			gw.clearComment()
+		}
+	})
+}
+
+func (gw *GameWindow) clearComment() {
+	if gw.comments.bubble != nil {
+		gw.boardContainer.Remove(gw.comments.bubble)
+		gw.comments.bubble = nil
+	}
+}
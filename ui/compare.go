@@ -0,0 +1,287 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"io"
+	"strings"
+
+	"simple-gomoku/game"
+	"simple-gomoku/render"
+	"simple-gomoku/render/fynecanvas"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+const currentGameName = "Current game"
+
+var (
+	compareGeometry = boardGeometry{cell: 30, padding: 22, stone: 24, marker: 8}
+	diffColor       = color.RGBA{R: 220, G: 30, B: 30, A: 255}
+)
+
+// comparison shows two game records side by side, stepping through both at
+// once and ringing the points where their positions differ
+type comparison struct {
+	games  [2]*game.Board
+	names  [2]string
+	common int // Moves the games share from the start
+	step   int // Moves shown, the same in both games
+	views  [2]*comparisonView
+	status *widget.Label
+	moves  *widget.Label
+}
+
+// comparisonView is one of the two boards of a comparison
+type comparisonView struct {
+	stones [][]*canvas.Circle
+	marks  *fyne.Container // Difference rings and the last move marker
+	book   *widget.Label   // The book line the position is in
+}
+
+func newComparison(games [2]*game.Board, names [2]string) *comparison {
+	c := &comparison{games: games, names: names}
+	a, b := games[0].MoveHistory, games[1].MoveHistory
+	for c.common < min(len(a), len(b)) && a[c.common].Pos() == b[c.common].Pos() {
+		c.common++
+	}
+	return c
+}
+
+// length returns the moves of the longer game, the last step
+func (c *comparison) length() int {
+	return max(len(c.games[0].MoveHistory), len(c.games[1].MoveHistory))
+}
+
+// position returns game i after the moves of the current step, or all its
+// moves if it is shorter
+func (c *comparison) position(i int) *game.Board {
+	board := c.games[i].Clone()
+	for len(board.MoveHistory) > c.step {
+		board.Undo()
+	}
+	return board
+}
+
+// divergence describes where the games part
+func (c *comparison) divergence() string {
+	a, b := c.games[0].MoveHistory, c.games[1].MoveHistory
+	switch {
+	case c.common == len(a) && c.common == len(b):
+		return "The games have the same moves"
+	case c.common == len(a):
+		return fmt.Sprintf("%s continues after %s ends at move %d", c.names[1], c.names[0], c.common)
+	case c.common == len(b):
+		return fmt.Sprintf("%s continues after %s ends at move %d", c.names[0], c.names[1], c.common)
+	}
+	return fmt.Sprintf("The games diverge at move %d: %s in %s, %s in %s",
+		c.common+1, a[c.common], c.names[0], b[c.common], c.names[1])
+}
+
+func (c *comparison) goTo(step int) {
+	c.step = max(0, min(step, c.length()))
+	c.refresh()
+}
+
+func (c *comparison) refresh() {
+	positions := [2]*game.Board{c.position(0), c.position(1)}
+	differ := 0
+	for i, view := range c.views {
+		board, other := positions[i], positions[1-i]
+		view.marks.RemoveAll()
+		for row := 0; row < game.BoardSize; row++ {
+			for col := 0; col < game.BoardSize; col++ {
+				frame.paint(view.stones[row][col], displayNormal.stoneColor(board.Grid[row][col]))
+				if board.Grid[row][col] != other.Grid[row][col] {
+					view.marks.Add(diffRing(row, col))
+					differ++
+				}
+			}
+		}
+		if move, ok := board.LastMove(); ok && !move.IsPass() {
+			view.marks.Add(lastMoveDot(move))
+		}
+		view.marks.Refresh()
+		view.book.SetText(bookText(board))
+	}
+
+	status := c.divergence()
+	switch {
+	case c.step > c.common && positions[0].Equal(positions[1]):
+		status += "\nSame position again here, by a different move order"
+	case differ == 2:
+		status += "\n1 point differs here"
+	case differ > 0:
+		status += fmt.Sprintf("\n%d points differ here", differ/2)
+	}
+	c.status.SetText(status)
+	c.moves.SetText(fmt.Sprintf("Move %d of %d", c.step, c.length()))
+}
+
+// bookText names the opening book line the position on board is in, noting
+// when the game reached it by another move order
+func bookText(board *game.Board) string {
+	if len(board.MoveHistory) == 0 {
+		return ""
+	}
+	position, ok := game.DefaultBook().Position(board)
+	if !ok {
+		return "Out of book"
+	}
+	moves := make([]string, len(position.Line))
+	for i, move := range position.Line {
+		moves[i] = game.FormatMove(move[0], move[1])
+	}
+	if position.Transposed {
+		return "Book by transposition: " + strings.Join(moves, " ")
+	}
+	return "Book: " + strings.Join(moves, " ")
+}
+
+// diffRing rings the point at (row, col), which differs between the games
+func diffRing(row, col int) fyne.CanvasObject {
+	g := compareGeometry
+	ring := canvas.NewCircle(color.Transparent)
+	ring.StrokeColor = diffColor
+	ring.StrokeWidth = 2
+	size := g.stone + 4
+	ring.Resize(fyne.NewSize(size, size))
+	ring.Move(fyne.NewPos(g.coord(col)-size/2, g.coord(row)-size/2))
+	return ring
+}
+
+// lastMoveDot marks the last move shown in the stone's opposite color
+func lastMoveDot(move game.Move) fyne.CanvasObject {
+	g := compareGeometry
+	dot := canvas.NewCircle(displayNormal.stoneColor(opposite(move.Player)))
+	dot.Resize(fyne.NewSize(g.marker, g.marker))
+	dot.Move(fyne.NewPos(g.coord(move.Col)-g.marker/2, g.coord(move.Row)-g.marker/2))
+	return dot
+}
+
+// newView draws board i with its name above it
+func (c *comparison) newView(i int) fyne.CanvasObject {
+	g := compareGeometry
+	view := &comparisonView{marks: container.NewWithoutLayout(), book: widget.NewLabel("")}
+	c.views[i] = view
+
+	board := fynecanvas.New()
+	render.Grid(board, g.render(), render.DefaultStyle)
+	view.stones = make([][]*canvas.Circle, game.BoardSize)
+	for row := range view.stones {
+		view.stones[row] = make([]*canvas.Circle, game.BoardSize)
+		for col := range view.stones[row] {
+			stone := canvas.NewCircle(color.Transparent)
+			stone.Resize(fyne.NewSize(g.stone, g.stone))
+			stone.Move(fyne.NewPos(g.coord(col)-g.stone/2, g.coord(row)-g.stone/2))
+			view.stones[row][col] = stone
+			board.Add(stone)
+		}
+	}
+	board.Add(view.marks)
+
+	spacer := canvas.NewRectangle(color.Transparent)
+	spacer.SetMinSize(fyne.NewSize(g.total(), g.total()))
+	title := widget.NewLabel(fmt.Sprintf("%s (%d moves)", c.names[i], len(c.games[i].MoveHistory)))
+	title.TextStyle = fyne.TextStyle{Bold: true}
+	return container.NewBorder(title, view.book, nil, nil, container.NewStack(spacer, board.Container))
+}
+
+// show opens the comparison in a window of its own, so the game in the main
+// window stays as it is
+func (c *comparison) show() {
+	window := fyne.CurrentApp().NewWindow("Compare Games")
+	c.status = widget.NewLabel("")
+	c.moves = widget.NewLabel("")
+	controls := container.NewHBox(
+		widget.NewButtonWithIcon("", theme.MediaSkipPreviousIcon(), func() { c.goTo(0) }),
+		widget.NewButtonWithIcon("", theme.NavigateBackIcon(), func() { c.goTo(c.step - 1) }),
+		c.moves,
+		widget.NewButtonWithIcon("", theme.NavigateNextIcon(), func() { c.goTo(c.step + 1) }),
+		widget.NewButtonWithIcon("", theme.MediaSkipNextIcon(), func() { c.goTo(c.length()) }),
+		widget.NewButton("Go to Divergence", func() { c.goTo(c.common + 1) }),
+	)
+	boards := container.NewHBox(c.newView(0), widget.NewSeparator(), c.newView(1))
+	window.SetContent(container.NewVBox(boards, container.NewCenter(controls), c.status))
+	window.Canvas().SetOnTypedKey(func(event *fyne.KeyEvent) {
+		switch event.Name {
+		case fyne.KeyLeft:
+			c.goTo(c.step - 1)
+		case fyne.KeyRight:
+			c.goTo(c.step + 1)
+		case fyne.KeyHome:
+			c.goTo(0)
+		case fyne.KeyEnd:
+			c.goTo(c.length())
+		}
+	})
+	c.goTo(c.common + 1)
+	window.Show()
+}
+
+// showCompareDialog asks for the two games to compare, the current game and
+// a game record by default
+func (gw *GameWindow) showCompareDialog() {
+	var games [2]*game.Board
+	var names [2]string
+	var labels [2]*widget.Label
+	choose := func(i int, board *game.Board, name string) {
+		games[i], names[i] = board, name
+		labels[i].SetText(fmt.Sprintf("%s (%d moves)", name, len(board.MoveHistory)))
+	}
+
+	rows := container.NewVBox()
+	for i := range games {
+		labels[i] = widget.NewLabel("None chosen")
+		current := widget.NewButton("Current Game", func() { choose(i, gw.board.Clone(), currentGameName) })
+		open := widget.NewButton("Open SGF...", func() {
+			gw.openGameRecord(func(board *game.Board, name string) { choose(i, board, name) })
+		})
+		rows.Add(container.NewBorder(nil, nil, widget.NewLabel(fmt.Sprintf("Game %d:", i+1)),
+			container.NewHBox(current, open), labels[i]))
+	}
+	choose(0, gw.board.Clone(), currentGameName)
+
+	dialog.ShowCustomConfirm("Compare Games", "Compare", "Cancel", rows, func(ok bool) {
+		if !ok {
+			return
+		}
+		if games[0] == nil || games[1] == nil {
+			dialog.ShowError(errors.New("choose two games to compare"), gw.window)
+			return
+		}
+		gw.logEvent("Comparing %s with %s", names[0], names[1])
+		newComparison(games, names).show()
+	}, gw.window)
+}
+
+// openGameRecord asks for an SGF file and passes its game, played under the
+// current rules, to done
+func (gw *GameWindow) openGameRecord(done func(board *game.Board, name string)) {
+	open := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil || reader == nil {
+			return
+		}
+		defer reader.Close()
+		data, err := io.ReadAll(reader)
+		if err != nil {
+			dialog.ShowError(err, gw.window)
+			return
+		}
+		board, err := game.ParseSGF(string(data), gw.board.Rules())
+		if err != nil {
+			dialog.ShowError(err, gw.window)
+			return
+		}
+		done(board, reader.URI().Name())
+	}, gw.window)
+	open.SetFilter(storage.NewExtensionFileFilter([]string{".sgf"}))
+	open.Show()
+}
@@ -0,0 +1,76 @@
+package ui
+
+import (
+	"image/color"
+
+	"simple-gomoku/game"
+)
+
+// displayPolicy decides how stones are drawn, independently of the game state
+type displayPolicy int
+
+const (
+	displayNormal   displayPolicy = iota
+	displayOneColor               // All stones look alike, for board memory training
+)
+
+var (
+	oneColorStone = color.RGBA{R: 90, G: 90, B: 90, A: 255}
+	redStone      = color.RGBA{R: 200, G: 30, B: 30, A: 255} // Third player's stones
+)
+
+func (p displayPolicy) stoneColor(player game.Player) color.Color {
+	switch {
+	case player == game.Empty || player == game.Blocked:
+		return color.Transparent // Blocked points are drawn by markObstacles
+	case p == displayOneColor:
+		return oneColorStone
+	case player == game.Black:
+		return color.Black
+	case player == game.Red:
+		return redStone
+	default:
+		return color.White
+	}
+}
+
+// activePolicy returns the policy to draw with, showing real colors once a
+// one-color game has been revealed
+func (gw *GameWindow) activePolicy() displayPolicy {
+	if gw.revealed {
+		return displayNormal
+	}
+	return gw.displayPolicy
+}
+
+// drawBoard renders the stones of board using the given display policy
+func (gw *GameWindow) drawBoard(board *game.Board, policy displayPolicy) {
+	gw.drawGrid(&board.Grid, policy)
+}
+
+// drawGrid renders stones that need not belong to a game, such as a position
+// being set up
+func (gw *GameWindow) drawGrid(grid *[game.BoardSize][game.BoardSize]game.Player, policy displayPolicy) {
+	for i := 0; i < game.BoardSize; i++ {
+		for j := 0; j < game.BoardSize; j++ {
+			frame.paint(gw.stones[i][j], policy.stoneColor(grid[i][j]))
+		}
+	}
+	gw.markObstacles(grid)
+}
+
+// updateRevealToggle shows the reveal option in one-color mode and only
+// allows it once the game is over
+func (gw *GameWindow) updateRevealToggle() {
+	if gw.displayPolicy != displayOneColor {
+		gw.revealCheck.Hide()
+		return
+	}
+	gw.revealCheck.Show()
+	if gw.board.IsGameFinished() {
+		gw.revealCheck.Enable()
+		return
+	}
+	gw.revealCheck.SetChecked(false)
+	gw.revealCheck.Disable()
+}
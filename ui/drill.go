@@ -0,0 +1,98 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"simple-gomoku/game"
+
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// showDrillDialog lets the player choose an opening to drill
+func (gw *GameWindow) showDrillDialog() {
+	openings := game.DefaultBook().Openings()
+	if len(openings) == 0 {
+		dialog.ShowInformation("Opening Drill", "The opening book has no openings to drill.", gw.window)
+		return
+	}
+
+	schedule := loadSchedule()
+	names := make([]string, len(openings))
+	for i, opening := range openings {
+		names[i] = opening.Name
+		if card, ok := schedule.Cards[opening.Key()]; ok && !card.Due.After(time.Now()) {
+			names[i] += " (due)"
+		}
+	}
+	openingSelect := widget.NewSelect(names, nil)
+	openingSelect.SetSelectedIndex(0)
+
+	content := container.NewVBox(
+		widget.NewLabel("Play Black through the book lines of an opening.\nThe engine varies its replies; every move of yours is graded."),
+		openingSelect,
+	)
+	dialog.ShowCustomConfirm("Opening Drill", "Start", "Cancel", content, func(ok bool) {
+		if ok {
+			gw.startDrill(openings[openingSelect.SelectedIndex()])
+		}
+	}, gw.window)
+}
+
+// startDrill starts a drill of the given opening
+func (gw *GameWindow) startDrill(opening game.Opening) {
+	gw.stopAI()
+	gw.drill = game.NewDrill(game.DefaultBook(), opening, game.Black)
+	gw.ai = gw.drill.NewAI()
+	gw.adaptive = false
+	gw.hotSeat = false
+	gw.ladderLevel = -1
+	gw.gauntlet = nil
+	gw.closeEngine()
+	gw.board = game.NewBoard()
+	gw.updateBoard()
+	gw.updateStatus()
+	gw.logEvent("New drill of the %s", opening.Name)
+}
+
+// gradeDrillMove grades the player's move at (row, col) before it is played
+func (gw *GameWindow) gradeDrillMove(row, col int) {
+	grade := gw.drill.Grade(gw.board, row, col)
+	switch {
+	case grade.Correct && grade.InBook:
+		gw.showToast("Book move")
+	case grade.Correct:
+		gw.showToast("Good move")
+	case grade.InBook:
+		gw.showToast("Out of book; the book plays %s", game.FormatMove(grade.Better[0], grade.Better[1]))
+	default:
+		gw.showToast("Inaccurate; the engine prefers %s", game.FormatMove(grade.Better[0], grade.Better[1]))
+	}
+	result := "wrong"
+	if grade.Correct {
+		result = "correct"
+	}
+	gw.logEvent("Drill move %s graded %s", game.FormatMove(row, col), result)
+}
+
+// finishDrill scores a completed drill and schedules its next review
+func (gw *GameWindow) finishDrill() {
+	drill := gw.drill
+	gw.drill = nil
+
+	schedule := loadSchedule()
+	card := schedule.Review(drill.Opening.Key(), drill.Opening.Name, game.ReviewQuality(drill.Accuracy()), time.Now())
+	saveSchedule(schedule)
+	gw.window.SetMainMenu(gw.mainMenu()) // Update the number of reviews due
+	gw.logEvent("Drill finished: %d of %d correct", drill.Correct, drill.Graded)
+
+	message := fmt.Sprintf("%d of %d moves correct (%.0f%%)\nNext review in %d days",
+		drill.Correct, drill.Graded, drill.Accuracy()*100, card.Interval)
+	dialog.ShowCustomConfirm("Drill Complete", "Again", "Close", widget.NewLabel(message), func(again bool) {
+		if again {
+			gw.startDrill(drill.Opening)
+		}
+	}, gw.window)
+}
@@ -0,0 +1,146 @@
+package ui
+
+import (
+	"image/color"
+	"math"
+	"math/rand"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+)
+
+const (
+	winEffectKey     = "effects.win"
+	loseEffectKey    = "effects.lose"
+	reducedMotionKey = "effects.reducedMotion"
+
+	effectNone     = "None"
+	effectConfetti = "Confetti"
+	effectShimmer  = "Shimmer"
+	effectShake    = "Shake"
+
+	confettiPieces = 60
+)
+
+var (
+	winEffects  = []string{effectConfetti, effectShimmer, effectNone}
+	loseEffects = []string{effectShake, effectShimmer, effectNone}
+
+	confettiColors = []color.Color{
+		color.RGBA{R: 230, G: 57, B: 70, A: 255},
+		color.RGBA{R: 244, G: 162, B: 97, A: 255},
+		color.RGBA{R: 233, G: 196, B: 106, A: 255},
+		color.RGBA{R: 42, G: 157, B: 143, A: 255},
+		color.RGBA{R: 69, G: 123, B: 157, A: 255},
+	}
+)
+
+// effectSetting returns the saved effect for the key, or its first option
+func effectSetting(key string, options []string) string {
+	saved := fyne.CurrentApp().Preferences().StringWithFallback(profileKey(key), options[0])
+	for _, option := range options {
+		if option == saved {
+			return saved
+		}
+	}
+	return options[0]
+}
+
+// reducedMotion reports whether animations are off, by the player's choice
+// or in low power mode
+func reducedMotion() bool {
+	return fyne.CurrentApp().Preferences().Bool(profileKey(reducedMotionKey)) || lowPower()
+}
+
+// playGameOverEffect runs the configured win or lose effect over the board
+func (gw *GameWindow) playGameOverEffect(won bool) {
+	if reducedMotion() {
+		return
+	}
+
+	effect := effectSetting(loseEffectKey, loseEffects)
+	if won {
+		effect = effectSetting(winEffectKey, winEffects)
+	}
+	switch effect {
+	case effectConfetti:
+		gw.playConfetti()
+	case effectShimmer:
+		gw.playShimmer()
+	case effectShake:
+		gw.playShake()
+	}
+}
+
+// playConfetti drops colored pieces from the top of the board
+func (gw *GameWindow) playConfetti() {
+	for i := 0; i < confettiPieces; i++ {
+		piece := canvas.NewRectangle(confettiColors[rand.Intn(len(confettiColors))])
+		piece.Resize(fyne.NewSize(6, 10))
+
+		start := fyne.NewPos(rand.Float32()*gw.geom.total(), -rand.Float32()*gw.geom.total()/2)
+		drift := (rand.Float32() - 0.5) * gw.geom.cell * 3
+		stop := fyne.NewPos(start.X+drift, gw.geom.total()+20)
+		piece.Move(start)
+		gw.boardContainer.Add(piece)
+
+		duration := time.Duration(1500+rand.Intn(1500)) * time.Millisecond
+		fall := canvas.NewPositionAnimation(start, stop, duration, piece.Move)
+		fall.Curve = fyne.AnimationEaseIn
+		fall.Start()
+		time.AfterFunc(duration+100*time.Millisecond, func() {
+			gw.boardContainer.Remove(piece)
+		})
+	}
+}
+
+// playShimmer pulses a glow around the winning stones
+func (gw *GameWindow) playShimmer() {
+	line := gw.board.WinningLine()
+	if len(line) == 0 {
+		return
+	}
+
+	glow := color.RGBA{R: 255, G: 215, B: 0, A: 255}
+	rings := make([]*canvas.Circle, 0, len(line))
+	for _, pos := range line {
+		ring := canvas.NewCircle(color.Transparent)
+		ring.StrokeWidth = 3
+		ring.Resize(fyne.NewSize(gw.geom.stone+6, gw.geom.stone+6))
+		ring.Move(fyne.NewPos(
+			gw.geom.coord(pos[1])-gw.geom.stone/2-3,
+			gw.geom.coord(pos[0])-gw.geom.stone/2-3,
+		))
+		rings = append(rings, ring)
+		gw.boardContainer.Add(ring)
+	}
+
+	shimmer := fyne.NewAnimation(600*time.Millisecond, func(progress float32) {
+		glow.A = uint8(255 * progress)
+		for _, ring := range rings {
+			ring.StrokeColor = glow
+			ring.Refresh()
+		}
+	})
+	shimmer.AutoReverse = true
+	shimmer.RepeatCount = 2
+	shimmer.Start()
+
+	time.AfterFunc(3700*time.Millisecond, func() {
+		for _, ring := range rings {
+			gw.boardContainer.Remove(ring)
+		}
+	})
+}
+
+// playShake briefly shakes the board from side to side
+func (gw *GameWindow) playShake() {
+	origin := gw.boardContainer.Position()
+	shake := fyne.NewAnimation(500*time.Millisecond, func(progress float32) {
+		offset := float32(math.Sin(float64(progress)*6*math.Pi)) * 6 * (1 - progress)
+		gw.boardContainer.Move(fyne.NewPos(origin.X+offset, origin.Y))
+	})
+	shake.Curve = fyne.AnimationLinear
+	shake.Start()
+}
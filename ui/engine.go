@@ -0,0 +1,140 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"simple-gomoku/game"
+	"simple-gomoku/pbrain"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+)
+
+const analysisEngineKey = "engine.analysis" // Path of the external engine used for hints
+
+// analysisEngine returns the external engine that answers hints, starting it
+// if needed, or nil if none is set
+func (gw *GameWindow) analysisEngine() (*pbrain.Engine, error) {
+	path := fyne.CurrentApp().Preferences().String(profileKey(analysisEngineKey))
+	if gw.analysis != nil && gw.analysisPath == path {
+		return gw.analysis, nil
+	}
+	gw.closeAnalysisEngine()
+	if path == "" {
+		return nil, nil
+	}
+	engine, err := pbrain.StartEngine(path, pbrain.DefaultTurnTime)
+	if err != nil {
+		return nil, err
+	}
+	gw.analysis, gw.analysisPath = engine, path
+	return engine, nil
+}
+
+func (gw *GameWindow) closeAnalysisEngine() {
+	if gw.analysis != nil {
+		gw.analysis.Close()
+		gw.analysis, gw.analysisPath = nil, ""
+	}
+}
+
+// externalHint asks the analysis engine for a move for the side to move,
+// giving up when ctx is cancelled. ok is false when no engine is set or it
+// failed, and the built-in engine should answer instead.
+func (gw *GameWindow) externalHint(ctx context.Context, board *game.Board) (row, col int, name string, ok bool) {
+	if !board.Rules().Standard() || len(board.Obstacles()) > 0 {
+		return 0, 0, "", false // External engines only know five in a row on an open board
+	}
+	engine, err := gw.analysisEngine()
+	if err == nil && engine != nil {
+		turnCtx, cancel := context.WithTimeout(ctx, pbrain.DefaultTurnTime*2)
+		defer cancel()
+		if row, col, err = engine.Move(turnCtx, board); err == nil {
+			return row, col, engine.Name, true
+		}
+	}
+	if err != nil && ctx.Err() == nil { // A cancelled hint is no failure
+		fyne.LogError("Analysis engine failed", err)
+		gw.showToast("Analysis engine failed, using the built-in hint")
+		if !errors.Is(err, context.DeadlineExceeded) {
+			gw.closeAnalysisEngine()
+		}
+	}
+	return -1, -1, "", false
+}
+
+// analysisEngineName describes the hint engine for the settings dialog
+func analysisEngineName() string {
+	path := fyne.CurrentApp().Preferences().String(profileKey(analysisEngineKey))
+	if path == "" {
+		return "Built-in"
+	}
+	return filepath.Base(path)
+}
+
+const (
+	opponentEngineKey  = "engine.opponent" // Path of the last custom engine played against
+	customEngineOption = "Custom Engine..."
+)
+
+// opponent plays White's moves against the human player
+type opponent interface {
+	MakeMoveCtx(ctx context.Context, board *game.Board) (int, int, error)
+}
+
+// opponent returns the external engine when one plays White, else the AI
+func (gw *GameWindow) opponent() opponent {
+	if gw.engine != nil {
+		return gw.engine
+	}
+	gw.ai.SetWorkers(searchWorkers())
+	gw.ai.SetVariety(gw.openingVariety())
+	return gw.ai
+}
+
+// startCustomEngine plays the next games against the engine at path
+func (gw *GameWindow) startCustomEngine(path string) error {
+	player, err := pbrain.NewEnginePlayer(path, pbrain.DefaultTurnTime)
+	if err != nil {
+		return err
+	}
+	gw.closeEngine()
+	gw.engine = player
+	fyne.CurrentApp().Preferences().SetString(profileKey(opponentEngineKey), path)
+	return nil
+}
+
+func (gw *GameWindow) closeEngine() {
+	if gw.engine != nil {
+		gw.engine.Close()
+		gw.engine = nil
+	}
+}
+
+// chooseCustomEngine asks for an engine executable and calls started once it
+// is running
+func (gw *GameWindow) chooseCustomEngine(started func()) {
+	open := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil || reader == nil {
+			return
+		}
+		reader.Close()
+		if err := gw.startCustomEngine(reader.URI().Path()); err != nil {
+			dialog.ShowError(fmt.Errorf("Could not start the engine: %w", err), gw.window)
+			return
+		}
+		started()
+	}, gw.window)
+	open.Show()
+}
+
+// customEngineText describes the engine playing White in the new game dialog
+func (gw *GameWindow) customEngineText() string {
+	if gw.engine == nil {
+		return "No engine chosen, Hard plays instead"
+	}
+	return "Engine: " + gw.engine.Name()
+}
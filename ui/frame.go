@@ -0,0 +1,57 @@
+package ui
+
+import (
+	"image/color"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+)
+
+const frameInterval = time.Second / 60 // Time between batched refreshes
+
+// frameBatch collects the board objects changed since the last frame and
+// refreshes each once when the frame is due, so a burst of updates, such as
+// stepping quickly through a game, costs one refresh per changed stone per
+// frame rather than one per stone per update
+type frameBatch struct {
+	mu      sync.Mutex
+	pending map[fyne.CanvasObject]bool
+	queued  bool // A flush is scheduled
+}
+
+var frame frameBatch
+
+// refresh queues obj to be refreshed with the next frame
+func (f *frameBatch) refresh(obj fyne.CanvasObject) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.pending == nil {
+		f.pending = make(map[fyne.CanvasObject]bool)
+	}
+	f.pending[obj] = true
+	if !f.queued {
+		f.queued = true
+		time.AfterFunc(frameInterval, f.flush)
+	}
+}
+
+func (f *frameBatch) flush() {
+	f.mu.Lock()
+	pending := f.pending
+	f.pending, f.queued = nil, false
+	f.mu.Unlock()
+	for obj := range pending {
+		obj.Refresh()
+	}
+}
+
+// paint gives stone the color c, queueing a refresh only when it changes
+func (f *frameBatch) paint(stone *canvas.Circle, c color.Color) {
+	if stone.FillColor == c {
+		return
+	}
+	stone.FillColor = c
+	f.refresh(stone)
+}
@@ -0,0 +1,105 @@
+package ui
+
+import (
+	"fmt"
+
+	"simple-gomoku/game"
+
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	gauntletTokens     = 5  // Hint and undo tokens shared by all games of a gauntlet
+	gauntletTokenBonus = 50 // Score bonus per token left at the end
+)
+
+// gauntlet tracks a run of consecutive games against the ladder levels
+type gauntlet struct {
+	stage  int // Index into game.Ladder of the current opponent
+	tokens int
+	score  int
+}
+
+func (gw *GameWindow) startGauntlet() {
+	gw.gauntlet = &gauntlet{tokens: gauntletTokens}
+	gw.startGauntletGame()
+}
+
+func (gw *GameWindow) startGauntletGame() {
+	level := game.Ladder[gw.gauntlet.stage]
+	gw.stopAI()
+	gw.adaptive = false
+	gw.hotSeat = false
+	gw.drill = nil
+	gw.closeEngine()
+	gw.ladderLevel = -1
+	gw.ai = level.NewAI(game.White)
+	gw.board = game.NewBoard()
+	gw.updateBoard()
+	gw.updateStatus()
+	gw.logEvent("New gauntlet game against %s", level.Name)
+}
+
+// useGauntletToken spends a token, reporting false if none are left.
+// Outside gauntlet mode it always succeeds.
+func (gw *GameWindow) useGauntletToken() bool {
+	if gw.gauntlet == nil {
+		return true
+	}
+	if gw.gauntlet.tokens == 0 {
+		gw.showToast("No hint or undo tokens left")
+		return false
+	}
+	gw.gauntlet.tokens--
+	gw.updateStatus()
+	return true
+}
+
+func (gw *GameWindow) showGauntletResult(won bool) {
+	g := gw.gauntlet
+	level := game.Ladder[g.stage]
+
+	if won {
+		g.score += level.Elo / 10 * (g.stage + 1)
+		g.stage++
+		if g.stage < len(game.Ladder) {
+			message := fmt.Sprintf("You beat %s!\nNext opponent: %s (%d)",
+				level.Name, game.Ladder[g.stage].Name, game.Ladder[g.stage].Elo)
+			dialog.ShowCustomConfirm("Gauntlet", "Next Game", "Give Up", widget.NewLabel(message),
+				func(ok bool) {
+					if ok {
+						gw.startGauntletGame()
+					} else {
+						gw.showGauntletScore()
+					}
+				}, gw.window)
+			return
+		}
+	}
+	gw.showGauntletScore()
+}
+
+func (gw *GameWindow) showGauntletScore() {
+	g := gw.gauntlet
+	gw.gauntlet = nil
+
+	bonus := 0
+	if g.stage > 0 {
+		bonus = g.tokens * gauntletTokenBonus
+	}
+	content := container.NewVBox(
+		widget.NewLabel(fmt.Sprintf("Opponents beaten: %d of %d", g.stage, len(game.Ladder))),
+		widget.NewLabel(fmt.Sprintf("Tokens left: %d (+%d)", g.tokens, bonus)),
+		widget.NewLabel(fmt.Sprintf("Final score: %d", g.score+bonus)),
+	)
+	dialog.ShowCustomConfirm("Gauntlet Over", "New Game", "Return to Board", content,
+		func(ok bool) {
+			if ok {
+				gw.stopAI()
+				gw.board = game.NewBoard()
+				gw.showDifficultyDialog()
+			}
+		}, gw.window)
+}
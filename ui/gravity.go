@@ -0,0 +1,37 @@
+package ui
+
+import (
+	"image/color"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+)
+
+var columnColor = color.NRGBA{R: 0, G: 90, B: 200, A: 40}
+
+// newColumnHighlight creates the band that marks the column under the
+// pointer in gravity games, hidden until the pointer is over the board
+func (gw *GameWindow) newColumnHighlight() fyne.CanvasObject {
+	gw.columnMark = canvas.NewRectangle(columnColor)
+	gw.columnMark.CornerRadius = gw.geom.cell / 4
+	gw.columnMark.Resize(fyne.NewSize(gw.geom.cell, gw.geom.span()+gw.geom.cell))
+	gw.columnMark.Hide()
+	return gw.columnMark
+}
+
+// hoverColumn highlights col while the pointer is over it in a gravity game
+// the player can move in, since a stone goes down whichever column is
+// clicked rather than to the point
+func (gw *GameWindow) hoverColumn(col int, in bool) {
+	playable := gw.board.Rules().Gravity && !gw.isProcessing && gw.setup == nil && !gw.board.IsGameFinished() &&
+		gw.board.DropRow(col) >= 0
+	if !in || !playable {
+		if gw.columnMark.Visible() {
+			gw.columnMark.Hide()
+		}
+		return
+	}
+	gw.columnMark.Move(fyne.NewPos(gw.geom.coord(col)-gw.geom.cell/2, gw.geom.padding-gw.geom.cell/2))
+	gw.columnMark.Show()
+	frame.refresh(gw.columnMark)
+}
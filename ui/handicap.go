@@ -0,0 +1,165 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+
+	"simple-gomoku/game"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+const handicapKey = "game.handicap" // Handicap stones Black starts new games with
+
+// handicapOptions lists the handicap choices of the new game dialog, indexed
+// by stone count
+func handicapOptions() []string {
+	options := []string{"None", "1 stone"}
+	for n := 2; n <= game.MaxHandicap; n++ {
+		options = append(options, fmt.Sprintf("%d stones", n))
+	}
+	return options
+}
+
+// handicapSetup is the handicap being placed before a game starts
+type handicapSetup struct {
+	board  *game.Board // Board the stones go on
+	count  int         // Stones to place
+	points [][2]int    // Stones placed so far, in order
+}
+
+// grid returns the stones placed so far
+func (h *handicapSetup) grid() *[game.BoardSize][game.BoardSize]game.Player {
+	var grid [game.BoardSize][game.BoardSize]game.Player
+	for _, point := range h.points {
+		grid[point[0]][point[1]] = game.Black
+	}
+	return &grid
+}
+
+// status describes the handicap being placed
+func (h *handicapSetup) status() string {
+	return fmt.Sprintf("Handicap: %d of %d black stones placed", len(h.points), h.count)
+}
+
+// placingHandicap returns the handicap being placed on the current board,
+// nil when the game is under way
+func (gw *GameWindow) placingHandicap() *handicapSetup {
+	if gw.handicap == nil || gw.handicap.board != gw.board {
+		return nil
+	}
+	return gw.handicap
+}
+
+// handicapAllowed reports whether the new game may start with handicap
+// stones. The engines that need five in a row from the empty board, the
+// opening rules that place the first stones, gravity, three-player games and
+// blocked points leave no room for them.
+func (gw *GameWindow) handicapAllowed() bool {
+	rules := gw.board.Rules()
+	return !gw.adaptive && gw.engine == nil && gw.ladderLevel < 0 && gw.gauntlet == nil && gw.drill == nil &&
+		rules.Opening == game.FreeOpening && !rules.Gravity && !rules.ThreePlayers && len(gw.board.MoveHistory) == 0 &&
+		len(gw.board.Obstacles()) == 0
+}
+
+// startHandicap lets Black's handicap stones be placed before the new game
+// starts, beginning with the usual points
+func (gw *GameWindow) startHandicap(count int) {
+	if count <= 0 {
+		return
+	}
+	if !gw.handicapAllowed() {
+		gw.showToast("Handicap stones need a two-player game with a free opening, no gravity and no blocked points, against a built-in engine")
+		return
+	}
+	if gw.miniMode {
+		gw.toggleMiniMode() // The tools need the full layout
+	}
+	gw.handicap = &handicapSetup{board: gw.board, count: count, points: game.HandicapPoints(count)}
+	gw.updateBoard()
+	gw.updateStatus()
+	gw.logEvent("Placing %d handicap stones", count)
+}
+
+// handicapClick adds or removes a handicap stone
+func (gw *GameWindow) handicapClick(row, col int) {
+	h := gw.placingHandicap()
+	point := [2]int{row, col}
+	if i := slices.Index(h.points, point); i >= 0 {
+		h.points = slices.Delete(h.points, i, i+1)
+	} else if len(h.points) < h.count {
+		h.points = append(h.points, point)
+	} else {
+		gw.showToast("All %d handicap stones are placed, click one to move it", h.count)
+		return
+	}
+	gw.drawGrid(h.grid(), displayNormal)
+	gw.updateStatus()
+}
+
+// playHandicap puts the handicap stones on the board and starts the game,
+// with White, the AI outside two-player games, moving first
+func (gw *GameWindow) playHandicap() {
+	h := gw.placingHandicap()
+	if h == nil {
+		return
+	}
+	if len(h.points) < h.count {
+		gw.showToast("Place %d more handicap stones", h.count-len(h.points))
+		return
+	}
+	if err := gw.board.SetupHandicap(h.points); err != nil {
+		dialog.ShowError(err, gw.window)
+		return
+	}
+	gw.handicap = nil
+	gw.updateBoard()
+	gw.updateStatus()
+	gw.logEvent("Playing with %d handicap stones", len(h.points))
+	if !gw.hotSeat {
+		gw.isProcessing = true
+		gw.playAITurn()
+	}
+}
+
+// skipHandicap starts the game without handicap stones
+func (gw *GameWindow) skipHandicap() {
+	gw.handicap = nil
+	gw.updateBoard()
+	gw.updateStatus()
+}
+
+// newHandicapBar creates the handicap tools, hidden unless handicap stones
+// are being placed
+func (gw *GameWindow) newHandicapBar() fyne.CanvasObject {
+	defaultButton := widget.NewButton("Default Points", func() {
+		if h := gw.placingHandicap(); h != nil {
+			h.points = game.HandicapPoints(h.count)
+			gw.updateBoard()
+			gw.updateStatus()
+		}
+	})
+	gw.handicapBar = container.NewHBox(
+		widget.NewLabel("Click points to move the handicap stones:"),
+		defaultButton,
+		widget.NewButton("Start", gw.playHandicap),
+		widget.NewButton("No Handicap", gw.skipHandicap),
+	)
+	gw.refreshHandicapBar()
+	return gw.handicapBar
+}
+
+// refreshHandicapBar shows the handicap tools while stones are being placed
+func (gw *GameWindow) refreshHandicapBar() {
+	if gw.handicapBar == nil {
+		return // Not built yet
+	}
+	if gw.placingHandicap() != nil {
+		gw.handicapBar.Show()
+	} else {
+		gw.handicapBar.Hide()
+	}
+}
@@ -0,0 +1,101 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"image/color"
+
+	"simple-gomoku/game"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+)
+
+// showHint asks the AI for a move for the human player and marks it on the
+// board. The search is cancelled, through cancelAI, if the game is replaced.
+func (gw *GameWindow) showHint() {
+	if gw.isProcessing || gw.setup != nil || gw.board.IsGameFinished() || (gw.board.GetCurrentPlayer() != game.Black && !gw.hotSeat) {
+		return
+	}
+	if clubForbids(clubNoHintsKey) {
+		gw.showToast("Hints are off under the %s club settings", clubName())
+		return
+	}
+	if gw.locked("hints") {
+		return
+	}
+	if !gw.useGauntletToken() {
+		return
+	}
+	gw.isProcessing = true
+	ctx, cancel := context.WithCancel(context.Background())
+	gw.cancelAI = cancel
+
+	board, ai := gw.board, gw.ai
+	ai.SetWorkers(searchWorkers())
+	position := board.Clone()
+	go func() {
+		defer cancel()
+		if row, col, name, ok := gw.externalHint(ctx, position); ok {
+			if ctx.Err() != nil || gw.board != board {
+				return // The game was replaced while the engine was thinking
+			}
+			move := game.FormatMove(row, col)
+			gw.markHint(row, col)
+			gw.logEvent("Hint used: %s (%s)", move, name)
+			gw.showToast("Hint ready: %s", move)
+			gw.statusLabel.SetText(fmt.Sprintf("Hint: %s (from %s)", move, name))
+			gw.isProcessing = false
+			return
+		}
+
+		suggestion, err := ai.SuggestMoveCtx(ctx, position)
+		if err != nil || gw.board != board {
+			return
+		}
+		difficulty := ai.RateDifficulty(position)
+		if suggestion.Row >= 0 {
+			move := game.FormatMove(suggestion.Row, suggestion.Col)
+			gw.markHint(suggestion.Row, suggestion.Col)
+			gw.logEvent("Hint used: %s", move)
+			gw.showToast("Hint ready: %s", move)
+
+			// Say more about the position the harder it is
+			status := fmt.Sprintf("Hint: %s (score %d)", move, suggestion.Score)
+			switch {
+			case difficulty.Forced:
+				status = fmt.Sprintf("Hint: %s, you must play here", move)
+			case difficulty.Label() == "hard":
+				status += fmt.Sprintf(" - tricky position, %d moves are close and %d threats are on the board",
+					difficulty.GoodMoves, difficulty.Threats)
+			case difficulty.Label() == "moderate":
+				status += fmt.Sprintf(" - %d moves are close", difficulty.GoodMoves)
+			}
+			gw.statusLabel.SetText(status)
+		}
+		gw.isProcessing = false
+	}()
+}
+
+func (gw *GameWindow) markHint(row, col int) {
+	gw.clearHint()
+
+	marker := canvas.NewCircle(color.Transparent)
+	marker.StrokeColor = color.RGBA{R: 0, G: 160, B: 0, A: 255}
+	marker.StrokeWidth = 3
+	marker.Resize(fyne.NewSize(gw.geom.stone, gw.geom.stone))
+	marker.Move(fyne.NewPos(
+		gw.geom.coord(col)-gw.geom.stone/2,
+		gw.geom.coord(row)-gw.geom.stone/2,
+	))
+
+	gw.hintMarker = marker
+	gw.boardContainer.Add(marker)
+}
+
+func (gw *GameWindow) clearHint() {
+	if gw.hintMarker != nil {
+		gw.boardContainer.Remove(gw.hintMarker)
+		gw.hintMarker = nil
+	}
+}
@@ -0,0 +1,91 @@
+package ui
+
+import (
+	"errors"
+	"image/color"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+const minKioskPIN = 4 // Digits a tournament mode PIN needs at least
+
+// kiosk is tournament mode, for running the app as a playing and recording
+// station at events. Hints, engine assist, undo, engine comments, training
+// and settings are off, the bug reporter that opens the browser and custom
+// engines are hidden, the profile stays the one the event started with, and
+// leaving the mode or quitting takes the PIN.
+type kiosk struct {
+	pin string
+}
+
+// newKiosk returns tournament mode left by entering pin, which must be four
+// or more digits
+func newKiosk(pin string) (*kiosk, error) {
+	if len(pin) < minKioskPIN || strings.Trim(pin, "0123456789") != "" {
+		return nil, errors.New("the tournament mode PIN must be at least 4 digits")
+	}
+	return &kiosk{pin: pin}, nil
+}
+
+// lockKiosk makes quitting take the PIN, once the window is built in
+// tournament mode
+func (gw *GameWindow) lockKiosk() {
+	gw.window.SetCloseIntercept(func() {
+		gw.askKioskPIN("Quit", gw.window.Close)
+	})
+	gw.logEvent("Tournament mode on")
+}
+
+// exitKiosk asks for the PIN and leaves tournament mode
+func (gw *GameWindow) exitKiosk() {
+	gw.askKioskPIN("Leave Tournament Mode", func() {
+		gw.kiosk = nil
+		gw.rebuildUI()
+		gw.window.SetMainMenu(gw.mainMenu())
+		gw.window.SetCloseIntercept(nil)
+		gw.logEvent("Tournament mode off")
+	})
+}
+
+// askKioskPIN runs action once the tournament mode PIN is entered
+func (gw *GameWindow) askKioskPIN(title string, action func()) {
+	pinEntry := widget.NewPasswordEntry()
+	items := []*widget.FormItem{widget.NewFormItem("PIN", pinEntry)}
+	form := dialog.NewForm(title, "OK", "Cancel", items, func(ok bool) {
+		if !ok {
+			return
+		}
+		if pinEntry.Text != gw.kiosk.pin {
+			gw.logEvent("Wrong tournament mode PIN entered")
+			gw.showToast("Wrong PIN")
+			return
+		}
+		action()
+	}, gw.window)
+	form.Show()
+	gw.window.Canvas().Focus(pinEntry)
+}
+
+// locked reports whether feature, named in lower case, is off in tournament
+// mode, telling the player so
+func (gw *GameWindow) locked(feature string) bool {
+	if gw.kiosk == nil {
+		return false
+	}
+	gw.showToast("No %s in tournament mode", feature)
+	return true
+}
+
+// kioskBadge returns the tournament mode badge, nil outside the mode
+func (gw *GameWindow) kioskBadge() fyne.CanvasObject {
+	if gw.kiosk == nil {
+		return nil
+	}
+	badge := canvas.NewText("TOURNAMENT MODE", color.RGBA{R: 200, G: 30, B: 30, A: 255})
+	badge.TextStyle = fyne.TextStyle{Bold: true}
+	return badge
+}
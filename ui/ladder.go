@@ -0,0 +1,72 @@
+package ui
+
+import (
+	"fmt"
+
+	"simple-gomoku/game"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+const ladderUnlockedKey = "ladder.unlocked"
+
+// ladderUnlocked returns the index of the highest unlocked ladder level
+func ladderUnlocked() int {
+	unlocked := fyne.CurrentApp().Preferences().Int(profileKey(ladderUnlockedKey))
+	return min(unlocked, len(game.Ladder)-1)
+}
+
+func (gw *GameWindow) showLadderDialog() {
+	unlocked := ladderUnlocked()
+	levels := container.NewVBox(widget.NewLabel("Beat a level to unlock the next one:"))
+
+	var ladderDialog dialog.Dialog
+	for i, level := range game.Ladder {
+		button := widget.NewButton(fmt.Sprintf("%s (%d)", level.Name, level.Elo), func() {
+			ladderDialog.Hide()
+			gw.startLadderGame(i)
+		})
+		if i > unlocked {
+			button.Disable()
+		}
+		levels.Add(button)
+	}
+
+	ladderDialog = dialog.NewCustom("Ladder", "Cancel", levels, gw.window)
+	ladderDialog.Show()
+}
+
+func (gw *GameWindow) startLadderGame(level int) {
+	gw.ladderLevel = level
+	gw.gauntlet = nil
+	gw.adaptive = false
+	gw.hotSeat = false
+	gw.drill = nil
+	gw.closeEngine()
+	gw.stopAI()
+	gw.ai = game.Ladder[level].NewAI(game.White)
+	gw.board = game.NewBoard()
+	gw.updateBoard()
+	gw.updateStatus()
+	gw.logEvent("New ladder game against %s", game.Ladder[level].Name)
+}
+
+// recordLadderResult unlocks the next level after a ladder win and returns
+// a message describing the progress, if any
+func (gw *GameWindow) recordLadderResult(won bool) string {
+	if gw.ladderLevel < 0 || !won {
+		return ""
+	}
+	next := gw.ladderLevel + 1
+	if next >= len(game.Ladder) {
+		return "You have beaten the whole ladder!"
+	}
+	if next <= ladderUnlocked() {
+		return ""
+	}
+	fyne.CurrentApp().Preferences().SetInt(profileKey(ladderUnlockedKey), next)
+	return fmt.Sprintf("New ladder level unlocked: %s", game.Ladder[next].Name)
+}
@@ -0,0 +1,125 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+)
+
+const prefsVersionKey = "prefs.version"
+
+// prefsMigrations upgrade the saved preferences at startup; prefsMigrations[i]
+// upgrades version i to i+1. Append to the list to change the format of a
+// setting, never edit a step that has shipped.
+var prefsMigrations = []func(prefs fyne.Preferences){
+	// 0 to 1: the adaptive records and review schedules carry a version
+	func(prefs fyne.Preferences) {
+		if prefs.String(adaptiveRecordsKey) != "" {
+			saveRecordStore(loadRecordStore())
+		}
+		if prefs.String(reviewScheduleKey+adaptivePlayer) != "" {
+			saveSchedule(loadSchedule())
+		}
+	},
+}
+
+// Preference keys by type, for backups
+var (
+	stringPrefKeys = func() []string {
+		keys := []string{
+			adaptiveRecordsKey, reviewScheduleKey + adaptivePlayer, reviewPromptedKey,
+			backgroundKeyPrefix + "light", backgroundKeyPrefix + "dark",
+			winEffectKey, loseEffectKey, profilesKey, activeProfileKey, analysisEngineKey, opponentEngineKey,
+			clubNameKey, powerModeKey,
+		}
+		for _, action := range shortcutActions {
+			keys = append(keys, shortcutKeyPrefix+action.id)
+		}
+		return keys
+	}()
+	intPrefKeys  = []string{prefsVersionKey, ladderUnlockedKey, winLengthKey, openingKey, handicapKey, varietyKey, obstaclesKey}
+	boolPrefKeys = []string{raiseOnTurnKey, reducedMotionKey, bookLearningKey, commentaryOffKey, screenshotClipboardKey, clubNoUndoKey, clubNoHintsKey, exactKey, gravityKey, threePlayersKey}
+)
+
+// migratePreferences brings the saved preferences up to the current version,
+// backing them up first. Preferences from a newer release are left alone.
+func migratePreferences() {
+	prefs := fyne.CurrentApp().Preferences()
+	version := prefs.Int(prefsVersionKey)
+	if version >= len(prefsMigrations) {
+		return
+	}
+	if err := backupPreferences(fmt.Sprintf("v%d", version)); err != nil {
+		fyne.LogError("Not migrating preferences, backup failed", err)
+		return
+	}
+	for ; version < len(prefsMigrations); version++ {
+		prefsMigrations[version](prefs)
+		prefs.SetInt(prefsVersionKey, version+1)
+	}
+}
+
+// backupPreferences writes every saved preference of every profile to a JSON
+// file in the app's storage. Nothing is written when no preference has been
+// saved yet.
+func backupPreferences(label string) error {
+	values := preferenceValues()
+	if len(values) == 0 {
+		return nil
+	}
+	data, err := json.MarshalIndent(values, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeBackup("preferences-"+label, data)
+}
+
+// preferenceValues returns every saved preference of every profile by key
+func preferenceValues() map[string]any {
+	prefs := fyne.CurrentApp().Preferences()
+	values := make(map[string]any)
+	for _, p := range loadProfiles() {
+		for _, key := range stringPrefKeys {
+			if value := prefs.String(p.key(key)); value != "" {
+				values[p.key(key)] = value
+			}
+		}
+		for _, key := range intPrefKeys {
+			if value := prefs.Int(p.key(key)); value != 0 {
+				values[p.key(key)] = value
+			}
+		}
+		for _, key := range boolPrefKeys {
+			if prefs.Bool(p.key(key)) {
+				values[p.key(key)] = true
+			}
+		}
+	}
+	return values
+}
+
+// backupUnreadable keeps a saved value that could not be read, before it is
+// replaced, so data in a newer or damaged format is not lost
+func backupUnreadable(key, value string, err error) {
+	fyne.LogError("Could not read "+key, err)
+	if err := writeBackup(strings.TrimSuffix(key, "."), []byte(value)); err != nil {
+		fyne.LogError("Could not back up "+key, err)
+	}
+}
+
+// writeBackup writes data to a timestamped file in the app's storage
+func writeBackup(name string, data []byte) error {
+	file := fmt.Sprintf("backup-%s-%s.json", name, time.Now().Format("20060102-150405"))
+	writer, err := fyne.CurrentApp().Storage().Create(file)
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write(data); err != nil {
+		writer.Close()
+		return err
+	}
+	return writer.Close()
+}
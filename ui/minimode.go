@@ -0,0 +1,35 @@
+package ui
+
+// toggleMiniMode switches between the full layout and a compact layout with
+// just the board and a small control strip, restoring the previous window
+// size when switching back
+func (gw *GameWindow) toggleMiniMode() {
+	if gw.miniMode {
+		gw.miniMode = false
+		gw.geom = fullGeometry
+	} else {
+		gw.fullSize = gw.window.Canvas().Size()
+		gw.miniMode = true
+		gw.geom = miniGeometry
+	}
+
+	gw.rebuildUI()
+	if !gw.miniMode {
+		gw.window.Resize(gw.fullSize)
+	}
+}
+
+// rebuildUI builds the window content from scratch, for a new board size or
+// layout
+func (gw *GameWindow) rebuildUI() {
+	gw.lastMoveMarker = nil
+	gw.hintMarker = nil
+	gw.assistMarks = nil
+	gw.gridLines = nil
+	gw.toasts.mu.Lock()
+	gw.toasts.items = nil
+	gw.toasts.mu.Unlock()
+	gw.initializeUI()
+	gw.updateBoard()
+	gw.updateStatus()
+}
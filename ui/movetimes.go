@@ -0,0 +1,172 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"sync"
+	"time"
+
+	"simple-gomoku/game"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	chartHeight   = 160
+	chartBarWidth = 14
+	chartBarGap   = 4
+)
+
+// moveClock times every move of the current game. times[i] is the time
+// spent on board.MoveHistory[i].
+type moveClock struct {
+	mu        sync.Mutex
+	board     *game.Board // Game the times belong to
+	times     []time.Duration
+	turnStart time.Time
+	list      *widget.List // Move list panel, nil until created
+}
+
+// sync drops times that no longer match the board, after undos or when a new
+// game has started. The caller must hold the lock.
+func (c *moveClock) sync(board *game.Board) {
+	if c.board != board {
+		c.board = board
+		c.times = nil
+		c.turnStart = time.Now()
+	}
+	if len(c.times) > len(board.MoveHistory) {
+		c.times = c.times[:len(board.MoveHistory)]
+		c.turnStart = time.Now()
+	}
+}
+
+// snapshot returns the moves and their times for the current game
+func (gw *GameWindow) moveTimes() ([]game.Move, []time.Duration) {
+	gw.clock.mu.Lock()
+	defer gw.clock.mu.Unlock()
+	gw.clock.sync(gw.board)
+	moves := append([]game.Move(nil), gw.board.MoveHistory[:len(gw.clock.times)]...)
+	return moves, append([]time.Duration(nil), gw.clock.times...)
+}
+
+// recordHumanMove records the time since the turn started for the move just
+// played on the board
+func (gw *GameWindow) recordHumanMove() {
+	gw.clock.mu.Lock()
+	gw.clock.sync(gw.board)
+	spent := time.Since(gw.clock.turnStart)
+	gw.clock.mu.Unlock()
+	gw.recordMoveTime(gw.board, spent)
+}
+
+// recordMoveTime records the time spent on the last move of board and starts
+// timing the next turn
+func (gw *GameWindow) recordMoveTime(board *game.Board, spent time.Duration) {
+	gw.clock.mu.Lock()
+	gw.clock.sync(board)
+	for len(gw.clock.times) < len(board.MoveHistory)-1 {
+		gw.clock.times = append(gw.clock.times, 0) // Moves made before timing started
+	}
+	gw.clock.times = append(gw.clock.times, spent)
+	gw.clock.turnStart = time.Now()
+	gw.clock.mu.Unlock()
+}
+
+// refreshMoveList redraws the move list after the board changed
+func (gw *GameWindow) refreshMoveList() {
+	gw.clock.mu.Lock()
+	gw.clock.sync(gw.board)
+	gw.clock.mu.Unlock()
+	if gw.clock.list != nil {
+		gw.clock.list.Refresh()
+		gw.clock.list.ScrollToBottom()
+	}
+}
+
+func formatSeconds(d time.Duration) string {
+	return fmt.Sprintf("%.1fs", d.Seconds())
+}
+
+// moveListItem creates the collapsible move list with the time of each move
+func (gw *GameWindow) moveListItem() *widget.AccordionItem {
+	gw.clock.list = widget.NewList(
+		func() int {
+			moves, _ := gw.moveTimes()
+			return len(moves)
+		},
+		func() fyne.CanvasObject {
+			return widget.NewLabel("")
+		},
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			moves, times := gw.moveTimes()
+			if id >= len(moves) {
+				return
+			}
+			move := moves[id]
+			item.(*widget.Label).SetText(fmt.Sprintf("%d. %s %s  %s",
+				move.Number, gw.getPlayerText(move.Player), move, formatSeconds(times[id])))
+		},
+	)
+	chartButton := widget.NewButton("Time Chart...", gw.showTimeChart)
+
+	sized := container.New(layout.NewGridWrapLayout(fyne.NewSize(gw.geom.total(), sessionLogHeight)), gw.clock.list)
+	content := container.NewBorder(nil, container.NewHBox(layout.NewSpacer(), chartButton), nil, nil, sized)
+	return widget.NewAccordionItem("Moves", content)
+}
+
+// showTimeChart charts the time spent on every move of the game, with the
+// totals for each player
+func (gw *GameWindow) showTimeChart() {
+	moves, times := gw.moveTimes()
+	if len(times) == 0 {
+		gw.showToast("No moves yet")
+		return
+	}
+
+	longest := time.Duration(1)
+	var totals [4]time.Duration
+	var counts [4]int
+	for i, spent := range times {
+		longest = max(longest, spent)
+		totals[moves[i].Player] += spent
+		counts[moves[i].Player]++
+	}
+
+	width := float32(len(times)*(chartBarWidth+chartBarGap) + chartBarGap)
+	chart := container.NewWithoutLayout()
+	background := canvas.NewRectangle(woodColor)
+	background.Resize(fyne.NewSize(width, chartHeight))
+	chart.Add(background)
+	for i, spent := range times {
+		height := max(1, float32(chartHeight-10)*float32(spent)/float32(longest))
+		bar := canvas.NewRectangle(color.Black)
+		switch moves[i].Player {
+		case game.White:
+			bar.FillColor = color.White
+			bar.StrokeColor = color.Black
+			bar.StrokeWidth = 1
+		case game.Red:
+			bar.FillColor = redStone
+		}
+		bar.Resize(fyne.NewSize(chartBarWidth, height))
+		bar.Move(fyne.NewPos(float32(chartBarGap+i*(chartBarWidth+chartBarGap)), chartHeight-height))
+		chart.Add(bar)
+	}
+	spacer := canvas.NewRectangle(color.Transparent)
+	spacer.SetMinSize(fyne.NewSize(width, chartHeight))
+	scroll := container.NewHScroll(container.NewStack(spacer, chart))
+	scroll.SetMinSize(fyne.NewSize(min(width, 480), chartHeight+20))
+
+	summary := "Longest move: " + formatSeconds(longest)
+	for _, player := range gw.board.Rules().Players() {
+		summary += fmt.Sprintf("\n%s: %s total over %d moves", gw.getPlayerText(player), formatSeconds(totals[player]), counts[player])
+	}
+	content := container.NewVBox(scroll, widget.NewLabel(summary))
+	dialog.ShowCustom("Move Times", "Close", content, gw.window)
+}
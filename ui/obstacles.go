@@ -0,0 +1,88 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"slices"
+	"time"
+
+	"simple-gomoku/game"
+	"simple-gomoku/render"
+	"simple-gomoku/render/fynecanvas"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+)
+
+const obstaclesKey = "game.obstacles" // Blocked points new boards start with
+
+var (
+	obstacleCounts = []int{0, 5, 10, 20, 30}
+	blockedColor   = color.RGBA{R: 120, G: 110, B: 100, A: 255}
+)
+
+// obstacleOptions lists the blocked point choices of the game settings
+// dialog, in the order of obstacleCounts
+func obstacleOptions() []string {
+	options := []string{"None"}
+	for _, n := range obstacleCounts[1:] {
+		options = append(options, fmt.Sprintf("%d points", n))
+	}
+	return options
+}
+
+// savedObstacles returns the number of blocked points new boards start with
+func savedObstacles() int {
+	return fyne.CurrentApp().Preferences().Int(profileKey(obstaclesKey))
+}
+
+// placeObstacles blocks random points of a new board, a different layout
+// each game. Rated games, drills and the other modes newBoard plays on a
+// standard board never get them.
+func placeObstacles(board *game.Board) {
+	if n := savedObstacles(); n > 0 {
+		board.SetupObstacles(game.RandomObstacles(n, time.Now().UnixNano()))
+	}
+}
+
+// newObstacleSelect creates the blocked point choice of the game settings
+// dialog. A change starts a new board with the new number of points.
+func (gw *GameWindow) newObstacleSelect() *widget.Select {
+	obstacleSelect := widget.NewSelect(obstacleOptions(), func(selected string) {
+		n := obstacleCounts[slices.Index(obstacleOptions(), selected)]
+		if n == savedObstacles() {
+			return
+		}
+		fyne.CurrentApp().Preferences().SetInt(profileKey(obstaclesKey), n)
+		gw.stopAI()
+		gw.board = gw.newBoard()
+		gw.updateBoard()
+		gw.updateStatus()
+		gw.logEvent("Playing with %d blocked points", len(gw.board.Obstacles()))
+	})
+	selected := max(0, slices.Index(obstacleCounts, savedObstacles()))
+	obstacleSelect.SetSelected(obstacleOptions()[selected])
+	return obstacleSelect
+}
+
+// markObstacles draws the blocked points of grid over the board, as the
+// render package draws them in screenshots
+func (gw *GameWindow) markObstacles(grid *[game.BoardSize][game.BoardSize]game.Player) {
+	if gw.obstacleMarks != nil {
+		gw.boardContainer.Remove(gw.obstacleMarks)
+		gw.obstacleMarks = nil
+	}
+	marks := fynecanvas.New()
+	style := render.Style{Grid: gw.gridLines[0].StrokeColor, Blocked: blockedColor}
+	for i := range grid {
+		for j, cell := range grid[i] {
+			if cell == game.Blocked {
+				render.Obstacle(marks, gw.geom.render(), style, i, j)
+			}
+		}
+	}
+	if len(marks.Objects) > 0 {
+		gw.obstacleMarks = marks.Container
+		gw.boardContainer.Add(marks.Container)
+	}
+}
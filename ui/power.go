@@ -0,0 +1,77 @@
+package ui
+
+import (
+	"runtime"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+)
+
+const (
+	powerModeKey = "power.mode" // One of powerModes, for the whole app since it is about the machine
+
+	powerAuto = "Automatic (on battery)"
+	powerOn   = "On"
+	powerOff  = "Off"
+
+	lowPowerWorkers    = 1           // Search goroutines in low power mode
+	batteryCheckPeriod = time.Minute // How long a battery check is trusted
+)
+
+var powerModes = []string{powerAuto, powerOn, powerOff}
+
+// battery caches whether the machine runs on battery, since checking can
+// mean running a command
+var battery struct {
+	mu      sync.Mutex
+	checked time.Time
+	on      bool
+}
+
+// onBatteryCached reports whether the machine runs on battery, checking at
+// most once per batteryCheckPeriod
+func onBatteryCached() bool {
+	battery.mu.Lock()
+	defer battery.mu.Unlock()
+	if time.Since(battery.checked) > batteryCheckPeriod {
+		battery.on = onBattery()
+		battery.checked = time.Now()
+	}
+	return battery.on
+}
+
+// lowPower reports whether low power mode is on: animations and engine
+// assist are off and the engine searches on a single goroutine, so casual
+// games do not drain a laptop's battery
+func lowPower() bool {
+	switch effectSetting(powerModeKey, powerModes) {
+	case powerOn:
+		return true
+	case powerOff:
+		return false
+	}
+	return onBatteryCached()
+}
+
+// searchWorkers returns the goroutines the engine may search on
+func searchWorkers() int {
+	if lowPower() {
+		return lowPowerWorkers
+	}
+	return runtime.NumCPU()
+}
+
+// setPowerMode saves mode and brings the window in line with it
+func (gw *GameWindow) setPowerMode(mode string) {
+	before := lowPower()
+	fyne.CurrentApp().Preferences().SetString(profileKey(powerModeKey), mode)
+	if after := lowPower(); after != before {
+		gw.refreshAssist()
+		if after {
+			gw.logEvent("Low power mode on")
+		} else {
+			gw.logEvent("Low power mode off")
+		}
+	}
+}
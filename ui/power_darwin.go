@@ -0,0 +1,12 @@
+package ui
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// onBattery reports whether pmset says the power comes from the battery
+func onBattery() bool {
+	out, err := exec.Command("pmset", "-g", "batt").Output()
+	return err == nil && strings.Contains(string(out), "'Battery Power'")
+}
@@ -0,0 +1,21 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// onBattery reports whether a battery is discharging, going by the power
+// supplies the kernel lists
+func onBattery() bool {
+	supplies, _ := filepath.Glob("/sys/class/power_supply/*")
+	for _, supply := range supplies {
+		kind, _ := os.ReadFile(filepath.Join(supply, "type"))
+		status, _ := os.ReadFile(filepath.Join(supply, "status"))
+		if strings.TrimSpace(string(kind)) == "Battery" && strings.TrimSpace(string(status)) == "Discharging" {
+			return true
+		}
+	}
+	return false
+}
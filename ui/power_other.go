@@ -0,0 +1,9 @@
+//go:build !linux && !darwin && !windows
+
+package ui
+
+// onBattery reports false where the power source cannot be checked, so
+// automatic low power mode stays off
+func onBattery() bool {
+	return false
+}
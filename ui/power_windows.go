@@ -0,0 +1,25 @@
+package ui
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+var getSystemPowerStatus = syscall.NewLazyDLL("kernel32.dll").NewProc("GetSystemPowerStatus")
+
+// systemPowerStatus is the SYSTEM_POWER_STATUS structure
+type systemPowerStatus struct {
+	ACLineStatus        byte
+	BatteryFlag         byte
+	BatteryLifePercent  byte
+	SystemStatusFlag    byte
+	BatteryLifeTime     uint32
+	BatteryFullLifeTime uint32
+}
+
+// onBattery reports whether Windows says the machine is off mains power
+func onBattery() bool {
+	var status systemPowerStatus
+	ok, _, _ := getSystemPowerStatus.Call(uintptr(unsafe.Pointer(&status)))
+	return ok != 0 && status.ACLineStatus == 0
+}
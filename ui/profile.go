@@ -0,0 +1,215 @@
+package ui
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"simple-gomoku/game"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	profilesKey      = "profiles"        // Saved list of profiles
+	activeProfileKey = "profiles.active" // ID of the profile in use
+)
+
+// profileAvatars are the avatars a new profile can choose from
+var profileAvatars = []string{"🙂", "😎", "🐼", "🦊", "🐯", "🐸", "🐙", "🚀"}
+
+// sharedPrefKeys are kept once for the whole app; every other preference is
+// kept separately for each profile
+var sharedPrefKeys = map[string]bool{
+	prefsVersionKey:  true,
+	profilesKey:      true,
+	activeProfileKey: true,
+	powerModeKey:     true,
+}
+
+// profile is one local player with their own settings and stats
+type profile struct {
+	ID     string `json:"id"` // Empty for the first profile, which keeps the unprefixed keys
+	Name   string `json:"name"`
+	Avatar string `json:"avatar"`
+}
+
+// key returns the preference key the profile keeps a setting under
+func (p profile) key(key string) string {
+	if p.ID == "" || sharedPrefKeys[key] {
+		return key
+	}
+	return "profile." + p.ID + "." + key
+}
+
+// rating returns the profile's rating from games against rated engines
+func (p profile) rating() game.Rating {
+	record := readRecordStore(p.key(adaptiveRecordsKey)).Record(adaptivePlayer)
+	return record.PlayerRating(time.Now())
+}
+
+func (p profile) label() string {
+	return fmt.Sprintf("%s %s (%s)", p.Avatar, p.Name, p.rating())
+}
+
+// profileKey returns the preference key holding a setting or stat of the
+// active profile
+func profileKey(key string) string {
+	return profile{ID: fyne.CurrentApp().Preferences().String(activeProfileKey)}.key(key)
+}
+
+// loadProfiles returns the saved profiles, always at least the first one
+func loadProfiles() []profile {
+	var profiles []profile
+	if saved := fyne.CurrentApp().Preferences().String(profilesKey); saved != "" {
+		if err := json.Unmarshal([]byte(saved), &profiles); err != nil {
+			backupUnreadable(profilesKey, saved, err)
+			profiles = nil
+		}
+	}
+	if len(profiles) == 0 || profiles[0].ID != "" {
+		profiles = append([]profile{{Name: adaptivePlayer, Avatar: profileAvatars[0]}}, profiles...)
+	}
+	return profiles
+}
+
+func saveProfiles(profiles []profile) {
+	if data, err := json.Marshal(profiles); err == nil {
+		fyne.CurrentApp().Preferences().SetString(profilesKey, string(data))
+	}
+}
+
+// activeProfile returns the profile in use
+func activeProfile() profile {
+	id := fyne.CurrentApp().Preferences().String(activeProfileKey)
+	profiles := loadProfiles()
+	for _, p := range profiles {
+		if p.ID == id {
+			return p
+		}
+	}
+	return profiles[0]
+}
+
+// newProfileSelect creates the header dropdown for switching profiles,
+// disabled in tournament mode
+func (gw *GameWindow) newProfileSelect() fyne.CanvasObject {
+	gw.profileSelect = widget.NewSelect(nil, func(string) {
+		profiles := loadProfiles()
+		if i := gw.profileSelect.SelectedIndex(); i >= 0 && i < len(profiles) {
+			gw.switchProfile(profiles[i])
+		}
+	})
+	gw.refreshProfileSelect()
+	addButton := widget.NewButtonWithIcon("", theme.ContentAddIcon(), gw.showNewProfileDialog)
+	if gw.kiosk != nil {
+		// The event is played and rated under the profile it started with
+		gw.profileSelect.Disable()
+		addButton.Disable()
+	}
+	return container.NewHBox(gw.profileSelect, addButton)
+}
+
+// refreshProfileSelect updates the dropdown after a profile or its rating
+// changes
+func (gw *GameWindow) refreshProfileSelect() {
+	if gw.profileSelect == nil {
+		return
+	}
+	profiles := loadProfiles()
+	active := activeProfile()
+	labels := make([]string, len(profiles))
+	selected := 0
+	for i, p := range profiles {
+		labels[i] = p.label()
+		if p.ID == active.ID {
+			selected = i
+		}
+	}
+	gw.profileSelect.SetOptions(labels)
+	gw.profileSelect.SetSelectedIndex(selected)
+}
+
+// switchProfile makes another profile active, reloading its settings and
+// stats in place
+func (gw *GameWindow) switchProfile(p profile) {
+	if p.ID == activeProfile().ID {
+		return
+	}
+	gw.stopAI()
+	fyne.CurrentApp().Preferences().SetString(activeProfileKey, p.ID)
+
+	gw.applyBackground(loadBackground())
+	gw.installShortcuts()
+	gw.window.SetMainMenu(gw.mainMenu())
+
+	// Ladder, gauntlet and drill progress belongs to the player who started
+	// it, so the new player starts a fresh game against the same engine
+	gw.ladderLevel = -1
+	gw.gauntlet = nil
+	gw.drill = nil
+	if gw.adaptive {
+		gw.ai = adaptiveAI()
+	}
+	gw.applyOpeningBook()
+	gw.rules = savedRules()
+	gw.board = gw.newBoard()
+	gw.updateBoard()
+	gw.updateStatus()
+	gw.refreshProfileSelect()
+	gw.logEvent("Switched to profile %s", p.Name)
+	gw.showToast("Playing as %s %s", p.Avatar, p.Name)
+	gw.promptDueReviews()
+}
+
+func (gw *GameWindow) showNewProfileDialog() {
+	nameEntry := widget.NewEntry()
+	nameEntry.SetPlaceHolder("Name")
+	avatarSelect := widget.NewSelect(profileAvatars, nil)
+	avatarSelect.SetSelectedIndex(0)
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Name", nameEntry),
+		widget.NewFormItem("Avatar", avatarSelect),
+	}
+	dialog.ShowForm("New Profile", "Create", "Cancel", items, func(create bool) {
+		if !create {
+			return
+		}
+		name := strings.TrimSpace(nameEntry.Text)
+		profiles := loadProfiles()
+		if name == "" {
+			dialog.ShowError(errors.New("The profile needs a name"), gw.window)
+			return
+		}
+		for _, p := range profiles {
+			if strings.EqualFold(p.Name, name) {
+				dialog.ShowError(fmt.Errorf("There is already a profile called %s", p.Name), gw.window)
+				return
+			}
+		}
+
+		p := profile{ID: newProfileID(profiles), Name: name, Avatar: avatarSelect.Selected}
+		saveProfiles(append(profiles, p))
+		gw.switchProfile(p)
+	}, gw.window)
+}
+
+// newProfileID returns an ID no profile uses yet
+func newProfileID(profiles []profile) string {
+	used := make(map[string]bool)
+	for _, p := range profiles {
+		used[p.ID] = true
+	}
+	for n := len(profiles); ; n++ {
+		if id := fmt.Sprintf("p%d", n); !used[id] {
+			return id
+		}
+	}
+}
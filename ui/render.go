@@ -0,0 +1,63 @@
+package ui
+
+import (
+	"image"
+	"os"
+
+	"simple-gomoku/game"
+	"simple-gomoku/render"
+)
+
+const screenshotScale = 2 // Screenshot pixels per board point
+
+// render returns the geometry in the form the render package takes
+func (g boardGeometry) render() render.Geometry {
+	return render.Geometry{Cell: g.cell, Padding: g.padding, Stone: g.stone, Marker: g.marker}
+}
+
+// render returns the background in the form the render package takes.
+// Unreadable images fall back to the wood color.
+func (bg boardBackground) render() render.Background {
+	if bg.image != "" {
+		if f, err := os.Open(bg.image); err == nil {
+			defer f.Close()
+			if img, _, err := image.Decode(f); err == nil {
+				return render.Background{Image: img}
+			}
+		}
+		return render.Background{Start: woodColor}
+	}
+	// The window's gradient runs from start at the bottom right
+	return render.Background{Start: bg.end, End: bg.start}
+}
+
+// renderStyle returns the colors the board is drawn with under policy
+func (gw *GameWindow) renderStyle(policy displayPolicy) render.Style {
+	bg := loadBackground()
+	gridColor, markerColor := contrastColors(bg.isDark())
+	return render.Style{
+		Background: bg.render(),
+		Grid:       gridColor,
+		Marker:     markerColor,
+		Black:      policy.stoneColor(game.Black),
+		White:      policy.stoneColor(game.White),
+		Red:        policy.stoneColor(game.Red),
+		Blocked:    blockedColor,
+	}
+}
+
+// shownPosition returns the stones shown on the board, those being set up
+// or placed as a handicap before the game, and the last move marked
+func (gw *GameWindow) shownPosition() (*[game.BoardSize][game.BoardSize]game.Player, [2]int) {
+	if gw.setup != nil {
+		return &gw.setup.grid, game.PassMove
+	}
+	if h := gw.placingHandicap(); h != nil {
+		return h.grid(), game.PassMove
+	}
+	last := game.PassMove
+	if move, ok := gw.board.LastMove(); ok {
+		last = move.Pos()
+	}
+	return &gw.board.Grid, last
+}
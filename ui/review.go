@@ -0,0 +1,103 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"simple-gomoku/game"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	reviewScheduleKey = "review.schedule." // Followed by the player's name
+	reviewPromptedKey = "review.prompted"  // Date of the last due reminder
+)
+
+// loadSchedule returns the local player's review schedule
+func loadSchedule() *game.Schedule {
+	saved := fyne.CurrentApp().Preferences().String(profileKey(reviewScheduleKey + adaptivePlayer))
+	if saved == "" {
+		return game.NewSchedule()
+	}
+	schedule, err := game.LoadSchedule(strings.NewReader(saved))
+	if err != nil {
+		backupUnreadable(profileKey(reviewScheduleKey+adaptivePlayer), saved, err)
+		return game.NewSchedule()
+	}
+	return schedule
+}
+
+func saveSchedule(schedule *game.Schedule) {
+	var saved strings.Builder
+	if err := schedule.Save(&saved); err == nil {
+		fyne.CurrentApp().Preferences().SetString(profileKey(reviewScheduleKey+adaptivePlayer), saved.String())
+	}
+}
+
+// promptDueReviews reminds the player of the items due for review, at most
+// once a day. Training is off in tournament mode, so it says nothing there.
+func (gw *GameWindow) promptDueReviews() {
+	if gw.kiosk != nil {
+		return
+	}
+	prefs := fyne.CurrentApp().Preferences()
+	today := time.Now().Format(time.DateOnly)
+	if prefs.String(profileKey(reviewPromptedKey)) == today {
+		return
+	}
+	due := len(loadSchedule().Due(time.Now()))
+	if due == 0 {
+		return
+	}
+	prefs.SetString(profileKey(reviewPromptedKey), today)
+	gw.showToast("Review due: %d items (Training > Review)", due)
+}
+
+// reviewMenuItem returns the main menu entry for the review list, labelled
+// with the number of items due
+func (gw *GameWindow) reviewMenuItem() *fyne.MenuItem {
+	label := "Review"
+	if due := len(loadSchedule().Due(time.Now())); due > 0 {
+		label = fmt.Sprintf("Review (%d due)", due)
+	}
+	return fyne.NewMenuItem(label, gw.showReviewDialog)
+}
+
+// showReviewDialog lists the training items due for review, each with a
+// button to start it
+func (gw *GameWindow) showReviewDialog() {
+	due := loadSchedule().Due(time.Now())
+	if len(due) == 0 {
+		dialog.ShowInformation("Review", "Nothing is due for review today.", gw.window)
+		return
+	}
+
+	openings := make(map[string]game.Opening)
+	for _, opening := range game.DefaultBook().Openings() {
+		openings[opening.Key()] = opening
+	}
+
+	var reviewDialog dialog.Dialog
+	items := container.NewVBox(widget.NewLabel(fmt.Sprintf("Review due: %d items", len(due))))
+	for _, card := range due {
+		label := widget.NewLabel(fmt.Sprintf("%s (every %d days)", card.Title, max(card.Interval, 1)))
+		opening, ok := openings[card.ID]
+		if !ok {
+			items.Add(label) // Content no longer available
+			continue
+		}
+		start := widget.NewButton("Start", func() {
+			reviewDialog.Hide()
+			gw.startDrill(opening)
+		})
+		items.Add(container.NewBorder(nil, nil, nil, start, label))
+	}
+	reviewDialog = dialog.NewCustom("Review", "Close", container.NewVScroll(items), gw.window)
+	reviewDialog.Resize(fyne.NewSize(360, 300))
+	reviewDialog.Show()
+}
@@ -0,0 +1,399 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"slices"
+	"strings"
+
+	"simple-gomoku/game"
+	"simple-gomoku/render"
+	"simple-gomoku/render/fynecanvas"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	diagramCell    = 24
+	diagramPadding = 16
+	diagramStone   = 20
+
+	winLengthKey = "game.winLength" // Stones in a row that win the profile's games, 0 for five
+	openingKey   = "game.opening"   // Opening rule of the profile's games, a game.OpeningRule
+	exactKey     = "game.exact"     // Only rows of exactly the win length win the profile's games
+	gravityKey   = "game.gravity"   // Stones drop down their column in the profile's games
+
+	threePlayersKey = "game.threePlayers" // Red joins the profile's games as a third player
+)
+
+// openingOptions name the opening rules in the new game dialog, indexed by
+// game.OpeningRule
+var openingOptions = []string{"Free", "Pro", "Long Pro", "Swap"}
+
+// savedRules returns the rules the active profile last chose
+func savedRules() game.Rules {
+	prefs := fyne.CurrentApp().Preferences()
+	rules := game.Rules{
+		WinLength: prefs.Int(profileKey(winLengthKey)),
+		Exact:     prefs.Bool(profileKey(exactKey)),
+		Opening:   game.OpeningRule(prefs.Int(profileKey(openingKey))),
+		Gravity:   prefs.Bool(profileKey(gravityKey)),
+
+		ThreePlayers: prefs.Bool(profileKey(threePlayersKey)),
+	}
+	if rules.WinLength == 0 {
+		rules.WinLength = game.WinCondition // Never chosen
+	}
+	if rules.WinLength > game.MaxExactLength {
+		rules.Exact = false // Left over from a shorter win length
+	}
+	if rules.Validate() != nil {
+		return game.StandardRules
+	}
+	return rules
+}
+
+// winLengthOptions are the rule choices offered in the new game dialog
+func winLengthOptions() []string {
+	var options []string
+	for length := game.MinWinLength; length <= game.MaxWinLength; length++ {
+		options = append(options, ruleOption(game.Rules{WinLength: length}))
+	}
+	return options
+}
+
+// ruleOption names rules in the new game dialog, e.g. "Five in a row"
+func ruleOption(rules game.Rules) string {
+	name := rules.String()
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+func openingFromOption(option string) game.OpeningRule {
+	return game.OpeningRule(max(0, slices.Index(openingOptions, option)))
+}
+
+func rulesFromOption(option string) game.Rules {
+	for length := game.MinWinLength; length <= game.MaxWinLength; length++ {
+		if rules := (game.Rules{WinLength: length}); ruleOption(rules) == option {
+			return rules
+		}
+	}
+	return game.StandardRules
+}
+
+// newBoard returns an empty board for a game under the chosen rules. The
+// adaptive engine, custom engines, ladder, gauntlet and drills always play
+// five in a row, which their ratings, protocols and lines assume.
+func (gw *GameWindow) newBoard() *game.Board {
+	if gw.adaptive || gw.engine != nil || gw.ladderLevel >= 0 || gw.gauntlet != nil || gw.drill != nil {
+		return game.NewBoard()
+	}
+	board, err := game.NewBoardWithRules(gw.rules)
+	if err != nil {
+		return game.NewBoard()
+	}
+	placeObstacles(board)
+	return board
+}
+
+// ruleEntry is one topic of the rules reference. Diagrams are drawn from
+// rows of text: 'X' is a black stone, 'O' a white stone, 'R' a red stone,
+// '#' a blocked point, '*' a key point and '.' an empty intersection.
+type ruleEntry struct {
+	name        string
+	description string
+	diagram     []string
+}
+
+var ruleEntries = []ruleEntry{
+	{
+		name: "Freestyle rules",
+		description: "Black moves first and the players take turns placing one stone on an empty " +
+			"intersection of the 15x15 board. The first player to get five or more stones in an " +
+			"unbroken row, horizontally, vertically or diagonally, wins.",
+		diagram: []string{
+			".......",
+			".......",
+			"XXXXX..",
+			"OOOO...",
+			".......",
+		},
+	},
+	{
+		name: "Pro opening",
+		description: "An optional rule that takes the edge off Black's first-move advantage. Black's " +
+			"first stone goes in the center, and Black's second stone, the third move of the game, " +
+			"at least three rows or columns away from it: on or outside the marked square. Under " +
+			"Long Pro the second stone goes at least four rows or columns away.",
+		diagram: []string{
+			"*******",
+			"*.....*",
+			"*.....*",
+			"*..X..*",
+			"*...O.*",
+			"*.....*",
+			"*******",
+		},
+	},
+	{
+		name: "Swap opening",
+		description: "An optional rule against Black's first-move advantage. After Black's first " +
+			"stone, White may take it over instead of replying: the stone turns white and Black " +
+			"plays next. Black has to open with a stone that is not too good to give away, such as one " +
+			"off the center.",
+		diagram: []string{".....", "..O..", "....."},
+	},
+	{
+		name: "Gravity",
+		description: "An optional variant played like Connect Four. A stone drops down the column " +
+			"clicked to the lowest empty point, so every stone rests on the bottom edge or on " +
+			"another stone, and rows are built up from the bottom. Pro openings cannot be combined with it.",
+		diagram: []string{
+			".......",
+			"...*...",
+			"..*XO..",
+			".OXOX*.",
+			"XOXXOXO",
+		},
+	},
+	{
+		name: "Three players",
+		description: "An optional variant for three. Red joins and moves after White, so the turns go " +
+			"Black, White, Red. The first to make a winning row wins. A threat must often be " +
+			"blocked by the player who moves next, since the one after may be too late. Opening " +
+			"rules and handicap stones are for two players and cannot be combined with it.",
+		diagram: []string{
+			".......",
+			".XRO...",
+			"..XRO..",
+			"...*...",
+			".......",
+		},
+	},
+	{
+		name: "Blocked points",
+		description: "An optional variant: some intersections, chosen at random for each game, are " +
+			"blocked before the first move. Nobody may play on them, and a row cannot run through " +
+			"one, so they cut lines short like a stone of neither color. The game is a draw once " +
+			"every other point is taken. Handicap stones cannot be combined with them, and rated " +
+			"games are always played on an open board.",
+		diagram: []string{
+			".......",
+			".XXXX#.",
+			"...O...",
+			"..#O...",
+			"...O*..",
+		},
+	},
+	{
+		name:        "Five",
+		description: "Five stones in an unbroken row. The game is won.",
+		diagram:     []string{"XXXXX"},
+	},
+	{
+		name: "Overline",
+		description: "Six or more stones in an unbroken row. Under freestyle rules it wins like a " +
+			"five. Under the exact length rule it does not win, and the game goes on.",
+		diagram: []string{"XXXXXX"},
+	},
+	{
+		name: "Open four",
+		description: "Four in a row with both ends empty. Either end makes five, so it cannot be " +
+			"blocked and wins on the next move.",
+		diagram: []string{"*XXXX*"},
+	},
+	{
+		name: "Four",
+		description: "Four in a row with one end blocked. It threatens five at the marked point and " +
+			"must be answered there.",
+		diagram: []string{"OXXXX*"},
+	},
+	{
+		name: "Broken four",
+		description: "Four stones with a single gap in five cells. Filling the gap makes five, so " +
+			"it is as forcing as a four.",
+		diagram: []string{"XX*XX"},
+	},
+	{
+		name: "Open three",
+		description: "Three in a row with room on both sides. Left alone it becomes an open four at " +
+			"either marked point, so it must usually be blocked.",
+		diagram: []string{".*XXX*."},
+	},
+	{
+		name:        "Broken three",
+		description: "Three stones with a gap and both ends open. Filling the gap makes an open four.",
+		diagram:     []string{".XX*X."},
+	},
+	{
+		name:        "Double four",
+		description: "One move that makes two fours at once. The opponent can only block one of them.",
+		diagram: []string{
+			"......",
+			".X....",
+			"..X...",
+			"...X..",
+			".XXX*.",
+			"......",
+		},
+	},
+	{
+		name: "Four-three",
+		description: "One move that makes a four and an open three at once. Blocking the four lets " +
+			"the three become an open four.",
+		diagram: []string{
+			".......",
+			"...X...",
+			"...X...",
+			"XXX*...",
+			".......",
+		},
+	},
+	{
+		name: "Double three",
+		description: "One move that makes two open threes at once. Only one can be blocked, and the " +
+			"other becomes an open four.",
+		diagram: []string{
+			".......",
+			"...X...",
+			"...X...",
+			".XX*...",
+			".......",
+		},
+	},
+}
+
+// miniBoard draws a small board showing a rules diagram
+func miniBoard(diagram []string) fyne.CanvasObject {
+	rows, cols := len(diagram), 0
+	for _, line := range diagram {
+		cols = max(cols, len(line))
+	}
+	width := float32(2*diagramPadding + (cols-1)*diagramCell)
+	height := float32(2*diagramPadding + (rows-1)*diagramCell)
+	coord := func(index int) float32 {
+		return float32(diagramPadding + index*diagramCell)
+	}
+
+	board := container.NewWithoutLayout()
+	background := canvas.NewRectangle(woodColor)
+	background.Resize(fyne.NewSize(width, height))
+	board.Add(background)
+
+	for i := 0; i < rows; i++ {
+		line := canvas.NewLine(color.Black)
+		line.Position1 = fyne.NewPos(coord(0), coord(i))
+		line.Position2 = fyne.NewPos(coord(cols-1), coord(i))
+		board.Add(line)
+	}
+	for j := 0; j < cols; j++ {
+		line := canvas.NewLine(color.Black)
+		line.Position1 = fyne.NewPos(coord(j), coord(0))
+		line.Position2 = fyne.NewPos(coord(j), coord(rows-1))
+		board.Add(line)
+	}
+
+	for i, line := range diagram {
+		for j, cell := range line {
+			var stone *canvas.Circle
+			switch cell {
+			case 'X':
+				stone = canvas.NewCircle(color.Black)
+			case 'O':
+				stone = canvas.NewCircle(color.White)
+			case 'R':
+				stone = canvas.NewCircle(redStone)
+			case '*':
+				stone = canvas.NewCircle(color.Transparent)
+				stone.StrokeColor = color.RGBA{R: 0, G: 160, B: 0, A: 255}
+				stone.StrokeWidth = 3
+			case '#':
+				g := render.Geometry{Cell: diagramCell, Padding: diagramPadding, Stone: diagramStone}
+				render.Obstacle(fynecanvas.Canvas{Container: board}, g, render.Style{Grid: color.Black, Blocked: blockedColor}, i, j)
+				continue
+			default:
+				continue
+			}
+			stone.Resize(fyne.NewSize(diagramStone, diagramStone))
+			stone.Move(fyne.NewPos(coord(j)-diagramStone/2, coord(i)-diagramStone/2))
+			board.Add(stone)
+		}
+	}
+
+	// Without a layout the container has no size of its own
+	spacer := canvas.NewRectangle(color.Transparent)
+	spacer.SetMinSize(fyne.NewSize(width, height))
+	return container.NewStack(spacer, board)
+}
+
+// showRulesDialog shows the rules and the pattern glossary, drawing the
+// diagram of whichever topic is selected
+func (gw *GameWindow) showRulesDialog() {
+	description := widget.NewLabel("")
+	description.Wrapping = fyne.TextWrapWord
+	diagram := container.NewCenter()
+
+	topics := widget.NewList(
+		func() int { return len(ruleEntries) },
+		func() fyne.CanvasObject { return widget.NewLabel("Double three") },
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			item.(*widget.Label).SetText(ruleEntries[id].name)
+		},
+	)
+	topics.OnSelected = func(id widget.ListItemID) {
+		entry := ruleEntries[id]
+		description.SetText(entry.description)
+		diagram.Objects = []fyne.CanvasObject{miniBoard(entry.diagram)}
+		diagram.Refresh()
+	}
+	topics.Select(0)
+
+	legend := widget.NewLabel("Green rings mark the key points.")
+	detail := container.NewBorder(diagram, legend, nil, nil, description)
+	split := container.NewHSplit(topics, detail)
+	split.Offset = 0.3
+
+	// The topics describe five in a row; shapes shift with the win length
+	var content fyne.CanvasObject = split
+	if current := gw.board.Rules(); current.WinLength != game.WinCondition {
+		note := widget.NewLabel(fmt.Sprintf("This game is %s: %d or more stones in an unbroken row win. "+
+			"The shapes below are named for five in a row; here a four is any row one stone short "+
+			"of a win and a three two stones short.", current, current.WinLength))
+		note.Wrapping = fyne.TextWrapWord
+		content = container.NewBorder(note, nil, nil, nil, split)
+	}
+
+	rules := dialog.NewCustom("Rules", "Close", content, gw.window)
+	rules.Resize(fyne.NewSize(560, 380))
+	rules.Show()
+}
+
+// mainMenu creates the window menu
+func (gw *GameWindow) mainMenu() *fyne.MainMenu {
+	if gw.kiosk != nil {
+		return fyne.NewMainMenu(
+			fyne.NewMenu("Help",
+				fyne.NewMenuItem("Rules", gw.showRulesDialog),
+				fyne.NewMenuItem("Leave Tournament Mode...", gw.exitKiosk),
+			),
+		)
+	}
+	return fyne.NewMainMenu(
+		fyne.NewMenu("Training",
+			fyne.NewMenuItem("Opening Drill...", gw.showDrillDialog),
+			fyne.NewMenuItem("Set Up Position", gw.startSetup),
+			fyne.NewMenuItem("Teaching Layout", gw.toggleTeaching),
+			fyne.NewMenuItem("Compare Games...", gw.showCompareDialog),
+			fyne.NewMenuItem("Adjudicate Game...", gw.adjudicate),
+			gw.reviewMenuItem(),
+		),
+		fyne.NewMenu("Help",
+			fyne.NewMenuItem("Rules", gw.showRulesDialog),
+			fyne.NewMenuItem("Report a Bug...", gw.showBugReportDialog),
+		),
+	)
+}
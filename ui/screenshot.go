@@ -0,0 +1,188 @@
+package ui
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"simple-gomoku/render"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+const (
+	screenshotClipboardKey = "screenshot.clipboard" // Copy screenshots to the clipboard too
+	screenshotFontSize     = 12                     // Caption text size in board points
+)
+
+// screenshotDir is the folder screenshots are saved to
+func screenshotDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, "Pictures", "Gomoku", "Screenshots"), nil
+}
+
+func screenshotClipboard() bool {
+	return fyne.CurrentApp().Preferences().Bool(profileKey(screenshotClipboardKey))
+}
+
+// takeScreenshot saves the position on the board, with a caption naming the
+// players, the result, the date and the move number
+func (gw *GameWindow) takeScreenshot() {
+	shot := gw.boardImage()
+	players, status := gw.screenshotCaption()
+	if err := drawCaption(shot, players, status, screenshotScale); err != nil {
+		fyne.LogError("Could not draw the screenshot caption", err)
+	}
+	path, err := saveScreenshot(shot)
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("could not save the screenshot: %w", err), gw.window)
+		return
+	}
+	gw.logEvent("Screenshot saved to %s", path)
+	if screenshotClipboard() {
+		if err := copyImageToClipboard(path); err != nil {
+			fyne.LogError("Could not copy the screenshot", err)
+			gw.showToast("Screenshot saved, but could not be copied")
+			return
+		}
+		gw.showToast("Screenshot saved and copied")
+		return
+	}
+	gw.showToast("Screenshot saved to %s", filepath.Dir(path))
+}
+
+// boardImage draws the board shown at full size with the image backend, so
+// screenshots need neither the window nor a display. Hints and other marks
+// over the board are left out.
+func (gw *GameWindow) boardImage() *image.RGBA {
+	g := fullGeometry.render()
+	img := render.NewImage(g.Total(), g.Total(), screenshotScale)
+	grid, last := gw.shownPosition()
+	render.Board(img, g, gw.renderStyle(gw.activePolicy()), grid, last)
+	return img.RGBA
+}
+
+// screenshotCaption returns the players, and the move number, result and date,
+// written over the top and bottom of a screenshot
+func (gw *GameWindow) screenshotCaption() (players, status string) {
+	players = fmt.Sprintf("%s (Black) vs %s (White)", activeProfile().Name, gw.difficultyName)
+	switch {
+	case gw.hotSeat && gw.board.Rules().ThreePlayers:
+		players = "Three players"
+	case gw.hotSeat:
+		players = "Two players"
+	case gw.board.Rules().ThreePlayers:
+		players = fmt.Sprintf("%s (Black) vs %s (White and Red)", activeProfile().Name, gw.difficultyName)
+	case gw.engine != nil:
+		players = fmt.Sprintf("%s (Black) vs %s (White)", activeProfile().Name, gw.engine.Name())
+	}
+
+	result := gw.board.Result()
+	state := result.String()
+	if !result.Finished() {
+		state = gw.getPlayerText(gw.board.GetCurrentPlayer()) + " to move"
+	}
+	if rules := gw.board.Rules(); !rules.Standard() {
+		state += ", " + rules.String()
+	}
+	status = fmt.Sprintf("Move %d · %s · %s", len(gw.board.MoveHistory), state, time.Now().Format("2 Jan 2006 15:04"))
+	return players, status
+}
+
+// drawCaption writes one line in a dark band across the top of img and
+// another across the bottom, over the board's margins, with scale pixels per
+// board point
+func drawCaption(img *image.RGBA, top, bottom string, scale float64) error {
+	ttf, err := opentype.Parse(goregular.TTF)
+	if err != nil {
+		return err
+	}
+	face, err := opentype.NewFace(ttf, &opentype.FaceOptions{Size: screenshotFontSize * scale, DPI: 72})
+	if err != nil {
+		return err
+	}
+	defer face.Close()
+
+	metrics := face.Metrics()
+	margin := int(4 * scale)
+	height := metrics.Height.Ceil() + 2*margin
+	width := img.Bounds().Dx()
+	drawer := font.Drawer{Dst: img, Src: image.White, Face: face}
+	for _, band := range []struct {
+		text string
+		rect image.Rectangle
+	}{
+		{top, image.Rect(0, 0, width, height)},
+		{bottom, image.Rect(0, img.Bounds().Dy()-height, width, img.Bounds().Dy())},
+	} {
+		draw.Draw(img, band.rect, image.NewUniform(color.NRGBA{A: 160}), image.Point{}, draw.Over)
+		drawer.Dot = fixed.P(margin, band.rect.Min.Y+margin+metrics.Ascent.Ceil())
+		drawer.DrawString(band.text)
+	}
+	return nil
+}
+
+// saveScreenshot writes img to a new PNG file in the screenshots folder and
+// returns its path
+func saveScreenshot(img image.Image) (string, error) {
+	dir, err := screenshotDir()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, "gomoku-"+time.Now().Format("20060102-150405")+".png")
+	file, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	if err := png.Encode(file, img); err != nil {
+		file.Close()
+		return "", err
+	}
+	return path, file.Close()
+}
+
+// copyImageToClipboard puts the PNG at path on the system clipboard. Fyne's
+// clipboard only holds text, so this uses the platform's own tools.
+func copyImageToClipboard(path string) error {
+	switch runtime.GOOS {
+	case "darwin":
+		script := fmt.Sprintf("set the clipboard to (read (POSIX file %q) as «class PNGf»)", path)
+		return exec.Command("osascript", "-e", script).Run()
+	case "linux":
+		if _, err := exec.LookPath("wl-copy"); err == nil && os.Getenv("WAYLAND_DISPLAY") != "" {
+			cmd := exec.Command("wl-copy", "--type", "image/png")
+			file, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+			cmd.Stdin = file
+			return cmd.Run()
+		}
+		return exec.Command("xclip", "-selection", "clipboard", "-t", "image/png", "-i", path).Run()
+	case "windows":
+		script := fmt.Sprintf("Add-Type -AssemblyName System.Windows.Forms,System.Drawing; "+
+			"[Windows.Forms.Clipboard]::SetImage([Drawing.Image]::FromFile('%s'))", strings.ReplaceAll(path, "'", "''"))
+		return exec.Command("powershell", "-STA", "-Command", script).Run()
+	}
+	return fmt.Errorf("copying images is not supported on %s", runtime.GOOS)
+}
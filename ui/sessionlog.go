@@ -0,0 +1,94 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+)
+
+const sessionLogHeight = 120
+
+// sessionLog keeps timestamped, human-readable events for the whole session
+type sessionLog struct {
+	mu      sync.Mutex
+	entries []string
+	list    *widget.List // Panel showing the entries, nil until created
+}
+
+func (l *sessionLog) add(event string) {
+	l.mu.Lock()
+	l.entries = append(l.entries, time.Now().Format("15:04:05")+"  "+event)
+	l.mu.Unlock()
+	if l.list != nil {
+		l.list.Refresh()
+		l.list.ScrollToBottom()
+	}
+}
+
+func (l *sessionLog) text() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return strings.Join(l.entries, "\n") + "\n"
+}
+
+func (l *sessionLog) length() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.entries)
+}
+
+func (l *sessionLog) entry(i int) string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.entries[i]
+}
+
+// logEvent adds an event to the session log
+func (gw *GameWindow) logEvent(format string, args ...any) {
+	gw.sessionLog.add(fmt.Sprintf(format, args...))
+}
+
+// sessionLogItem creates the collapsible panel listing the session log
+func (gw *GameWindow) sessionLogItem() *widget.AccordionItem {
+	log := &gw.sessionLog
+	log.list = widget.NewList(
+		log.length,
+		func() fyne.CanvasObject {
+			return widget.NewLabel("")
+		},
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			item.(*widget.Label).SetText(log.entry(id))
+		},
+	)
+	exportButton := widget.NewButton("Export...", gw.exportSessionLog)
+
+	sized := container.New(layout.NewGridWrapLayout(fyne.NewSize(gw.geom.total(), sessionLogHeight)), log.list)
+	content := container.NewBorder(nil, container.NewHBox(layout.NewSpacer(), exportButton), nil, nil, sized)
+	return widget.NewAccordionItem("Session Log", content)
+}
+
+// exportSessionLog saves the session log as a text file
+func (gw *GameWindow) exportSessionLog() {
+	save := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil || writer == nil {
+			return
+		}
+		defer writer.Close()
+		if _, err := writer.Write([]byte(gw.sessionLog.text())); err != nil {
+			dialog.ShowError(err, gw.window)
+			return
+		}
+		gw.showToast("Session log saved")
+	}, gw.window)
+	save.SetFileName("gomoku-session-" + time.Now().Format("20060102-150405") + ".txt")
+	save.SetFilter(storage.NewExtensionFileFilter([]string{".txt"}))
+	save.Show()
+}
@@ -0,0 +1,159 @@
+package ui
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+)
+
+const raiseOnTurnKey = "window.raiseOnTurn"
+
+// raiseForTurn brings the window to the front when the opponent has moved,
+// if enabled in settings
+func (gw *GameWindow) raiseForTurn() {
+	if fyne.CurrentApp().Preferences().Bool(profileKey(raiseOnTurnKey)) {
+		gw.window.RequestFocus()
+	}
+}
+
+func (gw *GameWindow) showSettingsDialog() {
+	if gw.locked("settings") {
+		return
+	}
+	names := make([]string, 0, len(backgroundPresets))
+	for _, preset := range backgroundPresets {
+		names = append(names, preset.name)
+	}
+	backgroundSelect := widget.NewSelect(names, func(selected string) {
+		for _, preset := range backgroundPresets {
+			if preset.name == selected {
+				saveBackground(preset)
+				gw.applyBackground(preset)
+			}
+		}
+	})
+	if current := loadBackground(); current.image == "" {
+		backgroundSelect.SetSelected(current.name)
+	}
+
+	imageButton := widget.NewButton("Custom Image...", func() {
+		open := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err != nil || reader == nil {
+				return
+			}
+			reader.Close()
+			bg := imageBackground(reader.URI().Path())
+			saveBackground(bg)
+			gw.applyBackground(bg)
+			backgroundSelect.ClearSelected()
+		}, gw.window)
+		open.SetFilter(storage.NewExtensionFileFilter([]string{".png", ".jpg", ".jpeg"}))
+		open.Show()
+	})
+
+	prefs := fyne.CurrentApp().Preferences()
+	winSelect := widget.NewSelect(winEffects, func(selected string) {
+		prefs.SetString(profileKey(winEffectKey), selected)
+	})
+	winSelect.SetSelected(effectSetting(winEffectKey, winEffects))
+	loseSelect := widget.NewSelect(loseEffects, func(selected string) {
+		prefs.SetString(profileKey(loseEffectKey), selected)
+	})
+	loseSelect.SetSelected(effectSetting(loseEffectKey, loseEffects))
+	reducedMotionCheck := widget.NewCheck("Reduced motion (no animations)", func(checked bool) {
+		prefs.SetBool(profileKey(reducedMotionKey), checked)
+	})
+	reducedMotionCheck.SetChecked(prefs.Bool(profileKey(reducedMotionKey)))
+	raiseCheck := widget.NewCheck("Raise window when it's my turn", func(checked bool) {
+		prefs.SetBool(profileKey(raiseOnTurnKey), checked)
+	})
+	raiseCheck.SetChecked(prefs.Bool(profileKey(raiseOnTurnKey)))
+	commentaryCheck := widget.NewCheck("Opponent comments during play", func(checked bool) {
+		prefs.SetBool(profileKey(commentaryOffKey), !checked)
+		if !checked {
+			gw.clearComment()
+		}
+	})
+	commentaryCheck.SetChecked(commentaryEnabled())
+	clipboardCheck := widget.NewCheck("Copy screenshots to the clipboard", func(checked bool) {
+		prefs.SetBool(profileKey(screenshotClipboardKey), checked)
+	})
+	clipboardCheck.SetChecked(screenshotClipboard())
+
+	powerNote := widget.NewLabel("")
+	refreshPowerNote := func() {
+		if lowPower() {
+			powerNote.SetText("On: no animations or engine assist, one search thread")
+		} else {
+			powerNote.SetText("Off")
+		}
+	}
+	powerSelect := widget.NewSelect(powerModes, func(selected string) {
+		gw.setPowerMode(selected)
+		refreshPowerNote()
+	})
+	powerSelect.SetSelected(effectSetting(powerModeKey, powerModes))
+	refreshPowerNote()
+
+	shortcutsButton := widget.NewButton("Keyboard Shortcuts...", gw.showShortcutsDialog)
+
+	learnCheck := widget.NewCheck("Learn openings from my finished games", func(checked bool) {
+		prefs.SetBool(profileKey(bookLearningKey), checked)
+		gw.applyOpeningBook()
+	})
+	learnCheck.SetChecked(bookLearning())
+	forgetButton := widget.NewButton("Forget Learned Openings", func() {
+		dialog.ShowConfirm("Forget Learned Openings", "Delete the openings learned from your games?", func(ok bool) {
+			if ok {
+				gw.forgetLearnedOpenings()
+			}
+		}, gw.window)
+	})
+
+	engineLabel := widget.NewLabel(analysisEngineName())
+	engineButton := widget.NewButton("Choose...", func() {
+		open := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err != nil || reader == nil {
+				return
+			}
+			reader.Close()
+			prefs.SetString(profileKey(analysisEngineKey), reader.URI().Path())
+			engineLabel.SetText(analysisEngineName())
+		}, gw.window)
+		open.Show()
+	})
+	engineClearButton := widget.NewButton("Built-in", func() {
+		prefs.SetString(profileKey(analysisEngineKey), "")
+		gw.closeAnalysisEngine()
+		engineLabel.SetText(analysisEngineName())
+	})
+
+	content := container.NewVBox(
+		widget.NewLabel("Board Background:"),
+		backgroundSelect,
+		imageButton,
+		widget.NewLabel("Win Effect:"),
+		winSelect,
+		widget.NewLabel("Lose Effect:"),
+		loseSelect,
+		reducedMotionCheck,
+		commentaryCheck,
+		widget.NewLabel("Window:"),
+		raiseCheck,
+		clipboardCheck,
+		shortcutsButton,
+		widget.NewLabel("Low Power Mode:"),
+		powerSelect,
+		powerNote,
+		widget.NewLabel("Opening Book:"),
+		learnCheck,
+		forgetButton,
+		widget.NewLabel("Hint Engine (pbrain/Yixin protocol):"),
+		container.NewHBox(engineLabel, engineButton, engineClearButton),
+		widget.NewLabel("Club Settings:"),
+		gw.newClubSettings(),
+	)
+	dialog.ShowCustom("Settings", "Close", content, gw.window)
+}
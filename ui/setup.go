@@ -0,0 +1,483 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+
+	"simple-gomoku/game"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// brush is what a click paints in setup mode
+type brush int
+
+const (
+	brushBlack     brush = iota
+	brushWhite           // White stones
+	brushAlternate       // Black and White by turns
+	brushErase           // Empty cells
+	brushBlocked         // Blocked points
+)
+
+var brushNames = []string{"Black", "White", "Alternate", "Erase", "Blocked"}
+
+// mirror is the symmetry setup mode repeats every stone in
+type mirror int
+
+const (
+	mirrorNone      mirror = iota
+	mirrorLeftRight        // Across the middle column
+	mirrorTopBottom        // Across the middle row
+	mirrorCenter           // Through the center point
+)
+
+var mirrorNames = []string{"No mirror", "Mirror left-right", "Mirror top-bottom", "Mirror through center"}
+
+var playerNames = map[game.Player]string{game.Black: "Black", game.White: "White"}
+
+// boardSetup is the position being edited in setup mode
+type boardSetup struct {
+	grid      [game.BoardSize][game.BoardSize]game.Player
+	brush     brush
+	next      game.Player // Color the alternate brush places next
+	rectangle bool        // Clicks mark two corners of a rectangle the brush fills
+	corner    *[2]int     // First corner of the rectangle, nil until clicked
+	mirror    mirror
+	toMove    game.Player // Side to play once the position is set up
+	placed    [][2]int    // Stones painted so far, in order
+}
+
+// setupPosition is a position set up in setup mode and the board it is
+// played on. Games from it are not rated or learned from, and undo stops
+// at the position.
+type setupPosition struct {
+	board *game.Board
+	plies int // Moves that place the set-up stones
+}
+
+// setupPlies returns the moves of the current game that set up its
+// position, 0 for a game played from the empty board
+func (gw *GameWindow) setupPlies() int {
+	if gw.position.board != gw.board {
+		return 0
+	}
+	return gw.position.plies
+}
+
+// color returns the color the brush paints next
+func (s *boardSetup) color() game.Player {
+	switch s.brush {
+	case brushBlack:
+		return game.Black
+	case brushWhite:
+		return game.White
+	case brushAlternate:
+		return s.next
+	case brushBlocked:
+		return game.Blocked
+	}
+	return game.Empty
+}
+
+// paint sets the cell at (row, col) and its mirror images to player
+func (s *boardSetup) paint(row, col int, player game.Player) {
+	last := game.BoardSize - 1
+	cells := [][2]int{{row, col}}
+	switch s.mirror {
+	case mirrorLeftRight:
+		cells = append(cells, [2]int{row, last - col})
+	case mirrorTopBottom:
+		cells = append(cells, [2]int{last - row, col})
+	case mirrorCenter:
+		cells = append(cells, [2]int{last - row, last - col})
+	}
+	for _, cell := range cells {
+		s.grid[cell[0]][cell[1]] = player
+		s.placed = slices.DeleteFunc(s.placed, func(stone [2]int) bool { return stone == cell })
+		if player != game.Empty {
+			s.placed = append(s.placed, cell)
+		}
+	}
+}
+
+// counts returns the number of black and white stones, leaving out blocked
+// points
+func (s *boardSetup) counts() (black, white int) {
+	for _, row := range s.grid {
+		for _, cell := range row {
+			switch cell {
+			case game.Black:
+				black++
+			case game.White:
+				white++
+			}
+		}
+	}
+	return black, white
+}
+
+// blocked returns the number of blocked points
+func (s *boardSetup) blocked() int {
+	blocked := 0
+	for _, row := range s.grid {
+		for _, cell := range row {
+			if cell == game.Blocked {
+				blocked++
+			}
+		}
+	}
+	return blocked
+}
+
+// removeSurplus takes away stones of the color with too many until Black
+// has as many as White or one more, the most recently painted first
+func (s *boardSetup) removeSurplus() {
+	for {
+		black, white := s.counts()
+		surplus := game.Empty
+		switch {
+		case black > white+1:
+			surplus = game.Black
+		case white > black:
+			surplus = game.White
+		default:
+			return
+		}
+		s.removeLast(surplus)
+	}
+}
+
+// removeLast removes the stone of player painted last, or the last in
+// reading order if none of its stones were painted in this setup
+func (s *boardSetup) removeLast(player game.Player) {
+	for i := len(s.placed) - 1; i >= 0; i-- {
+		if stone := s.placed[i]; s.grid[stone[0]][stone[1]] == player {
+			s.grid[stone[0]][stone[1]] = game.Empty
+			s.placed = slices.Delete(s.placed, i, i+1)
+			return
+		}
+	}
+	for i := game.BoardSize - 1; i >= 0; i-- {
+		for j := game.BoardSize - 1; j >= 0; j-- {
+			if s.grid[i][j] == player {
+				s.grid[i][j] = game.Empty
+				return
+			}
+		}
+	}
+}
+
+// settle drops every stone down its column onto the bottom row, a blocked
+// point or the stone below it, for gravity games, keeping the order of each
+// column. Blocked points stay where they are.
+func (s *boardSetup) settle() {
+	for col := 0; col < game.BoardSize; col++ {
+		bottom := game.BoardSize - 1
+		for row := game.BoardSize - 1; row >= 0; row-- {
+			if player := s.grid[row][col]; player == game.Blocked {
+				bottom = row - 1
+			} else if player != game.Empty {
+				s.grid[row][col] = game.Empty
+				s.grid[bottom][col] = player
+				bottom--
+			}
+		}
+	}
+	s.placed = nil // The painted stones have moved
+}
+
+// sideToMove returns the side whose turn follows from the stone counts
+func (s *boardSetup) sideToMove() game.Player {
+	if black, white := s.counts(); black > white {
+		return game.White
+	}
+	return game.Black
+}
+
+// click applies the brush to the clicked cell, or to a rectangle once both
+// of its corners have been clicked
+func (s *boardSetup) click(row, col int) {
+	if s.rectangle {
+		if s.corner == nil {
+			s.corner = &[2]int{row, col}
+			return
+		}
+		corner := *s.corner
+		s.corner = nil
+		for i := min(row, corner[0]); i <= max(row, corner[0]); i++ {
+			for j := min(col, corner[1]); j <= max(col, corner[1]); j++ {
+				player := s.color()
+				if s.brush == brushAlternate && (i-corner[0]+j-corner[1])%2 != 0 {
+					player = opposite(player) // A checkerboard of both colors
+				}
+				s.paint(i, j, player)
+			}
+		}
+	} else {
+		player := s.color()
+		if s.grid[row][col] == player && s.brush != brushAlternate {
+			player = game.Empty // Clicking a stone of the brush color removes it
+		}
+		s.paint(row, col, player)
+	}
+	if s.brush == brushAlternate {
+		s.next = opposite(s.next)
+	}
+}
+
+func opposite(player game.Player) game.Player {
+	if player == game.Black {
+		return game.White
+	}
+	return game.Black
+}
+
+// status describes the position being set up
+func (s *boardSetup) status() string {
+	if s.corner != nil {
+		return "Setup: click the opposite corner"
+	}
+	black, white := s.counts()
+	status := fmt.Sprintf("Setup: %d black, %d white", black, white)
+	if blocked := s.blocked(); blocked > 0 {
+		status += fmt.Sprintf(", %d blocked", blocked)
+	}
+	return status
+}
+
+// startSetup enters setup mode, starting from the stones on the board
+func (gw *GameWindow) startSetup() {
+	if gw.setup != nil {
+		return
+	}
+	if gw.board.Rules().ThreePlayers {
+		gw.showToast("Positions can only be set up for two players")
+		return
+	}
+	if gw.miniMode {
+		gw.toggleMiniMode() // The tools need the full layout
+	}
+	gw.stopAI()
+	gw.isProcessing = false
+	gw.setup = &boardSetup{grid: gw.board.Grid, next: game.Black}
+	gw.setup.toMove = gw.setup.sideToMove()
+	gw.setupToMove.SetSelected(playerNames[gw.setup.toMove])
+	gw.refreshSetupBar()
+	gw.updateBoard()
+	gw.updateStatus()
+	gw.logEvent("Setting up a position")
+}
+
+// setupClick paints on the position being set up
+func (gw *GameWindow) setupClick(row, col int) {
+	gw.setup.click(row, col)
+	gw.clearHint()
+	if corner := gw.setup.corner; corner != nil {
+		gw.markHint(corner[0], corner[1])
+	}
+	gw.drawGrid(&gw.setup.grid, displayNormal)
+	gw.updateStatus()
+}
+
+// finishSetup checks the position and plays on from it, or lists the
+// problems found with ways to fix them
+func (gw *GameWindow) finishSetup() {
+	gw.checkSetup(false)
+}
+
+// checkSetup validates the position before playing it. With finished set, a
+// winning row is accepted and the position is played as a finished game.
+func (gw *GameWindow) checkSetup(finished bool) {
+	var issues []game.PositionIssue
+	for _, issue := range game.CheckPosition(gw.setup.grid, gw.setup.toMove, gw.board.Rules()) {
+		if !finished || issue.Problem != game.ProblemWinningRow {
+			issues = append(issues, issue)
+		}
+	}
+	if len(issues) > 0 {
+		gw.showSetupIssues(issues)
+		return
+	}
+	gw.playSetup()
+}
+
+// showSetupIssues lists the problems with the position, each with a fix
+// where there is an obvious one
+func (gw *GameWindow) showSetupIssues(issues []game.PositionIssue) {
+	var issuesDialog dialog.Dialog
+	fix := func(label string, apply func()) *widget.Button {
+		return widget.NewButton(label, func() {
+			issuesDialog.Hide()
+			apply()
+		})
+	}
+
+	content := container.NewVBox()
+	onlyWins := true
+	for _, issue := range issues {
+		message := widget.NewLabel(issue.Message)
+		message.Wrapping = fyne.TextWrapWord
+		content.Add(message)
+		switch issue.Problem {
+		case game.ProblemStoneCount:
+			onlyWins = false
+			content.Add(fix("Remove Extra Stones", func() {
+				gw.setup.removeSurplus()
+				gw.updateBoard()
+				gw.updateStatus()
+				gw.finishSetup()
+			}))
+		case game.ProblemFloating:
+			onlyWins = false
+			content.Add(fix("Let Stones Fall", func() {
+				gw.setup.settle()
+				gw.updateBoard()
+				gw.updateStatus()
+				gw.finishSetup()
+			}))
+		case game.ProblemSideToMove:
+			onlyWins = false
+			side := gw.setup.sideToMove()
+			content.Add(fix(fmt.Sprintf("Let %s Move", playerNames[side]), func() {
+				gw.setup.toMove = side
+				gw.setupToMove.SetSelected(playerNames[side])
+				gw.finishSetup()
+			}))
+		}
+	}
+	if onlyWins {
+		content.Add(fix("Play as a Finished Game", func() { gw.checkSetup(true) }))
+	}
+
+	issuesDialog = dialog.NewCustom("Check the Position", "Keep Editing", content, gw.window)
+	issuesDialog.Resize(fyne.NewSize(420, 0))
+	issuesDialog.Show()
+}
+
+// playSetup leaves setup mode and plays on from the position, with the AI
+// moving first if it is White's turn
+func (gw *GameWindow) playSetup() {
+	board, err := game.NewBoardFromPosition(gw.setup.grid, gw.board.Rules())
+	if err != nil {
+		dialog.ShowError(err, gw.window)
+		return
+	}
+	if gw.drill != nil {
+		gw.ai = game.NewAI(game.White, game.Hard) // Drill engines only follow the book
+		gw.difficultyName = "Hard"
+	}
+	gw.ladderLevel = -1
+	gw.gauntlet = nil
+	gw.drill = nil
+	gw.setup = nil
+	gw.board = board
+	gw.position = setupPosition{board: board, plies: len(board.MoveHistory)}
+	gw.refreshSetupBar()
+	gw.updateBoard()
+	gw.updateStatus()
+	gw.logEvent("Playing from a set-up position")
+	if board.CurrentTurn == game.White && !gw.hotSeat && !board.IsGameFinished() {
+		gw.isProcessing = true
+		gw.playAITurn()
+	}
+}
+
+// cancelSetup leaves setup mode without changing the game
+func (gw *GameWindow) cancelSetup() {
+	gw.setup = nil
+	gw.refreshSetupBar()
+	gw.updateBoard()
+	gw.updateStatus()
+}
+
+// newSetupBar creates the setup mode tools, hidden outside setup mode
+func (gw *GameWindow) newSetupBar() fyne.CanvasObject {
+	brushes := widget.NewRadioGroup(brushNames, func(selected string) {
+		if gw.setup == nil {
+			return
+		}
+		for i, name := range brushNames {
+			if name == selected {
+				gw.setup.brush = brush(i)
+			}
+		}
+	})
+	brushes.Horizontal = true
+	brushes.Required = true
+	rectangleCheck := widget.NewCheck("Rectangle", func(checked bool) {
+		if gw.setup == nil {
+			return
+		}
+		gw.setup.rectangle = checked
+		gw.setup.corner = nil
+		gw.clearHint()
+		gw.updateStatus()
+	})
+	mirrorSelect := widget.NewSelect(mirrorNames, func(selected string) {
+		if gw.setup == nil {
+			return
+		}
+		for i, name := range mirrorNames {
+			if name == selected {
+				gw.setup.mirror = mirror(i)
+			}
+		}
+	})
+	if gw.setup != nil {
+		brushes.SetSelected(brushNames[gw.setup.brush])
+		rectangleCheck.SetChecked(gw.setup.rectangle)
+		mirrorSelect.SetSelected(mirrorNames[gw.setup.mirror])
+	} else {
+		brushes.SetSelected(brushNames[brushBlack])
+		mirrorSelect.SetSelected(mirrorNames[mirrorNone])
+	}
+
+	clearButton := widget.NewButton("Clear", func() {
+		if gw.setup != nil {
+			gw.setup.grid = [game.BoardSize][game.BoardSize]game.Player{}
+			gw.setup.placed = nil
+			gw.updateBoard()
+			gw.updateStatus()
+		}
+	})
+	gw.setupToMove = widget.NewRadioGroup([]string{playerNames[game.Black], playerNames[game.White]}, func(selected string) {
+		if gw.setup == nil {
+			return
+		}
+		for player, name := range playerNames {
+			if name == selected {
+				gw.setup.toMove = player
+			}
+		}
+	})
+	gw.setupToMove.Horizontal = true
+	gw.setupToMove.Required = true
+	toMove := game.Black
+	if gw.setup != nil {
+		toMove = gw.setup.toMove
+	}
+	gw.setupToMove.SetSelected(playerNames[toMove])
+
+	doneButton := widget.NewButton("Done", gw.finishSetup)
+	cancelButton := widget.NewButton("Cancel", gw.cancelSetup)
+
+	gw.setupBar = container.NewVBox(
+		container.NewHBox(brushes, rectangleCheck, mirrorSelect),
+		container.NewHBox(widget.NewLabel("To move:"), gw.setupToMove, clearButton, doneButton, cancelButton),
+	)
+	gw.refreshSetupBar()
+	return gw.setupBar
+}
+
+// refreshSetupBar shows the setup tools while in setup mode
+func (gw *GameWindow) refreshSetupBar() {
+	if gw.setup != nil {
+		gw.setupBar.Show()
+	} else {
+		gw.setupBar.Hide()
+	}
+}
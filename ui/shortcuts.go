@@ -0,0 +1,285 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/widget"
+)
+
+const shortcutKeyPrefix = "shortcuts."
+
+// shortcutAction is a command that can be bound to a key
+type shortcutAction struct {
+	id         string // Preference key suffix, must not change
+	name       string
+	defaultKey string
+}
+
+var shortcutActions = []shortcutAction{
+	{id: "undo", name: "Undo", defaultKey: "Ctrl+Z"},
+	{id: "redo", name: "Redo", defaultKey: "Ctrl+Y"},
+	{id: "hint", name: "Hint", defaultKey: "H"},
+	{id: "pass", name: "Pass", defaultKey: "P"},
+	{id: "newGame", name: "New Game", defaultKey: "Ctrl+N"},
+	{id: "ladder", name: "Ladder", defaultKey: "L"},
+	{id: "gauntlet", name: "Gauntlet", defaultKey: "G"},
+	{id: "settings", name: "Settings", defaultKey: "Ctrl+,"},
+	{id: "miniMode", name: "Mini Mode", defaultKey: "M"},
+	{id: "rules", name: "Rules", defaultKey: "F1"},
+	{id: "screenshot", name: "Screenshot", defaultKey: "F12"},
+}
+
+// shortcutCommand returns the function an action runs
+func (gw *GameWindow) shortcutCommand(id string) func() {
+	switch id {
+	case "undo":
+		return gw.undoMove
+	case "redo":
+		return gw.redoMove
+	case "hint":
+		return gw.showHint
+	case "pass":
+		return gw.passMove
+	case "newGame":
+		return gw.newGame
+	case "ladder":
+		return gw.showLadderDialog
+	case "gauntlet":
+		return gw.startGauntlet
+	case "settings":
+		return gw.showSettingsDialog
+	case "miniMode":
+		return gw.toggleMiniMode
+	case "rules":
+		return gw.showRulesDialog
+	case "screenshot":
+		return gw.takeScreenshot
+	}
+	return func() {}
+}
+
+var modifierNames = []struct {
+	name     string
+	modifier fyne.KeyModifier
+}{
+	{"Ctrl", fyne.KeyModifierControl},
+	{"Alt", fyne.KeyModifierAlt},
+	{"Shift", fyne.KeyModifierShift},
+	{"Super", fyne.KeyModifierSuper},
+}
+
+// namedKeys lists the keys besides letters and digits that can be bound
+var namedKeys = map[string]fyne.KeyName{
+	"F1": fyne.KeyF1, "F2": fyne.KeyF2, "F3": fyne.KeyF3, "F4": fyne.KeyF4,
+	"F5": fyne.KeyF5, "F6": fyne.KeyF6, "F7": fyne.KeyF7, "F8": fyne.KeyF8,
+	"F9": fyne.KeyF9, "F10": fyne.KeyF10, "F11": fyne.KeyF11, "F12": fyne.KeyF12,
+	"Left": fyne.KeyLeft, "Right": fyne.KeyRight, "Up": fyne.KeyUp, "Down": fyne.KeyDown,
+	"Home": fyne.KeyHome, "End": fyne.KeyEnd, "PageUp": fyne.KeyPageUp, "PageDown": fyne.KeyPageDown,
+	"Space": fyne.KeySpace, "Return": fyne.KeyReturn, "Escape": fyne.KeyEscape,
+	"Tab": fyne.KeyTab, "BackSpace": fyne.KeyBackspace, "Delete": fyne.KeyDelete,
+	",": fyne.KeyComma, ".": fyne.KeyPeriod, "/": fyne.KeySlash, "-": fyne.KeyMinus, "=": fyne.KeyEqual,
+}
+
+// keyBinding is a key with the modifiers that must be held with it
+type keyBinding struct {
+	key      fyne.KeyName
+	modifier fyne.KeyModifier
+}
+
+// parseBinding reads a binding such as "Ctrl+Shift+Z", "F1" or "H"
+func parseBinding(s string) (keyBinding, error) {
+	var binding keyBinding
+	parts := strings.Split(strings.TrimSpace(s), "+")
+	for _, part := range parts[:len(parts)-1] {
+		found := false
+		for _, m := range modifierNames {
+			if strings.EqualFold(part, m.name) {
+				binding.modifier |= m.modifier
+				found = true
+			}
+		}
+		if !found {
+			return binding, fmt.Errorf("unknown modifier %q", part)
+		}
+	}
+
+	key := parts[len(parts)-1]
+	if len(key) == 1 && strings.ContainsAny(strings.ToUpper(key), "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789") {
+		binding.key = fyne.KeyName(strings.ToUpper(key))
+		return binding, nil
+	}
+	for name, keyName := range namedKeys {
+		if strings.EqualFold(key, name) {
+			binding.key = keyName
+			return binding, nil
+		}
+	}
+	if key == "" {
+		return binding, errors.New("missing key")
+	}
+	return binding, fmt.Errorf("unknown key %q", key)
+}
+
+// String formats the binding the way parseBinding reads it
+func (b keyBinding) String() string {
+	var parts []string
+	for _, m := range modifierNames {
+		if b.modifier&m.modifier != 0 {
+			parts = append(parts, m.name)
+		}
+	}
+	key := string(b.key)
+	for name, keyName := range namedKeys {
+		if keyName == b.key {
+			key = name
+		}
+	}
+	return strings.Join(append(parts, key), "+")
+}
+
+// loadBinding returns the saved binding for an action, or its default
+func loadBinding(action shortcutAction) keyBinding {
+	saved := fyne.CurrentApp().Preferences().StringWithFallback(profileKey(shortcutKeyPrefix+action.id), action.defaultKey)
+	if binding, err := parseBinding(saved); err == nil {
+		return binding
+	}
+	binding, _ := parseBinding(action.defaultKey)
+	return binding
+}
+
+// shortcutConflicts describes every binding used by more than one action.
+// bindings is indexed like shortcutActions.
+func shortcutConflicts(bindings []keyBinding) []string {
+	users := make(map[keyBinding][]string)
+	for i, binding := range bindings {
+		users[binding] = append(users[binding], shortcutActions[i].name)
+	}
+
+	var conflicts []string
+	for binding, names := range users {
+		if len(names) > 1 {
+			conflicts = append(conflicts, fmt.Sprintf("%s is used by %s", binding, strings.Join(names, " and ")))
+		}
+	}
+	sort.Strings(conflicts)
+	return conflicts
+}
+
+// installShortcuts registers the current bindings with the window, replacing
+// any registered before. Bindings without modifiers act as plain key presses
+// when no widget has focus.
+func (gw *GameWindow) installShortcuts() {
+	canvas := gw.window.Canvas()
+	for _, shortcut := range gw.shortcuts {
+		canvas.RemoveShortcut(shortcut)
+	}
+	gw.shortcuts = nil
+
+	plainKeys := make(map[fyne.KeyName]func())
+	for _, action := range shortcutActions {
+		run := gw.shortcutCommand(action.id)
+		binding := loadBinding(action)
+		if binding.modifier == 0 {
+			plainKeys[binding.key] = run
+			continue
+		}
+		shortcut := &desktop.CustomShortcut{KeyName: binding.key, Modifier: binding.modifier}
+		canvas.AddShortcut(shortcut, func(fyne.Shortcut) { run() })
+		gw.shortcuts = append(gw.shortcuts, shortcut)
+	}
+	canvas.SetOnTypedKey(func(event *fyne.KeyEvent) {
+		if run, ok := plainKeys[event.Name]; ok {
+			run()
+		}
+	})
+}
+
+// showShortcutsDialog lets the player rebind every action, refusing to save
+// while two actions share a binding
+func (gw *GameWindow) showShortcutsDialog() {
+	entries := make([]*widget.Entry, len(shortcutActions))
+	items := make([]*widget.FormItem, len(shortcutActions))
+	conflictLabel := widget.NewLabel("")
+	conflictLabel.Wrapping = fyne.TextWrapWord
+
+	// bindings parses every entry, reporting the first invalid one
+	bindings := func() ([]keyBinding, error) {
+		result := make([]keyBinding, len(entries))
+		for i, entry := range entries {
+			binding, err := parseBinding(entry.Text)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", shortcutActions[i].name, err)
+			}
+			result[i] = binding
+		}
+		return result, nil
+	}
+	check := func(string) {
+		parsed, err := bindings()
+		switch {
+		case err != nil:
+			conflictLabel.SetText(err.Error())
+		case len(shortcutConflicts(parsed)) > 0:
+			conflictLabel.SetText(strings.Join(shortcutConflicts(parsed), "\n"))
+		default:
+			conflictLabel.SetText("")
+		}
+	}
+
+	for i, action := range shortcutActions {
+		entry := widget.NewEntry()
+		entry.SetText(loadBinding(action).String())
+		entry.SetPlaceHolder(action.defaultKey)
+		entry.Validator = func(s string) error {
+			_, err := parseBinding(s)
+			return err
+		}
+		entry.OnChanged = check
+		entries[i] = entry
+		items[i] = widget.NewFormItem(action.name, entry)
+	}
+
+	resetButton := widget.NewButton("Reset to Defaults", func() {
+		for i, action := range shortcutActions {
+			entries[i].SetText(action.defaultKey)
+		}
+	})
+	content := container.NewVBox(
+		widget.NewLabel("Use names like Ctrl+Z, Shift+F5 or H."),
+		widget.NewForm(items...),
+		resetButton,
+		conflictLabel,
+	)
+
+	var editor *dialog.CustomDialog
+	saveButton := widget.NewButton("Save", func() {
+		parsed, err := bindings()
+		if err != nil {
+			dialog.ShowError(err, gw.window)
+			return
+		}
+		if conflicts := shortcutConflicts(parsed); len(conflicts) > 0 {
+			dialog.ShowError(errors.New(strings.Join(conflicts, "\n")), gw.window)
+			return
+		}
+		prefs := fyne.CurrentApp().Preferences()
+		for i, action := range shortcutActions {
+			prefs.SetString(profileKey(shortcutKeyPrefix+action.id), parsed[i].String())
+		}
+		gw.installShortcuts()
+		editor.Hide()
+	})
+	cancelButton := widget.NewButton("Cancel", func() {
+		editor.Hide()
+	})
+	editor = dialog.NewCustomWithoutButtons("Keyboard Shortcuts", content, gw.window)
+	editor.SetButtons([]fyne.CanvasObject{cancelButton, saveButton})
+	editor.Show()
+}
@@ -0,0 +1,109 @@
+package ui
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// sound is a sound the game plays. Effects such as stone clicks are queued
+// and coalesced; announcements play on their own and duck the effects.
+type sound int
+
+const (
+	soundStone    sound = iota // A stone is placed
+	soundGameOver              // Announcement that the game has ended
+)
+
+const (
+	effectQueue  = 4    // Effects waiting to play; more are dropped
+	duckedVolume = 0.35 // Effect volume while an announcement plays
+)
+
+// mixer plays the game's sounds from two goroutines, one for effects and one
+// for announcements, so bursts of events such as fast replays neither stack
+// up overlapping players nor spawn a goroutine each
+type mixer struct {
+	start         sync.Once
+	effects       chan sound
+	announcements chan sound
+	ducked        atomic.Bool // An announcement is playing
+}
+
+var sounds = &mixer{
+	effects:       make(chan sound, effectQueue),
+	announcements: make(chan sound, 1),
+}
+
+// play queues an effect, dropping it if the queue is full
+func (m *mixer) play(s sound) {
+	m.start.Do(m.run)
+	select {
+	case m.effects <- s:
+	default:
+	}
+}
+
+// announce queues an announcement, dropping it if one is already waiting
+func (m *mixer) announce(s sound) {
+	m.start.Do(m.run)
+	select {
+	case m.announcements <- s:
+	default:
+	}
+}
+
+func (m *mixer) run() {
+	go func() {
+		for s := range m.effects {
+			// Effects that queued up while this one waited are the same
+			// click repeated; one is enough
+			for drained := false; !drained; {
+				select {
+				case <-m.effects:
+				default:
+					drained = true
+				}
+			}
+			volume := 1.0
+			if m.ducked.Load() {
+				volume = duckedVolume
+			}
+			playSystemSound(s, volume)
+		}
+	}()
+	go func() {
+		for s := range m.announcements {
+			m.ducked.Store(true)
+			playSystemSound(s, 1)
+			m.ducked.Store(false)
+		}
+	}()
+}
+
+// playSystemSound plays s with the platform's player and waits for it to end.
+// Volume runs from 0 to 1; the Windows beep has no volume.
+func playSystemSound(s sound, volume float64) {
+	switch runtime.GOOS {
+	case "darwin":
+		file := "/System/Library/Sounds/Tink.aiff"
+		if s == soundGameOver {
+			file = "/System/Library/Sounds/Glass.aiff"
+		}
+		exec.Command("afplay", "-v", fmt.Sprint(volume), file).Run()
+	case "linux":
+		file := "/usr/share/sounds/freedesktop/stereo/bell.oga"
+		if s == soundGameOver {
+			file = "/usr/share/sounds/freedesktop/stereo/complete.oga"
+		}
+		exec.Command("paplay", fmt.Sprintf("--volume=%d", int(volume*65536)), file).Run()
+	case "windows":
+		beep := "[console]::beep(2000,100)"
+		if s == soundGameOver {
+			beep = "[console]::beep(1000,300)"
+		}
+		exec.Command("powershell", beep).Run()
+	}
+}
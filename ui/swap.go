@@ -0,0 +1,34 @@
+package ui
+
+import (
+	"simple-gomoku/game"
+
+	"fyne.io/fyne/v2/dialog"
+)
+
+// offerSwap asks White, in a two-player game under the swap opening,
+// whether to take over Black's first stone instead of replying
+func (gw *GameWindow) offerSwap() {
+	confirm := dialog.NewConfirm("Swap?",
+		"White may take over Black's first stone instead of replying.\nThe stone turns White and Black plays next.",
+		func(swap bool) {
+			if swap && gw.board.CanSwap() {
+				gw.swapFirstStone()
+			}
+		}, gw.window)
+	confirm.SetConfirmText("Swap")
+	confirm.SetDismissText("Reply")
+	confirm.Show()
+}
+
+// swapFirstStone takes over Black's first stone for White and redraws it
+func (gw *GameWindow) swapFirstStone() {
+	if gw.board.Swap() != nil {
+		return
+	}
+	first := gw.board.MoveHistory[0]
+	frame.paint(gw.stones[first.Row][first.Col], gw.activePolicy().stoneColor(game.White))
+	gw.updateStatus()
+	gw.logEvent("White swaps, taking over %s", game.FormatMove(first.Row, first.Col))
+	gw.showToast("White takes over the first stone, Black plays next")
+}
@@ -0,0 +1,152 @@
+package ui
+
+import (
+	"image/color"
+
+	"simple-gomoku/game"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// demoBoard is the free board of the teaching layout, shown beside the game
+// for demonstrating lines without touching the game itself
+type demoBoard struct {
+	grid    [game.BoardSize][game.BoardSize]game.Player
+	next    game.Player // Color the next stone placed takes
+	history []demoState // States before each change, for undo
+	stones  [][]*canvas.Circle
+	status  *widget.Label
+}
+
+type demoState struct {
+	grid [game.BoardSize][game.BoardSize]game.Player
+	next game.Player
+}
+
+// click places a stone of the next color on an empty cell, alternating the
+// colors, or takes away the stone on an occupied one
+func (d *demoBoard) click(row, col int) {
+	d.history = append(d.history, demoState{d.grid, d.next})
+	if d.grid[row][col] != game.Empty {
+		d.grid[row][col] = game.Empty
+		return
+	}
+	d.grid[row][col] = d.next
+	d.next = opposite(d.next)
+}
+
+func (d *demoBoard) undo() {
+	if n := len(d.history); n > 0 {
+		d.grid, d.next = d.history[n-1].grid, d.history[n-1].next
+		d.history = d.history[:n-1]
+	}
+}
+
+// load replaces the stones with grid, next to move
+func (d *demoBoard) load(grid [game.BoardSize][game.BoardSize]game.Player, next game.Player) {
+	d.history = append(d.history, demoState{d.grid, d.next})
+	d.grid, d.next = grid, next
+}
+
+func (d *demoBoard) refresh() {
+	for i, row := range d.grid {
+		for j, cell := range row {
+			frame.paint(d.stones[i][j], displayNormal.stoneColor(cell))
+		}
+	}
+	d.status.SetText(playerNames[d.next] + " stone next")
+}
+
+// toggleTeaching shows or hides the demonstration board beside the game
+func (gw *GameWindow) toggleTeaching() {
+	if gw.teaching != nil {
+		gw.teaching = nil
+		gw.logEvent("Teaching layout closed")
+	} else {
+		gw.teaching = &demoBoard{next: game.Black}
+		gw.logEvent("Teaching layout opened")
+	}
+	if gw.miniMode {
+		gw.toggleMiniMode() // The demonstration board needs the full layout
+		return
+	}
+	gw.rebuildUI()
+}
+
+// boardArea returns the game board, with the demonstration board beside it
+// in the teaching layout
+func (gw *GameWindow) boardArea() fyne.CanvasObject {
+	if gw.teaching == nil || gw.miniMode {
+		return gw.boardContainer
+	}
+	size := fyne.NewSize(gw.geom.total(), gw.geom.total())
+	spacer := canvas.NewRectangle(color.Transparent)
+	spacer.SetMinSize(size)
+	return container.NewHBox(container.NewStack(spacer, gw.boardContainer), widget.NewSeparator(), gw.newDemoPanel())
+}
+
+// newDemoPanel draws the demonstration board with its tools below it
+func (gw *GameWindow) newDemoPanel() fyne.CanvasObject {
+	d := gw.teaching
+	g := gw.geom
+	board := container.NewWithoutLayout()
+	background := canvas.NewRectangle(woodColor)
+	background.Resize(fyne.NewSize(g.total(), g.total()))
+	board.Add(background)
+	for i := 0; i < game.BoardSize; i++ {
+		hLine := canvas.NewLine(color.Black)
+		hLine.Position1 = fyne.NewPos(g.padding, g.coord(i))
+		hLine.Position2 = fyne.NewPos(g.padding+g.span(), g.coord(i))
+		vLine := canvas.NewLine(color.Black)
+		vLine.Position1 = fyne.NewPos(g.coord(i), g.padding)
+		vLine.Position2 = fyne.NewPos(g.coord(i), g.padding+g.span())
+		board.Add(hLine)
+		board.Add(vLine)
+	}
+
+	d.stones = make([][]*canvas.Circle, game.BoardSize)
+	for i := 0; i < game.BoardSize; i++ {
+		d.stones[i] = make([]*canvas.Circle, game.BoardSize)
+		for j := 0; j < game.BoardSize; j++ {
+			stone := canvas.NewCircle(color.Transparent)
+			stone.Resize(fyne.NewSize(g.stone, g.stone))
+			stone.Move(fyne.NewPos(g.coord(j)-g.stone/2, g.coord(i)-g.stone/2))
+			d.stones[i][j] = stone
+			board.Add(stone)
+
+			clickArea := NewClickArea(func(row, col int) func() {
+				return func() {
+					d.click(row, col)
+					d.refresh()
+				}
+			}(i, j))
+			clickSize := g.cell * 0.5
+			clickArea.Resize(fyne.NewSize(clickSize, clickSize))
+			clickArea.Move(fyne.NewPos(g.coord(j)-clickSize/2, g.coord(i)-clickSize/2))
+			board.Add(clickArea)
+		}
+	}
+	spacer := canvas.NewRectangle(color.Transparent)
+	spacer.SetMinSize(fyne.NewSize(g.total(), g.total()))
+
+	d.status = widget.NewLabel("")
+	copyButton := widget.NewButton("Copy Game", func() {
+		d.load(gw.board.Grid, gw.board.GetCurrentPlayer())
+		d.refresh()
+	})
+	undoButton := widget.NewButton("Undo", func() {
+		d.undo()
+		d.refresh()
+	})
+	clearButton := widget.NewButton("Clear", func() {
+		d.load([game.BoardSize][game.BoardSize]game.Player{}, game.Black)
+		d.refresh()
+	})
+	d.refresh()
+
+	tools := container.NewHBox(d.status, copyButton, undoButton, clearButton)
+	return container.NewBorder(nil, tools, nil, nil, container.NewStack(spacer, board))
+}
@@ -0,0 +1,99 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+)
+
+const (
+	toastDuration = 2500 * time.Millisecond
+	toastFade     = 300 * time.Millisecond
+	toastPadding  = 8
+	toastMargin   = 6
+	toastLimit    = 3 // Older toasts are dropped beyond this many
+)
+
+var toastBackground = color.NRGBA{R: 30, G: 30, B: 30, A: 200}
+
+// toastStack holds the toasts currently shown over the board, newest last
+type toastStack struct {
+	mu    sync.Mutex
+	items []*fyne.Container
+}
+
+// showToast shows a short message over the bottom of the board that goes away
+// by itself, for events that should not interrupt play with a dialog
+func (gw *GameWindow) showToast(format string, args ...any) {
+	text := canvas.NewText(fmt.Sprintf(format, args...), color.White)
+	text.TextSize = theme.TextSize()
+	background := canvas.NewRectangle(toastBackground)
+	background.CornerRadius = 6
+
+	size := text.MinSize().AddWidthHeight(2*toastPadding, toastPadding)
+	background.Resize(size)
+	text.Move(fyne.NewPos(toastPadding, toastPadding/2))
+	toast := container.NewWithoutLayout(background, text)
+	toast.Resize(size)
+
+	gw.toasts.mu.Lock()
+	if len(gw.toasts.items) >= toastLimit {
+		gw.boardContainer.Remove(gw.toasts.items[0])
+		gw.toasts.items = gw.toasts.items[1:]
+	}
+	gw.toasts.items = append(gw.toasts.items, toast)
+	gw.boardContainer.Add(toast)
+	gw.layoutToasts()
+	gw.toasts.mu.Unlock()
+
+	time.AfterFunc(toastDuration, func() {
+		if reducedMotion() {
+			gw.removeToast(toast)
+			return
+		}
+		fade := fyne.NewAnimation(toastFade, func(progress float32) {
+			alpha := 1 - progress
+			background.FillColor = color.NRGBA{
+				R: toastBackground.R, G: toastBackground.G, B: toastBackground.B,
+				A: uint8(float32(toastBackground.A) * alpha),
+			}
+			text.Color = color.NRGBA{R: 255, G: 255, B: 255, A: uint8(255 * alpha)}
+			toast.Refresh()
+		})
+		fade.Start()
+		time.AfterFunc(toastFade, func() {
+			gw.removeToast(toast)
+		})
+	})
+}
+
+func (gw *GameWindow) removeToast(toast *fyne.Container) {
+	gw.toasts.mu.Lock()
+	defer gw.toasts.mu.Unlock()
+	for i, item := range gw.toasts.items {
+		if item == toast {
+			gw.toasts.items = append(gw.toasts.items[:i], gw.toasts.items[i+1:]...)
+			gw.boardContainer.Remove(toast)
+			gw.layoutToasts()
+			return
+		}
+	}
+}
+
+// layoutToasts stacks the toasts centered above the bottom edge of the board,
+// newest at the bottom. The caller must hold the toast lock.
+func (gw *GameWindow) layoutToasts() {
+	y := gw.geom.total() - toastMargin
+	for i := len(gw.toasts.items) - 1; i >= 0; i-- {
+		toast := gw.toasts.items[i]
+		y -= toast.Size().Height
+		toast.Move(fyne.NewPos((gw.geom.total()-toast.Size().Width)/2, y))
+		y -= toastMargin
+	}
+}
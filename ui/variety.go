@@ -0,0 +1,46 @@
+package ui
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+const varietyKey = "engine.variety" // Opening variety of the built-in engines, in percent
+
+// openingVariety returns how far the built-in engines stray from their best
+// opening moves, from 0 to 1. Rated games are played without variety, at
+// the strength the engines' ratings were measured at, and so are drills,
+// which follow set lines.
+func (gw *GameWindow) openingVariety() float64 {
+	if gw.rated() || gw.drill != nil {
+		return 0
+	}
+	return float64(fyne.CurrentApp().Preferences().Int(profileKey(varietyKey))) / 100
+}
+
+// newVarietySlider creates the opening variety choice of the game settings
+// dialog
+func newVarietySlider() fyne.CanvasObject {
+	prefs := fyne.CurrentApp().Preferences()
+	label := widget.NewLabel("")
+	showValue := func(percent int) {
+		if percent == 0 {
+			label.SetText("Off, always the best move")
+			return
+		}
+		label.SetText(fmt.Sprintf("%d%%", percent))
+	}
+
+	slider := widget.NewSlider(0, 100)
+	slider.Step = 10
+	slider.SetValue(float64(prefs.Int(profileKey(varietyKey))))
+	slider.OnChanged = func(value float64) {
+		prefs.SetInt(profileKey(varietyKey), int(value))
+		showValue(int(value))
+	}
+	showValue(int(slider.Value))
+	return container.NewBorder(nil, nil, nil, label, slider)
+}
@@ -1,25 +1,61 @@
 package ui
 
 import (
+	"context"
 	"fmt"
 	"image/color"
-	"os/exec"
-	"runtime"
+	"slices"
+	"strings"
 	"time"
 
 	"simple-gomoku/game"
+	"simple-gomoku/pbrain"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/canvas"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
 )
 
-// Click area widget, only handles click events
+// boardGeometry holds the pixel sizes the board is drawn with
+type boardGeometry struct {
+	cell    float32 // Cell size
+	padding float32 // Add padding to ensure complete board display
+	stone   float32 // Stone size
+	marker  float32 // Last move marker size
+}
+
+var (
+	fullGeometry = boardGeometry{cell: 40, padding: 30, stone: 32, marker: 10}
+	miniGeometry = boardGeometry{cell: 22, padding: 14, stone: 18, marker: 6}
+)
+
+// eloOptions are the rating-limited engines offered in the difficulty dialog
+var eloOptions = []int{1200, 1600, 2000}
+
+// span returns the actual board size (distance between the outer lines)
+func (g boardGeometry) span() float32 {
+	return float32(game.BoardSize-1) * g.cell
+}
+
+// coord returns the pixel offset of the grid line with the given index
+func (g boardGeometry) coord(index int) float32 {
+	return g.padding + float32(index)*g.cell
+}
+
+// total returns the total board size including padding
+func (g boardGeometry) total() float32 {
+	return g.span() + g.padding*2
+}
+
+// Click area widget, only handles click and hover events
 type ClickArea struct {
 	widget.BaseWidget
 	onTapped func()
+	onHover  func(in bool) // Called as the pointer enters and leaves, if set
 }
 
 func NewClickArea(onTapped func()) *ClickArea {
@@ -40,6 +76,20 @@ func (c *ClickArea) Tapped(_ *fyne.PointEvent) {
 	}
 }
 
+func (c *ClickArea) MouseIn(_ *desktop.MouseEvent) {
+	if c.onHover != nil {
+		c.onHover(true)
+	}
+}
+
+func (c *ClickArea) MouseMoved(_ *desktop.MouseEvent) {}
+
+func (c *ClickArea) MouseOut() {
+	if c.onHover != nil {
+		c.onHover(false)
+	}
+}
+
 type GameWindow struct {
 	window         fyne.Window
 	board          *game.Board
@@ -49,29 +99,115 @@ type GameWindow struct {
 	statusLabel    *widget.Label
 	isProcessing   bool
 	boardContainer *fyne.Container
-	lastMoveMarker *fyne.Container // Last move marker
+	geom           boardGeometry        // Pixel sizes of the board
+	miniMode       bool                 // Compact layout with just the board
+	fullSize       fyne.Size            // Window size to restore when leaving mini mode
+	lastMoveMarker *fyne.Container      // Last move marker
+	columnMark     *canvas.Rectangle    // Column a stone would drop down under gravity, shown on hover
+	hintMarker     *canvas.Circle       // Suggested move marker
+	assist         [4]bool              // Players shown the engine's top moves in casual games, by game.Player
+	assistMarks    []fyne.CanvasObject  // Engine assist markers, best move first
+	obstacleMarks  *fyne.Container      // Blocked points drawn over the board, nil without any
+	teaching       *demoBoard           // Demonstration board of the teaching layout, nil otherwise
+	kiosk          *kiosk               // Tournament mode lockdown, nil otherwise
+	gridLines      []*canvas.Line       // Grid lines, recolored to match the background
+	markerColor    color.Color          // Last move marker color
+	displayPolicy  displayPolicy        // How stones are drawn
+	revealed       bool                 // Show real colors after a one-color game
+	revealCheck    *widget.Check        // Reveal toggle shown in one-color mode
+	difficultyName string               // Difficulty chosen in the new game dialog
+	rules          game.Rules           // Rules chosen in the new game dialog, see newBoard
+	adaptive       bool                 // Engine strength follows the player's results
+	hotSeat        bool                 // Two people take turns at the same board
+	passButton     *widget.Button       // Only shown in hot-seat games
+	ladderLevel    int                  // Current ladder level, -1 outside ladder mode
+	gauntlet       *gauntlet            // Active gauntlet run, nil otherwise
+	drill          *game.Drill          // Active opening drill, nil otherwise
+	profileSelect  *widget.Select       // Profile switcher in the header, nil in mini mode
+	analysis       *pbrain.Engine       // External engine answering hints, nil if not started
+	analysisPath   string               // Path analysis was started from
+	engine         *pbrain.EnginePlayer // External engine playing White, nil otherwise
+	learnedBook    *game.Book           // Openings learned from the profile's games, nil unless learning
+	setup          *boardSetup          // Position being edited in setup mode, nil otherwise
+	setupBar       *fyne.Container      // Setup mode tools, shown above the board while editing
+	setupToMove    *widget.RadioGroup   // Side to move choice in the setup tools
+	position       setupPosition        // Last position set up, and the board it is played on
+	handicap       *handicapSetup       // Handicap stones being placed before a game, nil otherwise
+	handicapBar    *fyne.Container      // Handicap tools, shown above the board while placing stones
+	comments       commentary           // The engine's speech bubble over the board
+	sessionLog     sessionLog           // Events of this session, kept across games
+	toasts         toastStack           // Notifications shown over the board
+	clock          moveClock            // Time spent on each move of the current game
+	shortcuts      []fyne.Shortcut      // Keyboard shortcuts registered with the canvas
+	cancelAI       context.CancelFunc
 }
 
-func NewGameWindow(window fyne.Window) *GameWindow {
+// NewGameWindow sets up the game in window. A kioskPIN other than "" starts
+// it in tournament mode, left by entering the PIN.
+func NewGameWindow(window fyne.Window, kioskPIN string) (*GameWindow, error) {
+	migratePreferences()
+
 	gw := &GameWindow{
-		window: window,
-		board:  game.NewBoard(),
-		ai:     game.NewAI(game.White, game.Easy), // Create a default AI
+		window:         window,
+		board:          game.NewBoard(),
+		ai:             game.NewAI(game.White, game.Easy), // Create a default AI
+		ladderLevel:    -1,
+		geom:           fullGeometry,
+		difficultyName: "Easy", // Default to Easy difficulty
+		rules:          savedRules(),
+	}
+	if kioskPIN != "" {
+		kiosk, err := newKiosk(kioskPIN)
+		if err != nil {
+			return nil, err
+		}
+		gw.kiosk = kiosk
 	}
+	gw.applyOpeningBook()
 
 	// Initialize UI first to ensure board rendering
 	gw.initializeUI()
+	gw.window.SetMainMenu(gw.mainMenu())
+	gw.installShortcuts()
+	gw.window.SetOnClosed(func() {
+		gw.stopAI()
+		gw.closeEngine()
+		gw.closeAnalysisEngine()
+	})
+	if gw.kiosk != nil {
+		gw.lockKiosk()
+	}
 
 	// Ensure UI is fully rendered
 	gw.window.Canvas().Content().Refresh()
 
 	// Then show difficulty selection dialog
 	gw.showDifficultyDialog()
-	return gw
+	gw.promptDueReviews()
+	return gw, nil
 }
 
 func (gw *GameWindow) showDifficultyDialog() {
-	difficultySelect := widget.NewSelect([]string{"Easy", "Medium", "Hard"}, func(selected string) {
+	options := []string{"Easy", "Medium", "Hard", "Expert", "Master", "Monte Carlo"}
+	for _, elo := range eloOptions {
+		options = append(options, fmt.Sprintf("Elo %d", elo))
+	}
+	options = append(options, "Adaptive", "Two Players")
+	if gw.kiosk == nil {
+		options = append(options, customEngineOption) // Runs any program picked
+	}
+
+	engineLabel := widget.NewLabel("")
+	engineButton := widget.NewButton("Choose Engine...", func() {
+		gw.chooseCustomEngine(func() {
+			engineLabel.SetText(gw.customEngineText())
+			gw.logEvent("New game against %s", gw.engine.Name())
+		})
+	})
+	engineRow := container.NewHBox(engineLabel, engineButton)
+	assistChecks, refreshAssistChecks := gw.newAssistChecks()
+
+	difficultySelect := widget.NewSelect(options, func(selected string) {
 		var difficulty game.Difficulty
 		switch selected {
 		case "Easy":
@@ -80,18 +216,183 @@ func (gw *GameWindow) showDifficultyDialog() {
 			difficulty = game.Medium
 		case "Hard":
 			difficulty = game.Hard
+		case "Expert":
+			difficulty = game.Expert
+		case "Master":
+			difficulty = game.Master
+		case "Monte Carlo":
+			difficulty = game.MonteCarlo
 		default:
 			difficulty = game.Easy
 		}
+		gw.stopAI()
 		gw.ai = game.NewAI(game.White, difficulty)
-		gw.board = game.NewBoard() // Reset board
-		gw.updateBoard()           // Update UI
+		gw.adaptive = selected == "Adaptive"
+		if gw.adaptive {
+			gw.ai = adaptiveAI()
+		}
+		var elo int
+		if _, err := fmt.Sscanf(selected, "Elo %d", &elo); err == nil {
+			gw.ai = game.NewEloAI(game.White, elo)
+		}
+		gw.hotSeat = selected == "Two Players"
+		if gw.hotSeat {
+			gw.ai = game.NewAI(game.White, game.Hard) // Only used for hints
+		}
+		opponentName := selected
+		engineRow.Hide()
+		if selected == customEngineOption {
+			gw.ai = game.NewAI(game.White, game.Hard) // Used for hints, and moves until an engine runs
+			if gw.engine == nil {
+				path := fyne.CurrentApp().Preferences().String(profileKey(opponentEngineKey))
+				if path == "" || gw.startCustomEngine(path) != nil {
+					engineButton.OnTapped()
+				}
+			}
+			if gw.engine != nil {
+				opponentName = gw.engine.Name()
+			}
+			engineLabel.SetText(gw.customEngineText())
+			engineRow.Show()
+		} else {
+			gw.closeEngine()
+		}
+		gw.difficultyName = selected
+		gw.ladderLevel = -1
+		gw.gauntlet = nil
+		gw.drill = nil
+		gw.board = gw.newBoard() // Reset board
+		gw.updateBoard()         // Update UI
+		gw.updateStatus()
+		refreshAssistChecks()
+		gw.logEvent("New game against %s", opponentName)
+	})
+	difficultySelect.SetSelected(gw.difficultyName) // Keep the previous choice
+
+	var exactCheck *widget.Check
+	setRules := func(rules game.Rules) {
+		if rules == gw.rules {
+			return
+		}
+		gw.rules = rules
+		prefs := fyne.CurrentApp().Preferences()
+		prefs.SetInt(profileKey(winLengthKey), rules.WinLength)
+		prefs.SetBool(profileKey(exactKey), rules.Exact)
+		prefs.SetInt(profileKey(openingKey), int(rules.Opening))
+		prefs.SetBool(profileKey(gravityKey), rules.Gravity)
+		prefs.SetBool(profileKey(threePlayersKey), rules.ThreePlayers)
+		gw.stopAI()
+		gw.board = gw.newBoard()
+		gw.updateBoard()
+		gw.updateStatus()
+		refreshAssistChecks()
+		gw.logEvent("Playing %s", gw.board.Rules())
+	}
+	refreshExactCheck := func() {
+		if gw.rules.WinLength > game.MaxExactLength {
+			exactCheck.Disable()
+		} else {
+			exactCheck.Enable()
+		}
+	}
+	exactCheck = widget.NewCheck("Exact length (longer rows do not win)", func(checked bool) {
+		rules := gw.rules
+		rules.Exact = checked
+		setRules(rules)
+	})
+	exactCheck.SetChecked(gw.rules.Exact)
+
+	winLengthSelect := widget.NewSelect(winLengthOptions(), func(selected string) {
+		rules := gw.rules
+		rules.WinLength = rulesFromOption(selected).WinLength
+		if rules.WinLength > game.MaxExactLength {
+			rules.Exact = false
+		}
+		setRules(rules)
+		exactCheck.SetChecked(gw.rules.Exact)
+		refreshExactCheck()
+	})
+	winLengthSelect.SetSelected(ruleOption(game.Rules{WinLength: gw.rules.WinLength}))
+	refreshExactCheck()
+
+	var gravityCheck, threePlayersCheck *widget.Check
+	openingSelect := widget.NewSelect(openingOptions, func(selected string) {
+		rules := gw.rules
+		rules.Opening = openingFromOption(selected)
+		if rules.ThirdMoveDistance() > 0 {
+			rules.Gravity = false // Pro openings start in the center, out of reach of dropped stones
+		}
+		if rules.Opening != game.FreeOpening {
+			rules.ThreePlayers = false // Opening rules are for two players
+		}
+		setRules(rules)
+		if gravityCheck != nil {
+			gravityCheck.SetChecked(gw.rules.Gravity)
+		}
+		if threePlayersCheck != nil {
+			threePlayersCheck.SetChecked(gw.rules.ThreePlayers)
+		}
+	})
+	openingSelect.SetSelected(openingOptions[gw.rules.Opening])
+
+	gravityCheck = widget.NewCheck("Gravity (stones drop to the bottom of their column)", func(checked bool) {
+		rules := gw.rules
+		rules.Gravity = checked
+		if checked && rules.ThirdMoveDistance() > 0 {
+			rules.Opening = game.FreeOpening
+		}
+		setRules(rules)
+		openingSelect.SetSelected(openingOptions[gw.rules.Opening])
 	})
-	difficultySelect.SetSelected("Easy") // Default to Easy difficulty
+	gravityCheck.SetChecked(gw.rules.Gravity)
+
+	threePlayersCheck = widget.NewCheck("Three players (Red moves after White)", func(checked bool) {
+		rules := gw.rules
+		rules.ThreePlayers = checked
+		if checked {
+			rules.Opening = game.FreeOpening
+		}
+		setRules(rules)
+		openingSelect.SetSelected(openingOptions[gw.rules.Opening])
+	})
+	threePlayersCheck.SetChecked(gw.rules.ThreePlayers)
+
+	oneColorCheck := widget.NewCheck("One-color training (all stones look alike)", func(checked bool) {
+		gw.displayPolicy = displayNormal
+		if checked {
+			gw.displayPolicy = displayOneColor
+		}
+		gw.updateBoard()
+		gw.updateStatus()
+	})
+	oneColorCheck.SetChecked(gw.displayPolicy == displayOneColor)
+
+	handicap := fyne.CurrentApp().Preferences().Int(profileKey(handicapKey))
+	handicapSelect := widget.NewSelect(handicapOptions(), func(selected string) {
+		handicap = slices.Index(handicapOptions(), selected)
+		fyne.CurrentApp().Preferences().SetInt(profileKey(handicapKey), handicap)
+	})
+	handicapSelect.SetSelected(handicapOptions()[max(0, min(handicap, game.MaxHandicap))])
 
 	content := container.NewVBox(
 		widget.NewLabel("Select AI Difficulty:"),
 		difficultySelect,
+		engineRow,
+		widget.NewLabel("Win Length:"),
+		winLengthSelect,
+		exactCheck,
+		widget.NewLabel("Opening:"),
+		openingSelect,
+		gravityCheck,
+		threePlayersCheck,
+		widget.NewLabel("Blocked Points (a random layout each game):"),
+		gw.newObstacleSelect(),
+		widget.NewLabel("Handicap (Black's stones before White's first move):"),
+		handicapSelect,
+		widget.NewLabel(fmt.Sprintf("Opening Variety (engine strays from its best move for the first %d moves):", game.VarietyPlies)),
+		newVarietySlider(),
+		oneColorCheck,
+		assistChecks,
 	)
 
 	dialog := dialog.NewCustom(
@@ -100,47 +401,40 @@ func (gw *GameWindow) showDifficultyDialog() {
 		content,
 		gw.window,
 	)
+	dialog.SetOnClosed(func() { gw.startHandicap(handicap) })
 
 	dialog.Show()
 }
 
 func (gw *GameWindow) initializeUI() {
-	const (
-		cellSize  = float32(40) // Cell size
-		padding   = float32(30) // Add padding to ensure complete board display
-		stoneSize = float32(32) // Stone size
-	)
-
-	boardSize := float32(game.BoardSize-1) * cellSize // Actual board size (distance between lines)
-	totalSize := boardSize + padding*2                // Total size (including padding)
-
 	// Initialize storage
 	gw.stones = make([][]*canvas.Circle, game.BoardSize)
 	gw.clickAreas = make([][]*ClickArea, game.BoardSize)
 	gw.boardContainer = container.NewWithoutLayout()
 
-	// 1. Create background
-	background := canvas.NewRectangle(color.RGBA{R: 255, G: 223, B: 176, A: 255})
-	background.Resize(fyne.NewSize(totalSize, totalSize))
-	background.Move(fyne.NewPos(0, 0))
-	gw.boardContainer.Add(background)
+	// 1. Create background placeholder, filled in by applyBackground
+	gw.boardContainer.Add(canvas.NewRectangle(color.Transparent))
 
 	// 2. Create grid lines
 	for i := 0; i < game.BoardSize; i++ {
 		// Horizontal line
 		hLine := canvas.NewLine(color.Black)
 		hLine.StrokeWidth = 1
-		hLine.Move(fyne.NewPos(padding, padding+float32(i)*cellSize))
-		hLine.Resize(fyne.NewSize(boardSize, 1))
+		hLine.Move(fyne.NewPos(gw.geom.padding, gw.geom.coord(i)))
+		hLine.Resize(fyne.NewSize(gw.geom.span(), 1))
 		gw.boardContainer.Add(hLine)
 
 		// Vertical line
 		vLine := canvas.NewLine(color.Black)
 		vLine.StrokeWidth = 1
-		vLine.Move(fyne.NewPos(padding+float32(i)*cellSize, padding))
-		vLine.Resize(fyne.NewSize(1, boardSize))
+		vLine.Move(fyne.NewPos(gw.geom.coord(i), gw.geom.padding))
+		vLine.Resize(fyne.NewSize(1, gw.geom.span()))
 		gw.boardContainer.Add(vLine)
+
+		gw.gridLines = append(gw.gridLines, hLine, vLine)
 	}
+	gw.boardContainer.Add(gw.newColumnHighlight())
+	gw.applyBackground(loadBackground())
 
 	// 3. Create stones and click areas
 	for i := 0; i < game.BoardSize; i++ {
@@ -150,10 +444,10 @@ func (gw *GameWindow) initializeUI() {
 		for j := 0; j < game.BoardSize; j++ {
 			// Create stone (initially transparent)
 			stone := canvas.NewCircle(color.Transparent)
-			stone.Resize(fyne.NewSize(stoneSize, stoneSize))
+			stone.Resize(fyne.NewSize(gw.geom.stone, gw.geom.stone))
 			stone.Move(fyne.NewPos(
-				padding+float32(j)*cellSize-stoneSize/2,
-				padding+float32(i)*cellSize-stoneSize/2,
+				gw.geom.coord(j)-gw.geom.stone/2,
+				gw.geom.coord(i)-gw.geom.stone/2,
 			))
 			gw.stones[i][j] = stone
 			gw.boardContainer.Add(stone)
@@ -164,13 +458,14 @@ func (gw *GameWindow) initializeUI() {
 					gw.handleClick(row, col)
 				}
 			}(i, j))
+			clickArea.onHover = func(in bool) { gw.hoverColumn(j, in) }
 
 			// Set click area size to half of cell size to ensure clicks only near intersections
-			clickSize := cellSize * 0.5
+			clickSize := gw.geom.cell * 0.5
 			clickArea.Resize(fyne.NewSize(clickSize, clickSize))
 			clickArea.Move(fyne.NewPos(
-				padding+float32(j)*cellSize-clickSize/2,
-				padding+float32(i)*cellSize-clickSize/2,
+				gw.geom.coord(j)-clickSize/2,
+				gw.geom.coord(i)-clickSize/2,
 			))
 
 			gw.clickAreas[i][j] = clickArea
@@ -180,46 +475,156 @@ func (gw *GameWindow) initializeUI() {
 
 	// 4. Create control panel
 	gw.statusLabel = widget.NewLabel("Black's turn")
-	undoButton := widget.NewButton("Undo", func() {
-		if gw.isProcessing || gw.board.IsGameFinished() {
-			return
+	if gw.miniMode {
+		gw.passButton = widget.NewButtonWithIcon("", theme.MediaSkipNextIcon(), gw.passMove)
+		controls := container.NewHBox(
+			widget.NewButtonWithIcon("", theme.ContentUndoIcon(), gw.undoMove),
+			widget.NewButtonWithIcon("", theme.ContentRedoIcon(), gw.redoMove),
+			widget.NewButtonWithIcon("", theme.HelpIcon(), gw.showHint),
+			gw.passButton,
+			widget.NewButtonWithIcon("", theme.ViewFullScreenIcon(), gw.toggleMiniMode),
+			gw.statusLabel,
+		)
+		if badge := gw.kioskBadge(); badge != nil {
+			controls.Add(badge)
 		}
-		gw.isProcessing = true
-		if err := gw.board.Undo(); err == nil {
-			if gw.board.GetCurrentPlayer() == game.White {
-				gw.board.Undo()
-			}
-			gw.updateBoard()
-			gw.updateStatus()
-		}
-		gw.isProcessing = false
+		gw.revealCheck = widget.NewCheck("", nil)
+		gw.revealCheck.Hide()
+		gw.profileSelect = nil
+
+		gw.window.SetContent(container.NewBorder(nil, controls, nil, nil, gw.boardContainer))
+		gw.window.Resize(fyne.NewSize(gw.geom.total(), gw.geom.total()+40))
+		return
+	}
+
+	undoButton := widget.NewButton("Undo", gw.undoMove)
+	redoButton := widget.NewButton("Redo", gw.redoMove)
+	gw.passButton = widget.NewButton("Pass", gw.passMove)
+
+	newGameButton := widget.NewButton("New Game", gw.newGame)
+
+	ladderButton := widget.NewButton("Ladder", func() {
+		gw.showLadderDialog()
 	})
 
-	newGameButton := widget.NewButton("New Game", func() {
-		gw.board = game.NewBoard()
-		gw.showDifficultyDialog()
+	hintButton := widget.NewButton("Hint", func() {
+		gw.showHint()
 	})
 
-	controls := container.NewHBox(gw.statusLabel, undoButton, newGameButton)
-	mainContainer := container.NewBorder(nil, controls, nil, nil, gw.boardContainer)
+	gauntletButton := widget.NewButton("Gauntlet", func() {
+		gw.startGauntlet()
+	})
+
+	settingsButton := widget.NewButton("Settings", func() {
+		gw.showSettingsDialog()
+	})
+
+	miniButton := widget.NewButtonWithIcon("", theme.ViewRestoreIcon(), gw.toggleMiniMode)
+	if gw.kiosk != nil {
+		for _, button := range []*widget.Button{undoButton, redoButton, hintButton, settingsButton} {
+			button.Disable()
+		}
+	}
+
+	gw.revealCheck = widget.NewCheck("Reveal", func(checked bool) {
+		gw.revealed = checked
+		gw.updateBoard()
+	})
+	gw.revealCheck.Hide()
+
+	controls := container.NewHBox(gw.statusLabel, undoButton, redoButton, hintButton, gw.passButton, newGameButton, ladderButton, gauntletButton, settingsButton, miniButton, gw.revealCheck)
+	mainContainer := container.NewBorder(container.NewVBox(container.NewBorder(nil, nil, gw.newProfileSelect(), gw.kioskBadge()), gw.newSetupBar(), gw.newHandicapBar()), container.NewVBox(widget.NewAccordion(gw.moveListItem(), gw.sessionLogItem()), controls), nil, nil, gw.boardArea())
 
 	// 5. Set window content and size
 	gw.window.SetContent(mainContainer)
-	gw.window.Resize(fyne.NewSize(totalSize, totalSize+50))
+	gw.window.Resize(mainContainer.MinSize().Max(fyne.NewSize(gw.geom.total(), gw.geom.total()+130)))
+}
+
+func (gw *GameWindow) newGame() {
+	gw.stopAI()
+	gw.board = gw.newBoard()
+	gw.showDifficultyDialog()
 }
 
-func playSystemSound() {
-	switch runtime.GOOS {
-	case "darwin":
-		exec.Command("afplay", "/System/Library/Sounds/Tink.aiff").Run()
-	case "linux":
-		exec.Command("paplay", "/usr/share/sounds/freedesktop/stereo/bell.oga").Run()
-	case "windows":
-		exec.Command("powershell", "[console]::beep(2000,100)").Run()
+func (gw *GameWindow) undoMove() {
+	if gw.isProcessing || gw.setup != nil || gw.board.IsGameFinished() || len(gw.board.MoveHistory) == 0 {
+		return
+	}
+	if plies := gw.setupPlies(); plies > 0 && len(gw.board.MoveHistory)-plies < 2 {
+		// Undo takes back a move of each side and stops at the position
+		gw.showToast("Undo stops at the set-up position")
+		return
 	}
+	if len(gw.board.Handicap()) > 0 && !gw.hotSeat && len(gw.board.MoveHistory) < 2 {
+		gw.showToast("Undo stops at the handicap stones") // White's first move goes with them
+		return
+	}
+	if gw.drill != nil {
+		gw.showToast("Undo is not available in opening drills")
+		return
+	}
+	if clubForbids(clubNoUndoKey) {
+		gw.showToast("Undo is off under the %s club settings", clubName())
+		return
+	}
+	if gw.locked("undo") {
+		return
+	}
+	if !gw.useGauntletToken() {
+		return
+	}
+	gw.isProcessing = true
+	if err := gw.board.Undo(); err == nil {
+		for !gw.hotSeat && gw.board.GetCurrentPlayer() != game.Black && gw.board.CanUndo() {
+			gw.board.Undo()
+		}
+		gw.logEvent("Undo")
+		gw.updateBoard()
+		gw.updateStatus()
+	}
+	gw.isProcessing = false
+}
+
+// redoMove replays moves taken back by undo, the opponents' replies with the
+// player's move outside hot-seat games
+func (gw *GameWindow) redoMove() {
+	if gw.isProcessing || gw.setup != nil || !gw.board.CanRedo() || gw.locked("redo") {
+		return
+	}
+	if gw.drill != nil {
+		gw.showToast("Redo is not available in opening drills")
+		return
+	}
+	gw.isProcessing = true
+	if err := gw.board.Redo(); err == nil {
+		for !gw.hotSeat && gw.board.GetCurrentPlayer() != game.Black && gw.board.CanRedo() {
+			gw.board.Redo()
+		}
+		gw.logEvent("Redo")
+		gw.updateBoard()
+		gw.updateStatus()
+		if last, ok := gw.board.LastMove(); ok {
+			gw.updateLastMoveMarker(last.Row, last.Col)
+		}
+		if gw.board.IsGameFinished() {
+			gw.showGameOver(gw.winnerText())
+		} else if !gw.hotSeat && gw.board.GetCurrentPlayer() != game.Black {
+			gw.playAITurn() // Nothing left to redo for the engine
+			return
+		}
+	}
+	gw.isProcessing = false
 }
 
 func (gw *GameWindow) handleClick(row, col int) {
+	if gw.setup != nil {
+		gw.setupClick(row, col)
+		return
+	}
+	if gw.placingHandicap() != nil {
+		gw.handicapClick(row, col)
+		return
+	}
 	if gw.isProcessing || gw.board.IsGameFinished() {
 		return
 	}
@@ -230,90 +635,277 @@ func (gw *GameWindow) handleClick(row, col int) {
 		return
 	}
 
-	if gw.board.GetCurrentPlayer() != game.Black {
+	player := gw.board.GetCurrentPlayer()
+	if player != game.Black && !gw.hotSeat {
+		gw.isProcessing = false
+		return
+	}
+
+	if gw.board.Rules().Gravity {
+		// A click anywhere in a column drops the stone down it
+		if row = gw.board.DropRow(col); row < 0 {
+			gw.showToast("That column is full")
+			gw.isProcessing = false
+			return
+		}
+		gw.hoverColumn(col, false)
+	}
+
+	if gw.board.Grid[row][col] == game.Blocked {
+		gw.showToast("That point is blocked")
 		gw.isProcessing = false
 		return
 	}
 
-	if err := gw.board.PlaceStone(row, col); err == nil {
+	if gw.drill != nil && gw.board.Grid[row][col] == game.Empty {
+		gw.gradeDrillMove(row, col)
+	}
+	err := gw.board.PlaceStone(row, col)
+	if err == nil {
+		gw.clearHint()
+
 		// Human player stone animation
-		stone := gw.stones[row][col]
-		stone.FillColor = color.Black
-		stone.Refresh()
+		frame.paint(gw.stones[row][col], gw.activePolicy().stoneColor(player))
 		gw.updateLastMoveMarker(row, col)
 		gw.updateStatus()
+		gw.recordHumanMove()
+		gw.logEvent("%s plays %s", gw.getPlayerText(player), game.FormatMove(row, col))
 
-		// Play system sound in background after a tiny delay to ensure UI update
-		go func() {
-			time.Sleep(10 * time.Millisecond)
-			playSystemSound()
-		}()
+		sounds.play(soundStone)
 
 		if gw.board.IsGameFinished() {
-			gw.showGameOver("Black")
+			gw.showGameOver(gw.winnerText())
 			gw.isProcessing = false
 			return
 		}
+		if gw.hotSeat {
+			// The other player moves next at the same board
+			gw.isProcessing = false
+			if gw.board.CanSwap() {
+				gw.offerSwap()
+			}
+			return
+		}
+		if gw.drill != nil && gw.drill.Done() {
+			gw.finishDrill()
+			gw.isProcessing = false
+			return
+		}
+		gw.commentOnMove()
 
-		// AI's turn (with delay)
-		go func() {
-			time.Sleep(300 * time.Millisecond)
+		gw.playAITurn()
+	} else {
+		if gw.board.Grid[row][col] == game.Empty {
+			// An empty point the opening rule rules out
+			gw.showToast("%s", strings.ToUpper(err.Error()[:1])+err.Error()[1:])
+		}
+		gw.isProcessing = false
+	}
+}
 
-			aiRow, aiCol := gw.ai.MakeMove(gw.board)
-			if aiRow >= 0 && aiCol >= 0 {
-				// Update UI in main thread
-				gw.board.PlaceStone(aiRow, aiCol)
+// playAITurn lets the opponent answer after a short delay. The caller sets
+// isProcessing, which is cleared once the move is on the board. In
+// three-player games the engine plays White and Red in turn before the
+// player moves again.
+func (gw *GameWindow) playAITurn() {
+	ctx, cancel := context.WithCancel(context.Background())
+	gw.cancelAI = cancel
+	board, ai := gw.board, gw.opponent()
+	position := board.Clone() // The AI thinks on its own copy while the board is drawn
+	go func() {
+		defer cancel()
+		select {
+		case <-time.After(300 * time.Millisecond):
+		case <-ctx.Done():
+			return
+		}
+		if gw.engine == nil && gw.ai.ShouldSwap(position) {
+			if gw.board == board {
+				gw.swapFirstStone()
+				gw.isProcessing = false
+				gw.raiseForTurn()
+			}
+			return
+		}
 
-				// AI stone animation
-				stone := gw.stones[aiRow][aiCol]
-				stone.FillColor = color.White
-				stone.Refresh()
-				gw.updateLastMoveMarker(aiRow, aiCol)
-				gw.updateStatus()
+		mover := position.GetCurrentPlayer()
+		thinkStart := time.Now()
+		aiRow, aiCol, err := ai.MakeMoveCtx(ctx, position)
+		thinking := time.Since(thinkStart)
+		if err != nil || gw.board != board {
+			// The game was replaced while the AI was thinking
+			return
+		}
+		if aiRow >= 0 && aiCol >= 0 {
+			// Update UI in main thread
+			board.PlaceStone(aiRow, aiCol)
+			gw.recordMoveTime(board, thinking)
+
+			// AI stone animation
+			frame.paint(gw.stones[aiRow][aiCol], gw.activePolicy().stoneColor(mover))
+			gw.updateLastMoveMarker(aiRow, aiCol)
+			gw.updateStatus()
+			gw.logEvent("%s plays %s", gw.getPlayerText(mover), game.FormatMove(aiRow, aiCol))
 
-				// Play system sound in background after a tiny delay to ensure UI update
-				go func() {
-					time.Sleep(10 * time.Millisecond)
-					playSystemSound()
-				}()
+			sounds.play(soundStone)
 
-				if gw.board.IsGameFinished() {
-					gw.showGameOver("White")
-				}
+			if gw.board.IsGameFinished() {
+				gw.showGameOver(gw.winnerText())
+			} else if board.GetCurrentPlayer() != game.Black {
+				gw.playAITurn() // Red answers White
+				return
+			} else {
+				gw.commentOnMove()
 			}
-			gw.isProcessing = false
-		}()
-	} else {
+		}
 		gw.isProcessing = false
-	}
+		gw.raiseForTurn()
+	}()
 }
 
 func (gw *GameWindow) updateBoard() {
-	for i := 0; i < game.BoardSize; i++ {
-		for j := 0; j < game.BoardSize; j++ {
-			switch gw.board.Grid[i][j] {
-			case game.Black:
-				gw.stones[i][j].FillColor = color.Black
-			case game.White:
-				gw.stones[i][j].FillColor = color.White
-			default:
-				gw.stones[i][j].FillColor = color.Transparent
-			}
-			gw.stones[i][j].Refresh()
-		}
+	gw.clearHint()
+	gw.clearAssist()
+	gw.clearComment()
+	if gw.setup != nil {
+		gw.drawGrid(&gw.setup.grid, displayNormal)
+		return
+	}
+	gw.refreshHandicapBar()
+	if h := gw.placingHandicap(); h != nil {
+		gw.drawGrid(h.grid(), displayNormal)
+		return
 	}
+	gw.drawBoard(gw.board, gw.activePolicy())
 }
 
 func (gw *GameWindow) updateStatus() {
+	if gw.setup != nil {
+		gw.statusLabel.SetText(gw.setup.status())
+		return
+	}
+	if h := gw.placingHandicap(); h != nil {
+		gw.statusLabel.SetText(h.status())
+		return
+	}
+	status := fmt.Sprintf("%s's turn", gw.getPlayerText(gw.board.GetCurrentPlayer()))
 	if gw.board.IsGameFinished() {
-		gw.statusLabel.SetText("Game Over")
+		status = "Game Over"
+	}
+	if gw.gauntlet != nil {
+		status += fmt.Sprintf(" (tokens: %d)", gw.gauntlet.tokens)
+	}
+	if rules := gw.board.Rules(); !rules.Standard() {
+		status += fmt.Sprintf(" - %s", rules)
+	}
+	if n := len(gw.board.Obstacles()); n > 0 {
+		status += fmt.Sprintf(" - %d blocked points", n)
+	}
+	gw.statusLabel.SetText(status)
+	gw.updateRevealToggle()
+	gw.refreshMoveList()
+	gw.refreshAssist()
+	if gw.hotSeat {
+		gw.passButton.Show()
 	} else {
-		gw.statusLabel.SetText(fmt.Sprintf("%s's turn", gw.getPlayerText(gw.board.GetCurrentPlayer())))
+		gw.passButton.Hide()
 	}
 }
 
+// passMove passes the turn in a hot-seat game
+func (gw *GameWindow) passMove() {
+	if !gw.hotSeat || gw.isProcessing || gw.setup != nil || gw.board.IsGameFinished() {
+		return
+	}
+	player := gw.board.GetCurrentPlayer()
+	if err := gw.board.Pass(); err != nil {
+		return
+	}
+	gw.recordHumanMove()
+	gw.clearHint()
+	gw.updateLastMoveMarker(game.PassMove[0], game.PassMove[1])
+	gw.updateStatus()
+	gw.logEvent("%s passes", gw.getPlayerText(player))
+	if gw.board.IsDraw() {
+		gw.showGameOver("")
+	}
+}
+
+// showGameOver reports the result, winner is empty for a draw
 func (gw *GameWindow) showGameOver(winner string) {
-	content := widget.NewLabel(fmt.Sprintf("Game Over! %s wins!", winner))
+	switch winner {
+	case "Black":
+		gw.learnOpening(game.Black)
+	case "White":
+		gw.learnOpening(game.White)
+	default:
+		gw.learnOpening(game.Empty)
+	}
+	sounds.announce(soundGameOver)
+	if winner == "" {
+		reason := "Both players passed"
+		if gw.board.Rules().ThreePlayers {
+			reason = "All three players passed"
+		}
+		if gw.board.Result().Reason == game.ReasonFullBoard {
+			reason = "The board is full"
+		}
+		gw.logEvent("Game over, drawn (%s)", gw.board.Result().Reason)
+		rating := gw.recordRating(0.5)
+		if gw.gauntlet != nil {
+			if rating != "" {
+				gw.showToast("%s", rating)
+			}
+			gw.showGauntletResult(false) // Only wins carry a gauntlet on
+			return
+		}
+		message := reason + ", the game is drawn."
+		if rating != "" {
+			message += "\n" + rating
+		}
+		if progress := gw.recordLadderResult(false); progress != "" {
+			message += "\n" + progress
+		}
+		if progress := gw.recordAdaptiveResult(false); progress != "" {
+			message += "\n" + progress
+		}
+		dialog.ShowConfirm("Game Over", message+"\nStart a new game?", func(ok bool) {
+			if ok {
+				gw.newGame()
+			}
+		}, gw.window)
+		return
+	}
+	gw.logEvent("Game over, %s wins", winner)
+	gw.playGameOverEffect(winner == "Black" || gw.hotSeat)
+	score := 0.0
+	if winner == "Black" {
+		score = 1
+	}
+	rating := gw.recordRating(score)
+
+	if gw.gauntlet != nil {
+		if rating != "" {
+			gw.showToast("%s", rating)
+		}
+		gw.showGauntletResult(winner == "Black")
+		return
+	}
+
+	message := fmt.Sprintf("Game Over! %s wins!", winner)
+	if rating != "" {
+		message += "\n" + rating
+	}
+	if progress := gw.recordLadderResult(winner == "Black"); progress != "" {
+		message += "\n" + progress
+	}
+	if progress := gw.recordAdaptiveResult(winner == "Black"); progress != "" {
+		message += "\n" + progress
+	}
+	content := container.NewVBox(
+		widget.NewLabel(message),
+		widget.NewButton("Move Times...", gw.showTimeChart),
+	)
 	dialog := dialog.NewCustomConfirm(
 		"Game Over",
 		"New Game",
@@ -321,7 +913,8 @@ func (gw *GameWindow) showGameOver(winner string) {
 		content,
 		func(ok bool) {
 			if ok {
-				gw.board = game.NewBoard()
+				gw.stopAI()
+				gw.board = gw.newBoard()
 				gw.showDifficultyDialog()
 			}
 		},
@@ -330,44 +923,47 @@ func (gw *GameWindow) showGameOver(winner string) {
 	dialog.Show()
 }
 
-func (gw *GameWindow) getPlayerText(player game.Player) string {
-	if player == game.Black {
-		return "Black"
+// winnerText names the winner of the finished game, or is empty for a draw
+func (gw *GameWindow) winnerText() string {
+	if winner := gw.board.Result().Winner; winner != game.Empty {
+		return gw.getPlayerText(winner)
 	}
-	return "White"
+	return ""
+}
+
+func (gw *GameWindow) getPlayerText(player game.Player) string {
+	return player.String()
 }
 
 func (gw *GameWindow) updateLastMoveMarker(row, col int) {
 	if gw.lastMoveMarker != nil {
 		gw.boardContainer.Remove(gw.lastMoveMarker)
+		gw.lastMoveMarker = nil
+	}
+	if [2]int{row, col} == game.PassMove {
+		return
 	}
-
-	const (
-		cellSize   = float32(40) // Cell size
-		padding    = float32(30) // Padding
-		markerSize = float32(10) // Marker size
-	)
 
 	// Create marker container
 	markerContainer := container.NewWithoutLayout()
 
 	// Create horizontal marker line
-	hLine := canvas.NewLine(color.RGBA{R: 255, G: 0, B: 0, A: 255})
+	hLine := canvas.NewLine(gw.markerColor)
 	hLine.StrokeWidth = 2
-	hLine.Resize(fyne.NewSize(markerSize, 1))
+	hLine.Resize(fyne.NewSize(gw.geom.marker, 1))
 	hLine.Move(fyne.NewPos(
-		padding+float32(col)*cellSize-markerSize/2,
-		padding+float32(row)*cellSize,
+		gw.geom.coord(col)-gw.geom.marker/2,
+		gw.geom.coord(row),
 	))
 	markerContainer.Add(hLine)
 
 	// Create vertical marker line
-	vLine := canvas.NewLine(color.RGBA{R: 255, G: 0, B: 0, A: 255})
+	vLine := canvas.NewLine(gw.markerColor)
 	vLine.StrokeWidth = 2
-	vLine.Resize(fyne.NewSize(1, markerSize))
+	vLine.Resize(fyne.NewSize(1, gw.geom.marker))
 	vLine.Move(fyne.NewPos(
-		padding+float32(col)*cellSize,
-		padding+float32(row)*cellSize-markerSize/2,
+		gw.geom.coord(col),
+		gw.geom.coord(row)-gw.geom.marker/2,
 	))
 	markerContainer.Add(vLine)
 
@@ -376,6 +972,15 @@ func (gw *GameWindow) updateLastMoveMarker(row, col int) {
 	markerContainer.Refresh()
 }
 
+// stopAI cancels any move the AI is still thinking about
+func (gw *GameWindow) stopAI() {
+	if gw.cancelAI != nil {
+		gw.cancelAI()
+		gw.cancelAI = nil
+	}
+	gw.isProcessing = false
+}
+
 func (gw *GameWindow) Show() {
 	gw.window.Show()
 }